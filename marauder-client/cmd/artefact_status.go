@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// artefactStageBuilt indicates that an artefact was built and, if requested, signed.
+	artefactStageBuilt = "built"
+
+	// artefactStagePublished indicates that an artefact was published to the controller.
+	artefactStagePublished = "published"
+
+	// artefactStageDeployed indicates that an artefact was deployed to its targeted servers.
+	artefactStageDeployed = "deployed"
+)
+
+// The artefactStatus holds the progress of a single artefact processed together with other artefacts in one invocation.
+type artefactStatus struct {
+	// ManifestLocation is the location of the manifest the artefact is built from.
+	ManifestLocation string
+
+	// Identifier and Version identify the artefact, NAN if the manifest could not be parsed.
+	Identifier string
+	Version    string
+
+	// Stage is the last stage the artefact completed, empty if it failed to build.
+	Stage string
+
+	// Err holds the error that stopped the artefact from completing the next stage.
+	Err error
+}
+
+// printArtefactStatuses prints the progress of all passed artefacts.
+func printArtefactStatuses(cmd *cobra.Command, statuses []artefactStatus) {
+	for _, status := range statuses {
+		switch {
+		case status.Err != nil:
+			cmd.PrintErrln(bunt.Sprintf("Red{✗} *%s* Gray{%s (%s)} Red{%s}", status.Identifier, status.Version, status.ManifestLocation, status.Err))
+		default:
+			cmd.PrintErrln(bunt.Sprintf("LimeGreen{✓} *%s* Gray{%s (%s)} %s", status.Identifier, status.Version, status.ManifestLocation, status.Stage))
+		}
+	}
+}
+
+// artefactStatusErr joins the errors of all failed artefacts into a single error, nil if all artefacts succeeded.
+func artefactStatusErr(statuses []artefactStatus) error {
+	errs := make([]error, 0)
+	for _, status := range statuses {
+		if status.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", status.ManifestLocation, status.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gorilla/websocket"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// detachFirstKey is the first key of the detach sequence (ctrl-p).
+	detachFirstKey = 0x10
+
+	// detachSecondKey is the second key of the detach sequence (ctrl-q).
+	detachSecondKey = 0x11
+)
+
+// AttachCommand constructs the attach command, attaching the local terminal to the console of a server.
+func AttachCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "attach [reference]",
+		Short: "Attaches the local terminal to the console of the specified server",
+		Long: `Attaches the local terminal to the console of the specified server.
+All input, including ctrl-c, is forwarded to the server. Detach from the console using ctrl-p ctrl-q.`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		conn, err := client.AttachConsole(ctx, serverUUID)
+		if err != nil {
+			return fmt.Errorf("failed to attach to console of %s: %w", args[0], err)
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{attached to console of %s, detach using ctrl-p ctrl-q}", serverUUID))
+
+		console := &consoleConnection{conn: conn}
+
+		stdinFileDescriptor := int(os.Stdin.Fd())
+		if term.IsTerminal(stdinFileDescriptor) {
+			previousState, err := term.MakeRaw(stdinFileDescriptor)
+			if err != nil {
+				return fmt.Errorf("failed to put terminal into raw mode: %w", err)
+			}
+
+			defer func() { _ = term.Restore(stdinFileDescriptor, previousState) }()
+
+			resizeConsole := func() {
+				if width, height, err := term.GetSize(stdinFileDescriptor); err == nil {
+					_ = console.resize(uint(width), uint(height)) //nolint:gosec
+				}
+			}
+
+			resizeConsole()
+
+			resizeCtx, cancelResize := context.WithCancel(ctx)
+			defer cancelResize()
+			notifyTerminalResize(resizeCtx, resizeConsole)
+		}
+
+		outputDone := make(chan error, 1)
+		go func() { outputDone <- console.copyOutput(cmd) }()
+
+		detached := make(chan error, 1)
+		go func() { detached <- console.copyInput(os.Stdin) }()
+
+		select {
+		case err := <-outputDone:
+			if err != nil {
+				return fmt.Errorf("console of %s failed: %w", args[0], err)
+			}
+		case err := <-detached:
+			_ = console.close()
+			if err != nil {
+				return fmt.Errorf("failed to forward input to console of %s: %w", args[0], err)
+			}
+		}
+
+		return nil
+	}
+
+	return command
+}
+
+// consoleConnection wraps an attached console websocket, serialising all writes to it.
+type consoleConnection struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+}
+
+// write writes a single message to the console websocket.
+func (c *consoleConnection) write(messageType int, message []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	if err := c.conn.WriteMessage(messageType, message); err != nil {
+		return fmt.Errorf("failed to write console message: %w", err)
+	}
+
+	return nil
+}
+
+// resize informs the console about the new size of the local terminal.
+func (c *consoleConnection) resize(width uint, height uint) error {
+	message, err := json.Marshal(networkmodel.ConsoleControlMessage{
+		Type:   networkmodel.ConsoleControlTypeResize,
+		Width:  width,
+		Height: height,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal resize message: %w", err)
+	}
+
+	return c.write(websocket.TextMessage, message)
+}
+
+// close gracefully closes the console websocket.
+func (c *consoleConnection) close() error {
+	return c.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "detached"))
+}
+
+// copyOutput copies all console output to the commands output until the console is closed.
+func (c *consoleConnection) copyOutput(cmd *cobra.Command) error {
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read console output: %w", err)
+		}
+
+		if _, err := cmd.OutOrStdout().Write(message); err != nil {
+			return fmt.Errorf("failed to print console output: %w", err)
+		}
+	}
+}
+
+// copyInput forwards the local input to the console until the detach sequence is entered.
+func (c *consoleConnection) copyInput(input *os.File) error {
+	buffer := make([]byte, 1024)
+	pendingDetach := false
+
+	for {
+		readBytes, err := input.Read(buffer)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		forward, stillPending, detach := scanDetachSequence(pendingDetach, buffer[:readBytes])
+		pendingDetach = stillPending
+
+		if len(forward) > 0 {
+			if err := c.write(websocket.BinaryMessage, forward); err != nil {
+				return err
+			}
+		}
+
+		if detach {
+			return nil
+		}
+	}
+}
+
+// scanDetachSequence scans the input for the detach sequence, yielding back the input to forward, if the first detach key
+// is held back awaiting the next input and if the detach sequence was entered.
+func scanDetachSequence(pendingDetach bool, input []byte) ([]byte, bool, bool) {
+	forward := make([]byte, 0, len(input)+1)
+	for _, key := range input {
+		if pendingDetach {
+			if key == detachSecondKey {
+				return forward, false, true
+			}
+
+			forward = append(forward, detachFirstKey)
+			pendingDetach = false
+		}
+
+		if key == detachFirstKey {
+			pendingDetach = true
+			continue
+		}
+
+		forward = append(forward, key)
+	}
+
+	return forward, pendingDetach, false
+}
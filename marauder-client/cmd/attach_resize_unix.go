@@ -0,0 +1,29 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyTerminalResize calls onResize whenever the local terminal is resized until the context is done.
+func notifyTerminalResize(ctx context.Context, onResize func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				onResize()
+			}
+		}
+	}()
+}
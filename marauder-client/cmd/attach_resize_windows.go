@@ -0,0 +1,8 @@
+//go:build windows
+
+package cmd
+
+import "context"
+
+// notifyTerminalResize is a NOOP on windows, which does not signal terminal resizes.
+func notifyTerminalResize(_ context.Context, _ func()) {}
@@ -0,0 +1,11 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// BackupCommand constructs the backup subcommand.
+func BackupCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup",
+		Short: "The parent command for backups of server data folders managed by the operators",
+	}
+}
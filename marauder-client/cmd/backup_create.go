@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+// BackupCreateCommand constructs the backup create subcommand.
+func BackupCreateCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "create [reference]",
+		Short: "Creates a backup of the data folder of the specified server",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{creating backup of %s}", serverUUID))
+
+		backup, err := client.CreateServerBackup(ctx, serverUUID)
+		if err != nil {
+			return fmt.Errorf("failed to create backup of %s: %w", args[0], err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{created backup %s of %s}", backup.Name, args[0]))
+		printFetchResult(cmd, backup)
+
+		return nil
+	}
+
+	return command
+}
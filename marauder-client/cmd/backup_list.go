@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+// BackupListCommand constructs the backup list subcommand.
+func BackupListCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "list [reference]",
+		Short: "Lists the backups of the specified server, newest first",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{requesting backups of %s}", serverUUID))
+
+		backups, err := client.FetchServerBackups(ctx, serverUUID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch backups of %s: %w", args[0], err)
+		}
+
+		printFetchResult(cmd, backups)
+
+		return nil
+	}
+
+	return command
+}
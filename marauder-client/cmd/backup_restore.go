@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+// BackupRestoreCommand constructs the backup restore subcommand.
+func BackupRestoreCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "restore [reference] [backup]",
+		Short: "Restores a backup into the data folder of the specified server, which has to be stopped",
+		Args:  cobra.ExactArgs(2),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{restoring backup %s of %s}", args[1], serverUUID))
+
+		if err := client.RestoreServerBackup(ctx, serverUUID, args[1]); err != nil {
+			return fmt.Errorf("failed to restore backup %s of %s: %w", args[1], args[0], err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{restored backup %s of %s}", args[1], args[0]))
+
+		return nil
+	}
+
+	return command
+}
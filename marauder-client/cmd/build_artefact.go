@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"context"
-	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -15,12 +15,14 @@ import (
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/spf13/cobra"
-	"golang.org/x/crypto/ssh"
 )
 
 // NAN is a stand in value for strings that are not applicable.
 const NAN = "nan"
 
+// ErrNoManifestFound is returned if a directory of manifest files does not hold any manifest.
+var ErrNoManifestFound = errors.New("no manifest found")
+
 type OutputNameData struct {
 	Identifier string
 	Version    string
@@ -31,17 +33,19 @@ func BuildArtefactCommand(
 	configuration *Configuration,
 ) *cobra.Command {
 	var (
-		manifestFileLocation string
-		tarballName          string
-		sign                 bool
+		manifestFileLocations []string
+		tarballName           string
+		sign                  bool
 	)
 
 	command := &cobra.Command{
 		Use:   "artefact",
-		Short: "Builds a marauder artefact into a tarball ready for publishing.",
+		Short: "Builds marauder artefacts into tarballs ready for publishing.",
 		Args:  cobra.MaximumNArgs(1),
 	}
-	command.PersistentFlags().StringVarP(&manifestFileLocation, "manifest", "m", ".marauder.json", "location of the manifest file")
+	command.PersistentFlags().StringSliceVarP(
+		&manifestFileLocations, "manifest", "m", []string{".marauder.json"}, "locations of the manifest files or directories of manifest files",
+	)
 	command.PersistentFlags().StringVarP(&tarballName, "output", "o", "{{.Identifier}}-{{.Version}}-artefact.tar.gz", "name of the output tarball")
 	command.PersistentFlags().BoolVar(&sign, "sign", true, "whether or not to sign the output artefact.")
 
@@ -51,79 +55,167 @@ func BuildArtefactCommand(
 			workDirectory = args[0]
 		}
 
-		return buildArtefactInternalExecute(cmd, configuration, manifestFileLocation, tarballName, workDirectory, sign)
+		_, err := buildArtefactsInternalExecute(cmd, configuration, manifestFileLocations, tarballName, workDirectory, sign)
+
+		return err
 	}
 
 	return command
 }
 
-// buildArtefactInternalExecute is the internal execution logic of the build artefact command.
-func buildArtefactInternalExecute(
+// buildArtefactsInternalExecute builds the artefacts of all manifests found at the passed locations, sharing the build
+// information of the work directory between them.
+// All artefacts are attempted to be built, an error is returned if any of them failed.
+// The successfully built artefacts are yielded back regardless, allowing callers to clean up their tarballs.
+func buildArtefactsInternalExecute(
 	cmd *cobra.Command,
 	configuration *Configuration,
-	manifestFileLocation, tarballName, workDirectory string,
+	manifestFileLocations []string,
+	tarballName, workDirectory string,
 	sign bool,
-) error {
-	// Parse the manifest.
-	manifest, err := parseManifestFromDisk(cmd, manifestFileLocation, workDirectory)
+) ([]TarballBuildResult, error) {
+	manifestFiles, err := resolveManifestFileLocations(manifestFileLocations)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	buildInformation, fetched := fetchProjectBuildInformation(cmd, workDirectory)
+
+	results := make([]TarballBuildResult, 0, len(manifestFiles))
+	statuses := make([]artefactStatus, 0, len(manifestFiles))
+	for _, manifestFile := range manifestFiles {
+		status := artefactStatus{ManifestLocation: manifestFile, Identifier: NAN, Version: NAN}
+
+		manifest, err := parseManifestFromDisk(cmd, manifestFile, buildInformation, fetched)
+		if err == nil {
+			status.Identifier, status.Version = manifest.Identifier, manifest.Version
+
+			var result TarballBuildResult
+			if result, err = buildArtefactInternalExecute(cmd, configuration, manifest, tarballName, workDirectory, sign); err == nil {
+				result.ManifestLocation = manifestFile
+				results = append(results, result)
+				status.Stage = artefactStageBuilt
+			}
+		}
+
+		status.Err = err
+		statuses = append(statuses, status)
+	}
+
+	if len(statuses) > 1 {
+		printArtefactStatuses(cmd, statuses)
+	}
+
+	if err := artefactStatusErr(statuses); err != nil {
+		return results, fmt.Errorf("failed to build artefacts: %w", err)
 	}
 
+	return results, nil
+}
+
+// buildArtefactInternalExecute is the internal execution logic of the build artefact command.
+func buildArtefactInternalExecute(
+	cmd *cobra.Command,
+	configuration *Configuration,
+	manifest filemodel.Manifest,
+	tarballName, workDirectory string,
+	sign bool,
+) (TarballBuildResult, error) {
 	// Parse the tarball name from the commandline flag
 	finalTarballName, err := utils.ExecuteStringTemplateToString(tarballName, OutputNameData{
 		Identifier: manifest.Identifier,
 		Version:    manifest.Version,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to execute template for tarball output name: %w", err)
+		return TarballBuildResult{}, fmt.Errorf("failed to execute template for tarball output name: %w", err)
 	}
 
 	// Create the output file
 	cmd.PrintErrln(bunt.Sprintf("Gray{creating output artefact tarball *%s*}", finalTarballName))
 	tarballFileRef, err := os.Create(utils.CleanPathWorkingDir(finalTarballName))
 	if err != nil {
-		return fmt.Errorf("failed to open output tarball: %w", err)
+		return TarballBuildResult{}, fmt.Errorf("failed to open output tarball: %w", err)
 	}
 	defer func() { utils.SwallowClose(tarballFileRef) }()
 
 	// Build and write the tarball to file.
 	if err := builder.CreateArtefactTarball(os.DirFS(workDirectory), manifest, tarballFileRef); err != nil {
-		return fmt.Errorf("failed to create artefact tarball: %w", err)
+		return TarballBuildResult{}, fmt.Errorf("failed to create artefact tarball: %w", err)
 	}
 
 	cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully build artefact}"))
 	cmd.Println(finalTarballName)
-	cmd.SetContext(context.WithValue(cmd.Context(), KeyBuildCommandTarballOutput, TarballBuildResult{
+
+	result := TarballBuildResult{
 		TarballFileLocation:      finalTarballName,
 		TarballSignatureLocation: "",
 		Manifest:                 manifest,
-	}))
+	}
 
 	if sign {
-		if err := signCreatedArtefact(cmd, configuration, tarballFileRef); err != nil {
-			return err
+		signatureFileName, err := signCreatedArtefact(cmd, configuration, tarballFileRef)
+		if err != nil {
+			return TarballBuildResult{}, err
 		}
+
+		result.TarballSignatureLocation = signatureFileName
 	}
 
-	return nil
+	return result, nil
 }
 
-// parseManifestFromDisk parses the manifest from the disk with the given name in the given work directory.
-func parseManifestFromDisk(cmd *cobra.Command, manifestFileLocation string, workDirectory string) (filemodel.Manifest, error) {
-	file, err := os.ReadFile(utils.CleanPathWorkingDir(manifestFileLocation))
-	if err != nil {
-		return filemodel.Manifest{}, fmt.Errorf("failed to read %s: %w", manifestFileLocation, err)
+// resolveManifestFileLocations resolves the passed manifest file locations into the manifest files to build.
+// Directories are expanded into all json files found directly in them, ordered by name.
+func resolveManifestFileLocations(manifestFileLocations []string) ([]string, error) {
+	manifestFiles := make([]string, 0, len(manifestFileLocations))
+	for _, manifestFileLocation := range manifestFileLocations {
+		stat, err := os.Stat(utils.CleanPathWorkingDir(manifestFileLocation))
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat manifest location %s: %w", manifestFileLocation, err)
+		}
+
+		if !stat.IsDir() {
+			manifestFiles = append(manifestFiles, manifestFileLocation)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(utils.CleanPathWorkingDir(manifestFileLocation), "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list manifests in %s: %w", manifestFileLocation, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("directory %s: %w", manifestFileLocation, ErrNoManifestFound)
+		}
+
+		for _, match := range matches {
+			manifestFiles = append(manifestFiles, filepath.Join(manifestFileLocation, filepath.Base(match)))
+		}
 	}
 
-	var manifest filemodel.Manifest
+	return manifestFiles, nil
+}
 
+// fetchProjectBuildInformation fetches the build information of the project in the given work directory.
+// If the build information cannot be fetched, stand in values are yielded back and the build information are reported
+// as not fetched, excluding them from the built manifests.
+func fetchProjectBuildInformation(cmd *cobra.Command, workDirectory string) (filemodel.BuildInformation, bool) {
 	cmd.PrintErrln(bunt.Sprintf("Gray{fetching build information from project}"))
+
 	buildInformation, err := builder.FetchBuildInformation(workDirectory)
 	if err != nil {
 		cmd.PrintErrln(bunt.Sprintf("Red{failed to parse build information, excluding them: %s}", err.Error()))
-		timestamp := time.Now()
-		buildInformation = filemodel.BuildInformation{
+
+		timestamp, found, err := builder.SourceDateEpoch()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("Red{failed to parse source date epoch, using current time: %s}", err.Error()))
+		}
+
+		if !found {
+			timestamp = time.Now()
+		}
+
+		return filemodel.BuildInformation{
 			Repository:           NAN,
 			Branch:               NAN,
 			CommitUser:           NAN,
@@ -132,72 +224,87 @@ func parseManifestFromDisk(cmd *cobra.Command, manifestFileLocation string, work
 			CommitMessage:        NAN,
 			Timestamp:            timestamp,
 			BuildSpecificVersion: "t" + strconv.FormatInt(timestamp.Unix(), 10),
-		}
-	} else {
-		manifest.BuildInformation = &buildInformation
+		}, false
 	}
 
-	// Parse the manifest file
-	cmd.PrintErrln(bunt.Sprintf("Gray{parsing manifest file %s}", manifestFileLocation))
+	return buildInformation, true
+}
 
-	templatedManifestContent, err := utils.ExecuteStringTemplateToString(string(file), struct {
-		Build filemodel.BuildInformation
-	}{
-		Build: buildInformation,
-	})
+// parseManifestFromDisk parses the manifest from the disk with the given name, resolving its templates with the passed
+// build information.
+// The build information are only included in the manifest if they were fetched from the project.
+func parseManifestFromDisk(
+	cmd *cobra.Command,
+	manifestFileLocation string,
+	buildInformation filemodel.BuildInformation,
+	fetched bool,
+) (filemodel.Manifest, error) {
+	templatedManifestContent, err := readManifestFromDisk(cmd, manifestFileLocation, buildInformation)
 	if err != nil {
-		return filemodel.Manifest{}, fmt.Errorf("failed to resolve templates in manifest file: %w", err)
+		return filemodel.Manifest{}, err
+	}
+
+	var manifest filemodel.Manifest
+	if fetched {
+		manifest.BuildInformation = &buildInformation
 	}
 
-	if err := json.Unmarshal([]byte(templatedManifestContent), &manifest); err != nil {
+	if err := json.Unmarshal(templatedManifestContent, &manifest); err != nil {
 		return filemodel.Manifest{}, fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
 	return manifest, nil
 }
 
-// signCreatedArtefact signs the tarball file ref and stores it under the same name .sig.
-func signCreatedArtefact(cmd *cobra.Command, configuration *Configuration, tarballFileRef *os.File) error {
-	key, err := configuration.ParseSigningKey()
+// readManifestFromDisk reads the manifest from the disk with the given name and resolves its templates with the passed
+// build information.
+func readManifestFromDisk(cmd *cobra.Command, manifestFileLocation string, buildInformation filemodel.BuildInformation) ([]byte, error) {
+	file, err := os.ReadFile(utils.CleanPathWorkingDir(manifestFileLocation))
 	if err != nil {
-		return fmt.Errorf("failed to parse signing key: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", manifestFileLocation, err)
 	}
 
-	if _, err := tarballFileRef.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("failed to seek 0 index on file for signature computation: %w", err)
-	}
+	// Parse the manifest file
+	cmd.PrintErrln(bunt.Sprintf("Gray{parsing manifest file %s}", manifestFileLocation))
 
-	sha256, err := utils.ComputeSha256(tarballFileRef)
+	templatedManifestContent, err := utils.ExecuteStringTemplateToString(string(file), struct {
+		Build filemodel.BuildInformation
+	}{
+		Build: buildInformation,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to compute signature: %w", err)
+		return nil, fmt.Errorf("failed to resolve templates in manifest file: %w", err)
+	}
+
+	return []byte(templatedManifestContent), nil
+}
+
+// signCreatedArtefact signs the tarball file ref and stores it under the same name .sig, yielding back the name of the
+// signature file.
+func signCreatedArtefact(cmd *cobra.Command, configuration *Configuration, tarballFileRef *os.File) (string, error) {
+	if _, err := tarballFileRef.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek 0 index on file for signature computation: %w", err)
 	}
 
-	signature, err := key.Sign(rand.Reader, sha256)
+	signature, keyID, err := signWithConfiguredKey(configuration, tarballFileRef)
 	if err != nil {
-		return fmt.Errorf("failed to sign sha hashsum: %w", err)
+		return "", err
 	}
 
 	signatureFileName := tarballFileRef.Name() + ".sig"
-	if err := os.WriteFile(signatureFileName, ssh.Marshal(signature), 0o600); err != nil {
-		return fmt.Errorf("failed to write signature to %s: %w", signatureFileName, err)
+	if err := os.WriteFile(signatureFileName, signature, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write signature to %s: %w", signatureFileName, err)
 	}
 
-	cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully signed artefact}"))
+	cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully signed artefact with key %s}", keyID))
 	cmd.Println(signatureFileName)
 
-	result, ok := cmd.Context().Value(KeyBuildCommandTarballOutput).(TarballBuildResult)
-	if !ok {
-		return fmt.Errorf("failed to retrieve existing context: %w", ErrContextMissingValue)
-	}
-
-	result.TarballSignatureLocation = signatureFileName
-	cmd.SetContext(context.WithValue(cmd.Context(), KeyBuildCommandTarballOutput, result))
-
-	return nil
+	return signatureFileName, nil
 }
 
-// TarballBuildResult is a simple helper struct stored in the context of the build command to indicate the location of the output.
+// TarballBuildResult is a simple helper struct yielded back by the build command to indicate the location of the output.
 type TarballBuildResult struct {
+	ManifestLocation         string
 	Manifest                 filemodel.Manifest
 	TarballFileLocation      string
 	TarballSignatureLocation string
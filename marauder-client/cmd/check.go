@@ -0,0 +1,11 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// CheckCommand constructs the check subcommand.
+func CheckCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "The parent command for checking the deployments defined on the controller for problems",
+	}
+}
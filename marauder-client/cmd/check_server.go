@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// CheckServerCommand constructs the check server subcommand.
+func CheckServerCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "server [reference]",
+		Short: "Lists the files shipped by multiple artefacts of the specified server's TARGET and IS state",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	var asJSON bool
+	command.Flags().BoolVar(&asJSON, "json", false, "print the conflicts as json")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{checking file conflicts of %s}", serverUUID))
+
+		conflicts := make(map[networkmodel.ServerStateType][]networkmodel.FileConflict)
+		conflictCount := 0
+		for _, state := range []networkmodel.ServerStateType{networkmodel.TARGET, networkmodel.IS} {
+			stateConflicts, err := client.FetchServerFileConflicts(ctx, serverUUID, state)
+			if err != nil {
+				return fmt.Errorf("failed to fetch file conflicts of the %s state of %s: %w", state, args[0], err)
+			}
+
+			conflicts[state] = stateConflicts
+			conflictCount += len(stateConflicts)
+		}
+
+		if asJSON {
+			printFetchResult(cmd, conflicts)
+		} else {
+			printFileConflicts(cmd, conflicts)
+		}
+
+		if conflictCount > 0 {
+			return fmt.Errorf("found %d conflicting files on %s: %w", conflictCount, args[0], networkmodel.ErrFileConflict)
+		}
+
+		return nil
+	}
+
+	return command
+}
+
+// printFileConflicts prints the file conflicts of a server grouped by the state they were found in.
+func printFileConflicts(cmd *cobra.Command, conflicts map[networkmodel.ServerStateType][]networkmodel.FileConflict) {
+	for _, state := range []networkmodel.ServerStateType{networkmodel.TARGET, networkmodel.IS} {
+		if len(conflicts[state]) == 0 {
+			cmd.PrintErrln(bunt.Sprintf("*%s* LimeGreen{no file conflicts}", state))
+			continue
+		}
+
+		cmd.Println(bunt.Sprintf("*%s*", state))
+
+		for _, conflict := range conflicts[state] {
+			cmd.Println(bunt.Sprintf("  #c43f43{%s} Gray{shipped by %v}", conflict.Path, conflict.ArtefactIdentifiers))
+		}
+	}
+}
@@ -1,28 +1,32 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/worker"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/term"
 )
 
-type CommandContextKeyType int
+// sshAuthSockEnvironment is the environment variable pointing to the socket of the ssh agent.
+const sshAuthSockEnvironment = "SSH_AUTH_SOCK"
 
-const (
-	// KeyBuildCommandTarballOutput defines the shared key used by the build command to store the build output target in a context.
-	KeyBuildCommandTarballOutput CommandContextKeyType = iota
-	KeyPublishResultArtefactModel
-)
-
-// ErrContextMissingValue is returned if a command expects a context to contain a specific value, but it isn't there.
-var ErrContextMissingValue = errors.New("context missing value")
+// ErrSigningKeyPassphraseRequired is returned if the signing key is encrypted but its passphrase cannot be prompted for.
+// Such keys can be added to an ssh agent instead.
+var ErrSigningKeyPassphraseRequired = errors.New("signing key passphrase required")
 
 // DefaultConfiguration defines the default configuration.
 func DefaultConfiguration() Configuration {
@@ -41,6 +45,7 @@ type Configuration struct {
 	ControllerHost string                 `yaml:"controllerHost"`
 	TLS            utils.TLSConfiguration `yaml:"tls"`
 	SigningKey     string                 `yaml:"signingKey"`
+	Tracing        tracing.Configuration  `yaml:"tracing"`
 }
 
 // CreateTLSReadyHTTPClient creates a tls ready http client for communication with the controller.
@@ -78,21 +83,125 @@ func (c Configuration) CreateTLSReadyHTTPClient() (controller.DownloadingClient,
 	}, nil
 }
 
-// ParseSigningKey parses the signing key as defined int the configuration.
-func (c Configuration) ParseSigningKey() (ssh.Signer, error) {
+// ParseSigningKey resolves the signer of artefacts as defined in the configuration.
+// If an ssh agent is reachable via SSH_AUTH_SOCK and holds the configured key, the agent signs, supporting
+// passphrase-protected keys already unlocked in the agent as well as hardware keys. The agent key is identified by the
+// public key next to the configured key (<signingKey>.pub), or the configured key itself if it is a public key.
+// Otherwise, the private key is read from disk, prompting for its passphrase if it is encrypted.
+// The returned close function releases the connection to the agent and must always be called.
+func (c Configuration) ParseSigningKey() (ssh.Signer, func(), error) {
 	privateKeyFilePath, err := utils.EvaluateFilePathTemplate(c.SigningKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to evaluate private key file path: %w", err)
+		return nil, func() {}, fmt.Errorf("failed to evaluate private key file path: %w", err)
 	}
 
-	privateKeyBytes, err := os.ReadFile(filepath.Clean(privateKeyFilePath))
+	privateKeyFilePath = filepath.Clean(privateKeyFilePath)
+
+	agentSigner, closeAgent, err := findAgentSigner(privateKeyFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read private key file for signing: %w", err)
+		return nil, func() {}, err
+	}
+
+	if agentSigner != nil {
+		return agentSigner, closeAgent, nil
+	}
+
+	privateKeyBytes, err := os.ReadFile(privateKeyFilePath)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to read private key file for signing: %w", err)
 	}
 
 	key, err := ssh.ParsePrivateKey(privateKeyBytes)
+	if passphraseMissingErr := (&ssh.PassphraseMissingError{}); errors.As(err, &passphraseMissingErr) {
+		key, err = parseEncryptedPrivateKey(privateKeyFilePath, privateKeyBytes)
+	}
+
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to parse private key bytes: %w", err)
+	}
+
+	return key, func() {}, nil
+}
+
+// findAgentSigner looks up the signer of the key at the passed path in the ssh agent reachable via SSH_AUTH_SOCK.
+// If no agent is reachable, or it does not hold the key, no signer is yielded back. An agent that cannot be connected to
+// or queried, e.g. as SSH_AUTH_SOCK points to the socket of a terminated agent, is logged and skipped as well, so the key
+// on disk is used instead.
+func findAgentSigner(keyFilePath string) (ssh.Signer, func(), error) {
+	agentSocket := os.Getenv(sshAuthSockEnvironment)
+	if agentSocket == "" {
+		return nil, func() {}, nil
+	}
+
+	publicKey, err := readPublicKey(keyFilePath)
+	if err != nil || publicKey == nil {
+		return nil, func() {}, err
+	}
+
+	connection, err := (&net.Dialer{}).DialContext(context.Background(), "unix", agentSocket)
+	if err != nil {
+		logrus.Warnf("failed to connect to ssh agent at %s, using the signing key on disk: %s", agentSocket, err)
+		return nil, func() {}, nil
+	}
+
+	signers, err := agent.NewClient(connection).Signers()
+	if err != nil {
+		_ = connection.Close()
+		logrus.Warnf("failed to list keys of ssh agent at %s, using the signing key on disk: %s", agentSocket, err)
+
+		return nil, func() {}, nil
+	}
+
+	for _, signer := range signers {
+		if bytes.Equal(signer.PublicKey().Marshal(), publicKey.Marshal()) {
+			return signer, func() { _ = connection.Close() }, nil
+		}
+	}
+
+	_ = connection.Close()
+
+	return nil, func() {}, nil
+}
+
+// readPublicKey reads the public key of the key at the passed path, either from the file itself if it holds a public
+// key or from the .pub file next to it. If neither exists, no public key is yielded back.
+func readPublicKey(keyFilePath string) (ssh.PublicKey, error) {
+	for _, publicKeyFilePath := range []string{keyFilePath, keyFilePath + ".pub"} {
+		publicKeyBytes, err := os.ReadFile(filepath.Clean(publicKeyFilePath))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read public key file %s: %w", publicKeyFilePath, err)
+		}
+
+		if publicKey, _, _, _, err := ssh.ParseAuthorizedKey(publicKeyBytes); err == nil {
+			return publicKey, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// parseEncryptedPrivateKey parses a passphrase-protected private key, prompting for its passphrase on the terminal.
+func parseEncryptedPrivateKey(privateKeyFilePath string, privateKeyBytes []byte) (ssh.Signer, error) {
+	stdinFileDescriptor := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdinFileDescriptor) {
+		return nil, fmt.Errorf("cannot prompt for the passphrase of %s: %w", privateKeyFilePath, ErrSigningKeyPassphraseRequired)
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Enter passphrase for %s: ", privateKeyFilePath)
+	passphrase, err := term.ReadPassword(stdinFileDescriptor)
+	_, _ = fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	key, err := ssh.ParsePrivateKeyWithPassphrase(privateKeyBytes, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key bytes: %w", err)
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
 	}
 
 	return key, nil
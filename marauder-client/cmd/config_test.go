@@ -0,0 +1,130 @@
+package cmd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/knockturnmc/marauder/marauder-client/cmd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var _ = Describe("Parsing the signing key", Label("unittest"), func() {
+	var (
+		folder        string
+		privateKey    ed25519.PrivateKey
+		publicKey     ssh.PublicKey
+		configuration cmd.Configuration
+	)
+
+	writeKeyFile := func(path string, content []byte) {
+		GinkgoHelper()
+
+		Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+	}
+
+	writePrivateKey := func() {
+		GinkgoHelper()
+
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		Expect(err).To(Not(HaveOccurred()))
+		writeKeyFile(configuration.SigningKey, pem.EncodeToMemory(block))
+	}
+
+	// serveAgent serves the passed agent on a unix socket in the test folder, using the accepted connections as passed.
+	serveAgent := func(serve func(connection net.Conn)) string {
+		GinkgoHelper()
+
+		socket := filepath.Join(folder, "agent.sock")
+		listener, err := (&net.ListenConfig{}).Listen(GinkgoT().Context(), "unix", socket)
+		Expect(err).To(Not(HaveOccurred()))
+		DeferCleanup(listener.Close)
+
+		go func() {
+			for {
+				connection, err := listener.Accept()
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+
+				go serve(connection)
+			}
+		}()
+
+		return socket
+	}
+
+	serveKeyring := func(keyring agent.Agent) string {
+		return serveAgent(func(connection net.Conn) {
+			defer connection.Close()
+			_ = agent.ServeAgent(keyring, connection)
+		})
+	}
+
+	BeforeEach(func() {
+		folder = GinkgoT().TempDir()
+
+		var err error
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Not(HaveOccurred()))
+		publicKey, err = ssh.NewPublicKey(privateKey.Public())
+		Expect(err).To(Not(HaveOccurred()))
+
+		configuration = cmd.Configuration{SigningKey: filepath.Join(folder, "signingKey")}
+		writeKeyFile(configuration.SigningKey+".pub", ssh.MarshalAuthorizedKey(publicKey))
+	})
+
+	expectSignerOfKey := func() {
+		GinkgoHelper()
+
+		signer, closeSigner, err := configuration.ParseSigningKey()
+		defer closeSigner()
+
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(signer.PublicKey().Marshal()).To(Equal(publicKey.Marshal()))
+	}
+
+	It("signs with the ssh agent holding the key", func() {
+		keyring := agent.NewKeyring()
+		Expect(keyring.Add(agent.AddedKey{PrivateKey: privateKey})).To(Succeed())
+		GinkgoT().Setenv("SSH_AUTH_SOCK", serveKeyring(keyring))
+
+		// Only the public key is on disk, the key can only be used through the agent.
+		expectSignerOfKey()
+	})
+
+	It("uses the key on disk if the ssh agent does not hold the key", func() {
+		writePrivateKey()
+		GinkgoT().Setenv("SSH_AUTH_SOCK", serveKeyring(agent.NewKeyring()))
+
+		expectSignerOfKey()
+	})
+
+	It("uses the key on disk if the ssh agent socket is stale", func() {
+		writePrivateKey()
+		GinkgoT().Setenv("SSH_AUTH_SOCK", filepath.Join(folder, "terminated-agent.sock"))
+
+		expectSignerOfKey()
+	})
+
+	It("uses the key on disk if the ssh agent cannot list its keys", func() {
+		writePrivateKey()
+		GinkgoT().Setenv("SSH_AUTH_SOCK", serveAgent(func(connection net.Conn) { _ = connection.Close() }))
+
+		expectSignerOfKey()
+	})
+
+	It("uses the key on disk without an ssh agent", func() {
+		writePrivateKey()
+		GinkgoT().Setenv("SSH_AUTH_SOCK", "")
+
+		expectSignerOfKey()
+	})
+})
@@ -14,12 +14,18 @@ func DeployArtefactCommand(
 	ctx context.Context,
 	config *Configuration,
 ) *cobra.Command {
+	var allowFileConflicts bool
+
 	command := &cobra.Command{
 		Use:   "artefact artefactUUID servers...",
 		Short: "Patches a new deployment target onto ",
 		Args:  cobra.MinimumNArgs(2),
 	}
 
+	command.Flags().BoolVar(
+		&allowFileConflicts, "allow-file-conflicts", false, "deploy the artefact even if it ships files shipped by other artefacts of the servers",
+	)
+
 	command.RunE = func(cmd *cobra.Command, args []string) error {
 		client, err := config.CreateTLSReadyHTTPClient()
 		if err != nil {
@@ -40,6 +46,7 @@ func DeployArtefactCommand(
 		return deployArtefactInternalExecute(ctx, cmd, client, networkmodel.UpdateServerStateRequest{
 			ArtefactIdentifier: artefact.Identifier,
 			ArtefactUUID:       &artefactUUID,
+			AllowFileConflicts: allowFileConflicts,
 		}, args[1:])
 	}
 
@@ -62,10 +69,7 @@ func deployArtefactInternalExecute(
 			return fmt.Errorf("failed to fetch server uuid at %d: %w", i, err)
 		}
 
-		if err := client.UpdateState(ctx, serverUUID, networkmodel.TARGET, networkmodel.UpdateServerStateRequest{
-			ArtefactIdentifier: updateRequest.ArtefactIdentifier,
-			ArtefactUUID:       updateRequest.ArtefactUUID,
-		}); err != nil {
+		if err := client.UpdateState(ctx, serverUUID, networkmodel.TARGET, updateRequest); err != nil {
 			cmd.PrintErrln(bunt.Sprintf("Red{failed to patch server %s: %s}", serverUUID, err.Error()))
 			resultingErr = err
 		} else {
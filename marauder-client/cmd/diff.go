@@ -0,0 +1,11 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// DiffCommand constructs the diff subcommand.
+func DiffCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff",
+		Short: "The parent command for comparing deployed files with their artefacts",
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// DiffServerCommand constructs the diff server subcommand.
+func DiffServerCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "server [reference]",
+		Short: "Shows the files of the specified server that drifted from the artefacts in its IS state",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	var lastCheck, asJSON bool
+	command.Flags().BoolVar(&lastCheck, "last-check", false, "show the drift found by the last periodic check instead of computing it on the operator")
+	command.Flags().BoolVar(&asJSON, "json", false, "print the drift as json")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		var drift networkmodel.ServerDrift
+		if lastCheck {
+			cmd.PrintErrln(bunt.Sprintf("Gray{requesting last drift check of %s}", serverUUID))
+			drift, err = client.FetchServerDrift(ctx, serverUUID)
+		} else {
+			cmd.PrintErrln(bunt.Sprintf("Gray{computing drift of %s on its operator}", serverUUID))
+			drift, err = client.ComputeServerDrift(ctx, serverUUID)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch drift of %s: %w", args[0], err)
+		}
+
+		if asJSON {
+			printFetchResult(cmd, drift)
+			return nil
+		}
+
+		printServerDrift(cmd, drift)
+
+		return nil
+	}
+
+	return command
+}
+
+// printServerDrift prints the drift of a server grouped by the artefacts the drifted files belong to.
+func printServerDrift(cmd *cobra.Command, drift networkmodel.ServerDrift) {
+	if !drift.HasDrift() {
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{no drift found} Gray{(checked at %s)}", drift.CheckedAt.Local().Format("2006-01-02 15:04:05")))
+		return
+	}
+
+	cmd.PrintErrln(bunt.Sprintf("Gray{checked at %s}", drift.CheckedAt.Local().Format("2006-01-02 15:04:05")))
+
+	lastArtefact := ""
+	for _, file := range drift.Files {
+		if file.ArtefactIdentifier != lastArtefact {
+			lastArtefact = file.ArtefactIdentifier
+			cmd.Println(bunt.Sprintf("*%s* Gray{%s}", file.ArtefactIdentifier, file.ArtefactVersion))
+		}
+
+		switch file.Kind {
+		case networkmodel.FileDriftMissing:
+			cmd.Println(bunt.Sprintf("  #c43f43{missing}   %s", file.Path))
+		default:
+			cmd.Println(bunt.Sprintf("  Gold{modified}  %s Gray{[%s]}", file.Path, file.EqualityProvider))
+		}
+
+		if file.Detail != "" {
+			cmd.Println(bunt.Sprintf("            Gray{%s}", file.Detail))
+		}
+	}
+}
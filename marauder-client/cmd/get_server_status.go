@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// GetServerStatusCommand constructs the server status fetch subcommand.
+func GetServerStatusCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "status [environment|reference]",
+		Short: "Fetch the live runtime status of servers from their operators",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		resultSlice := make([]networkmodel.ServerRuntimeStatus, 0)
+
+		defer func() { printFetchResult(cmd, resultSlice) }()
+
+		// Attempt to parse uuid.
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err == nil {
+			cmd.PrintErrln(bunt.Sprintf("Gray{requesting status of server %s}", serverUUID))
+
+			status, err := client.FetchServerStatus(ctx, serverUUID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch status of server %s: %w", serverUUID, err)
+			}
+
+			resultSlice = append(resultSlice, status)
+
+			return nil
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{requesting status of servers by environment}"))
+
+		servers, err := client.FetchServers(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch servers %s: %w", args[0], err)
+		}
+
+		var lastErr error
+		for _, server := range servers {
+			status, err := client.FetchServerStatus(ctx, server.UUID)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to fetch status of server %s/%s: %w", server.Environment, server.Name, err)
+				cmd.PrintErrln(bunt.Sprintf("Red{%s}", lastErr.Error()))
+
+				continue
+			}
+
+			resultSlice = append(resultSlice, status)
+		}
+
+		return lastErr
+	}
+
+	return command
+}
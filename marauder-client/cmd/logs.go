@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// LogsCommand constructs the logs command, printing the container logs of a server.
+func LogsCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	var options networkmodel.ServerLogOptions
+
+	command := &cobra.Command{
+		Use:   "logs [reference]",
+		Short: "Prints the container logs of the specified server",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	command.PersistentFlags().StringVar(&options.Since, "since", "", "only print logs since a timestamp (e.g. 2013-01-02T13:23:37Z) or duration (e.g. 42m)")
+	command.PersistentFlags().StringVar(&options.Tail, "tail", "", "number of lines to print from the end of the logs")
+	command.PersistentFlags().BoolVarP(&options.Follow, "follow", "f", false, "follow the log output")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		// Stop following the logs gracefully on interrupt.
+		ctx, cancelFunction := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancelFunction()
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{requesting logs for %s}", serverUUID))
+
+		logs, err := client.FetchServerLogs(ctx, serverUUID, options)
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs for %s: %w", args[0], err)
+		}
+
+		defer func() { _ = logs.Close() }()
+
+		if _, err := utils.CopyAndFlush(cmd.OutOrStdout(), logs); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to print logs for %s: %w", args[0], err)
+		}
+
+		return nil
+	}
+
+	return command
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// OperateServerPlanCommand constructs the operate server plan subcommand.
+func OperateServerPlanCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	action := networkmodel.UpdateWithRestart
+	var asJSON bool
+
+	command := &cobra.Command{
+		Use:   "plan [reference]",
+		Short: "Shows the changes an update lifecycle action would perform on the passed server without performing them",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	command.Flags().Var(&action, "action", "the update lifecycle action to plan")
+	command.Flags().BoolVar(&asJSON, "json", false, "print the plan as json")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{planning %s of %s}", action, serverUUID))
+
+		plan, err := client.PlanServerUpdate(ctx, serverUUID, action)
+		if err != nil {
+			return fmt.Errorf("failed to plan %s of %s: %w", action, args[0], err)
+		}
+
+		if asJSON {
+			printFetchResult(cmd, plan)
+			return nil
+		}
+
+		printUpdatePlan(cmd, plan)
+
+		return nil
+	}
+
+	return command
+}
+
+// printUpdatePlan prints the plan of an update grouped by the artefacts updated.
+func printUpdatePlan(cmd *cobra.Command, plan networkmodel.UpdatePlan) {
+	if len(plan.Artefacts) == 0 {
+		cmd.PrintErrln(bunt.Sprint("LimeGreen{server is up to date}"))
+		return
+	}
+
+	for _, artefact := range plan.Artefacts {
+		cmd.Println(bunt.Sprintf(
+			"*%s* Gray{%s -> %s}",
+			artefact.ArtefactIdentifier,
+			versionOrPlaceholder(artefact.FromVersion, "not installed"),
+			versionOrPlaceholder(artefact.ToVersion, "uninstalled"),
+		))
+
+		if artefact.Deferred {
+			cmd.Println(bunt.Sprint("  Gold{deferred, requires a restart}"))
+			continue
+		}
+
+		for _, file := range artefact.Files {
+			var change string
+			switch file.Change {
+			case networkmodel.FileUpdateCreate:
+				change = bunt.Sprintf("LimeGreen{%-9s}", file.Change)
+			case networkmodel.FileUpdateDelete:
+				change = bunt.Sprintf("#c43f43{%-9s}", file.Change)
+			default:
+				change = bunt.Sprintf("Gold{%-9s}", file.Change)
+			}
+
+			if file.LosesLocalModification {
+				cmd.Println(bunt.Sprintf("  %s %s #c43f43{(local modification lost)}", change, file.Path))
+			} else {
+				cmd.Println(bunt.Sprintf("  %s %s", change, file.Path))
+			}
+		}
+	}
+
+	if plan.RequiresRestart {
+		cmd.PrintErrln(bunt.Sprint("Gold{the update requires a restart of the server}"))
+	}
+
+	for _, conflict := range plan.FileConflicts {
+		cmd.PrintErrln(bunt.Sprintf("#c43f43{%s is shipped by multiple artefacts}", conflict))
+	}
+
+	if len(plan.FileConflicts) > 0 {
+		cmd.PrintErrln(bunt.Sprint("#c43f43{the update fails on files shipped by multiple artefacts, allow file conflicts on their target state to deploy them anyway}"))
+	}
+
+	if plan.FailsOnLocalModifications {
+		cmd.PrintErrln(bunt.Sprint("#c43f43{the update fails on local modifications of installed artefacts, force it to overwrite them}"))
+	} else if plan.LosesLocalModifications() {
+		cmd.PrintErrln(bunt.Sprint("#c43f43{the update loses local modifications}"))
+	}
+}
+
+// versionOrPlaceholder yields back the passed version or the placeholder if no version is passed.
+func versionOrPlaceholder(version *string, placeholder string) string {
+	if version == nil {
+		return placeholder
+	}
+
+	return *version
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 
 	"github.com/gonvenience/bunt"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/artefact"
@@ -97,7 +98,6 @@ func publishArtefactInternalExecute(
 	}
 
 	cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully uploaded artefact to controller}"))
-	cmd.SetContext(context.WithValue(cmd.Context(), KeyPublishResultArtefactModel, publishArtefact)) //nolint:contextcheck
 	publishedArtefactAsJSON, err := json.Marshal(publishArtefact)
 	if err != nil {
 		cmd.Println(fmt.Sprintf("%v", publishArtefact))
@@ -158,3 +158,132 @@ func publishArtefactCheckExisting(ctx context.Context, client controller.Client,
 
 	return remoteArtefact, nil
 }
+
+// publishArtefactsInternalExecute publishes all passed built artefacts to the controller at once, yielding back the
+// published artefacts in the same order.
+// Artefacts already found on the controller with a matching manifest are reused. If any artefact conflicts with a
+// different remote artefact or fails to publish, none of the artefacts are published.
+func publishArtefactsInternalExecute(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client controller.Client,
+	builds []TarballBuildResult,
+) ([]networkmodel.ArtefactModel, error) {
+	publishedArtefacts, statusCode, err := publishArtefactBatch(ctx, client, builds)
+	if err == nil {
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully uploaded %d artefacts to controller}", len(builds)))
+		return publishedArtefacts, nil
+	}
+
+	if statusCode.OrElse(0) != http.StatusConflict {
+		return nil, fmt.Errorf("failed to publish artefacts: %w", err)
+	}
+
+	// Some of the artefacts already exist, reuse them if they match and publish the remaining ones.
+	cmd.PrintErrln(bunt.Sprintf("Gray{some artefacts already exist on the controller, comparing them}"))
+
+	publishedArtefacts = make([]networkmodel.ArtefactModel, len(builds))
+	remainingIndices := make([]int, 0, len(builds))
+	remainingBuilds := make([]TarballBuildResult, 0, len(builds))
+	for index, build := range builds {
+		remoteArtefact, found, err := publishArtefactFindExisting(ctx, client, build)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s with the controller: %w", build.TarballFileLocation, err)
+		}
+
+		if found {
+			cmd.PrintErrln(bunt.Sprintf("Gray{found existing matching artefact %s on controller}", build.TarballFileLocation))
+			publishedArtefacts[index] = remoteArtefact
+
+			continue
+		}
+
+		remainingIndices = append(remainingIndices, index)
+		remainingBuilds = append(remainingBuilds, build)
+	}
+
+	if len(remainingBuilds) == 0 {
+		return publishedArtefacts, nil
+	}
+
+	remainingArtefacts, _, err := publishArtefactBatch(ctx, client, remainingBuilds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish artefacts: %w", err)
+	}
+
+	for remainingIndex, index := range remainingIndices {
+		publishedArtefacts[index] = remainingArtefacts[remainingIndex]
+	}
+
+	cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully uploaded %d artefacts to controller}", len(remainingBuilds)))
+
+	return publishedArtefacts, nil
+}
+
+// publishArtefactBatch publishes the tarballs and signatures of the passed built artefacts to the controller at once.
+func publishArtefactBatch(
+	ctx context.Context,
+	client controller.Client,
+	builds []TarballBuildResult,
+) ([]networkmodel.ArtefactModel, mo.Option[int], error) {
+	uploads := make([]controller.ArtefactUpload, 0, len(builds))
+	fileHandles := make([]*os.File, 0, 2*len(builds))
+
+	defer func() {
+		for _, fileHandle := range fileHandles {
+			_ = fileHandle.Close()
+		}
+	}()
+
+	for _, build := range builds {
+		artefactFileHandle, err := os.Open(filepath.Clean(build.TarballFileLocation))
+		if err != nil {
+			return nil, mo.None[int](), fmt.Errorf("failed to open artefact file: %w", err)
+		}
+
+		fileHandles = append(fileHandles, artefactFileHandle)
+
+		signatureFileHandle, err := os.Open(filepath.Clean(build.TarballSignatureLocation))
+		if err != nil {
+			return nil, mo.None[int](), fmt.Errorf("failed to open signature file: %w", err)
+		}
+
+		fileHandles = append(fileHandles, signatureFileHandle)
+		uploads = append(uploads, controller.ArtefactUpload{Artefact: artefactFileHandle, Signature: signatureFileHandle})
+	}
+
+	return client.PublishArtefacts(ctx, uploads) //nolint:wrapcheck
+}
+
+// publishArtefactFindExisting looks up the passed built artefact on the controller, reporting if it exists.
+// If the artefact exists but does not match the built one, a ErrRemoteManifestMismatch is returned.
+func publishArtefactFindExisting(
+	ctx context.Context,
+	client controller.Client,
+	build TarballBuildResult,
+) (networkmodel.ArtefactModel, bool, error) {
+	remoteArtefacts, err := client.FetchArtefacts(ctx, build.Manifest.Identifier)
+	if err != nil {
+		return networkmodel.ArtefactModel{}, false, fmt.Errorf("failed to fetch remote artefacts: %w", err)
+	}
+
+	if !slices.ContainsFunc(remoteArtefacts, func(remoteArtefact networkmodel.ArtefactModel) bool {
+		return remoteArtefact.Version == build.Manifest.Version
+	}) {
+		return networkmodel.ArtefactModel{}, false, nil
+	}
+
+	artefactFileHandle, err := os.Open(filepath.Clean(build.TarballFileLocation))
+	if err != nil {
+		return networkmodel.ArtefactModel{}, false, fmt.Errorf("failed to open artefact file: %w", err)
+	}
+
+	defer func() { _ = artefactFileHandle.Close() }()
+
+	remoteArtefact, err := publishArtefactCheckExisting(ctx, client, artefactFileHandle)
+	if err != nil {
+		return networkmodel.ArtefactModel{}, false, err
+	}
+
+	return remoteArtefact, true, nil
+}
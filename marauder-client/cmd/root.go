@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -40,12 +41,14 @@ tarball and uploading said artefact to the marauder controller.`,
 
 	command.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
 		configurationBytes, err := ReadFileFromOrStdin(configurationPath, cmd.InOrStdin())
-		if err != nil {
-			return nil //nolint:nilerr
+		if err == nil {
+			if err := yaml.Unmarshal(configurationBytes, configuration); err != nil {
+				return fmt.Errorf("failed to unmarshal configuration content: %w", err)
+			}
 		}
 
-		if err := yaml.Unmarshal(configurationBytes, configuration); err != nil {
-			return fmt.Errorf("failed to unmarshal configuration content: %w", err)
+		if err := tracing.Setup(cmd.Context(), "marauder-client", version, configuration.Tracing); err != nil {
+			return fmt.Errorf("failed to setup tracing: %w", err)
 		}
 
 		return nil
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// ErrEmptySearch is returned if a search is executed without any filter.
+var ErrEmptySearch = errors.New("empty search")
+
+// SearchCommand constructs the search subcommand.
+func SearchCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search",
+		Short: "The parent command for searching artefacts and servers by the commit and branch they were built from or by files they own",
+	}
+}
+
+// bindArtefactSearchFlags binds the filters of the passed artefact search to flags of the command.
+func bindArtefactSearchFlags(command *cobra.Command, search *networkmodel.ArtefactSearch) {
+	command.Flags().StringVar(&search.CommitHash, "commit", "", "find artefacts built from the commit, which may be abbreviated")
+	command.Flags().StringVar(&search.Branch, "branch", "", "find artefacts built from the branch")
+	command.Flags().StringVar(&search.File, "file", "", "find artefacts owning the file in the server folder, e.g. plugins/spellcore.jar")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// SearchArtefactsCommand constructs the artefact search subcommand.
+func SearchArtefactsCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "artefacts",
+		Short: "Searches the artefacts on the controller, newest first",
+		Args:  cobra.NoArgs,
+	}
+
+	var search networkmodel.ArtefactSearch
+	bindArtefactSearchFlags(command, &search)
+
+	command.RunE = func(cmd *cobra.Command, _ []string) error {
+		if search.IsEmpty() {
+			return fmt.Errorf("pass at least one of --commit, --branch or --file: %w", ErrEmptySearch)
+		}
+
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{searching artefacts}"))
+
+		artefacts, err := client.SearchArtefacts(ctx, search)
+		if err != nil {
+			return fmt.Errorf("failed to search artefacts: %w", err)
+		}
+
+		printFetchResult(cmd, artefacts)
+
+		return nil
+	}
+
+	return command
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/spf13/cobra"
+)
+
+// SearchServersCommand constructs the server search subcommand.
+func SearchServersCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "servers",
+		Short: "Searches the servers shipping an artefact matching the search",
+		Args:  cobra.NoArgs,
+	}
+
+	var (
+		search networkmodel.ArtefactSearch
+		state  string
+	)
+
+	bindArtefactSearchFlags(command, &search)
+	command.Flags().StringVar(&state, "state", string(networkmodel.IS), "the state of the servers the artefacts are searched in")
+
+	command.RunE = func(cmd *cobra.Command, _ []string) error {
+		if search.IsEmpty() {
+			return fmt.Errorf("pass at least one of --commit, --branch or --file: %w", ErrEmptySearch)
+		}
+
+		stateType := networkmodel.ServerStateType(strings.ToUpper(state))
+		if !networkmodel.KnownServerStateType(stateType) {
+			return fmt.Errorf("failed to parse passed state %s: %w", stateType, networkmodel.ErrUnknownServerState)
+		}
+
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("Gray{searching servers by their %s state}", stateType))
+
+		servers, err := client.SearchServers(ctx, search, stateType)
+		if err != nil {
+			return fmt.Errorf("failed to search servers: %w", err)
+		}
+
+		printFetchResult(cmd, servers)
+
+		return nil
+	}
+
+	return command
+}
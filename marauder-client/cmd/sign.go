@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	"github.com/spf13/cobra"
+)
+
+// SignCommand constructs the sign command, signing files like artefacts built elsewhere offline.
+func SignCommand(config *Configuration) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "sign file [signatureFile]",
+		Short: "Signs a file with the configured signing key",
+		Long: "Signs a file with the configured signing key in the SSHSIG format, writing the signature to the passed signature file " +
+			"or the file name suffixed with .sig. The signature can be verified via marauder verify or " +
+			"ssh-keygen -Y verify -n " + keyauth.ArtefactNamespace + ".",
+		Args: cobra.RangeArgs(1, 2),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		signatureFileName := args[0] + ".sig"
+		if len(args) > 1 {
+			signatureFileName = args[1]
+		}
+
+		file, err := os.Open(filepath.Clean(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", args[0], err)
+		}
+
+		defer func() { _ = file.Close() }()
+
+		signature, keyID, err := signWithConfiguredKey(config, file)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(signatureFileName, signature, 0o600); err != nil {
+			return fmt.Errorf("failed to write signature to %s: %w", signatureFileName, err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{successfully signed %s with key %s}", args[0], keyID))
+		cmd.Println(signatureFileName)
+
+		return nil
+	}
+
+	return command
+}
+
+// signWithConfiguredKey signs the passed message with the signing key of the configuration in the SSHSIG format,
+// yielding back the armored signature and the id of the key that created it.
+func signWithConfiguredKey(configuration *Configuration, message io.Reader) ([]byte, string, error) {
+	signer, closeSigner, err := configuration.ParseSigningKey()
+	defer closeSigner()
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	signature, err := keyauth.SignSSHSIG(signer, keyauth.ArtefactNamespace, message)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return signature, keyauth.KeyID(signer.PublicKey()), nil
+}
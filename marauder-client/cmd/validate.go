@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ValidateCommand constructs the validate subcommand.
+func ValidateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "validate",
+		Short: "The subcommand to validate files consumed by marauder",
+	}
+
+	return command
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-client/pkg/builder"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ErrInvalidManifest is returned if the validation of a manifest found problems.
+	ErrInvalidManifest = errors.New("invalid manifest")
+
+	// ErrUnknownDeploymentTarget is returned if a manifest targets an environment or server unknown to the controller.
+	ErrUnknownDeploymentTarget = errors.New("unknown deployment target")
+)
+
+// ValidateManifestCommand constructs the command validating a manifest without building its artefact.
+func ValidateManifestCommand(
+	ctx context.Context,
+	configuration *Configuration,
+) *cobra.Command {
+	var (
+		workDirectory string
+		offline       bool
+	)
+
+	command := &cobra.Command{
+		Use:   "manifest [path]",
+		Short: "Validates the manifest and dry-runs the matching of its files without building a tarball",
+		Args:  cobra.MaximumNArgs(1),
+	}
+	command.Flags().StringVarP(&workDirectory, "workdir", "w", "", "directory the file globs are matched in, defaults to the manifests directory")
+	command.Flags().BoolVar(&offline, "offline", false, "skip validating the deployment targets against the controller")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		manifestFileLocation := ".marauder.json"
+		if len(args) > 0 {
+			manifestFileLocation = args[0]
+		}
+
+		if workDirectory == "" {
+			workDirectory = filepath.Dir(manifestFileLocation)
+		}
+
+		problems, err := validateManifest(ctx, cmd, configuration, manifestFileLocation, workDirectory, offline)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				cmd.PrintErrln(bunt.Sprintf("Red{%s}", problem))
+			}
+
+			return fmt.Errorf("found %d problems in %s: %w", len(problems), manifestFileLocation, ErrInvalidManifest)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{manifest %s is valid}", manifestFileLocation))
+
+		return nil
+	}
+
+	return command
+}
+
+// validateManifest validates the manifest against its schema, dry-runs the matching of its files and validates its
+// deployment targets, yielding back all problems found.
+// An error is only returned if the validation itself failed.
+func validateManifest(
+	ctx context.Context,
+	cmd *cobra.Command,
+	configuration *Configuration,
+	manifestFileLocation, workDirectory string,
+	offline bool,
+) ([]error, error) {
+	buildInformation, _ := fetchProjectBuildInformation(cmd, workDirectory)
+
+	templatedManifestContent, err := readManifestFromDisk(cmd, manifestFileLocation, buildInformation)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.PrintErrln(bunt.Sprintf("Gray{validating manifest against its schema}"))
+	if err := filemodel.ManifestSchema().Validate(templatedManifestContent); err != nil {
+		return unwrapJoined(err), nil
+	}
+
+	var manifest filemodel.Manifest
+	if err := json.Unmarshal(templatedManifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	cmd.PrintErrln(bunt.Sprintf("Gray{matching files in %s}", workDirectory))
+	dryRuns, err := builder.DryRunArtefactFiles(os.DirFS(workDirectory), manifest)
+	problems := unwrapJoined(err)
+
+	for _, dryRun := range dryRuns {
+		cmd.Println(bunt.Sprintf("*%s* Gray{%s matched %d files}", dryRun.Reference.Target, dryRun.Reference.CISourceGlob, len(dryRun.Matches)))
+
+		for _, match := range slices.Sorted(maps.Keys(dryRun.Matches)) {
+			cmd.Println(bunt.Sprintf("  %s Gray{->} %s", match, dryRun.Matches[match]))
+		}
+	}
+
+	if offline || len(manifest.DeploymentTargets) == 0 {
+		return problems, nil
+	}
+
+	client, err := configuration.CreateTLSReadyHTTPClient()
+	if err != nil {
+		cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+	}
+
+	cmd.PrintErrln(bunt.Sprintf("Gray{validating deployment targets against the controller}"))
+	targetProblems, err := validateDeploymentTargets(ctx, client, manifest.DeploymentTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(problems, targetProblems...), nil
+}
+
+// validateDeploymentTargets validates that the controller knows all environments and servers targeted by the manifest.
+func validateDeploymentTargets(ctx context.Context, client controller.Client, targets filemodel.DeploymentTargets) ([]error, error) {
+	problems := make([]error, 0)
+
+	for _, environment := range slices.Sorted(maps.Keys(targets)) {
+		servers, err := client.FetchServers(ctx, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch servers of environment %s: %w", environment, err)
+		}
+
+		if len(servers) == 0 {
+			problems = append(problems, fmt.Errorf("environment %s: %w", environment, ErrUnknownDeploymentTarget))
+			continue
+		}
+
+		serverNames := make(map[string]struct{}, len(servers))
+		for _, server := range servers {
+			serverNames[server.Name] = struct{}{}
+		}
+
+		for _, serverName := range targets[environment] {
+			if _, found := serverNames[serverName]; !found {
+				problems = append(problems, fmt.Errorf("server %s in environment %s: %w", serverName, environment, ErrUnknownDeploymentTarget))
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// unwrapJoined yields back the errors joined into the passed error, the error itself if it is not joined or none if
+// the error is nil.
+func unwrapJoined(err error) []error {
+	if err == nil {
+		return make([]error, 0)
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
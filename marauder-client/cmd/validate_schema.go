@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/spf13/cobra"
+)
+
+// ValidateSchemaCommand constructs the command printing the json schema of the manifest.
+// The printed schema is generated from the manifest type, it is published with the client under schema/.
+func ValidateSchemaCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the json schema of the artefact manifest",
+		Args:  cobra.NoArgs,
+	}
+
+	command.RunE = func(cmd *cobra.Command, _ []string) error {
+		schema, err := json.MarshalIndent(filemodel.ManifestSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialise manifest schema: %w", err)
+		}
+
+		cmd.Println(string(schema))
+
+		return nil
+	}
+
+	return command
+}
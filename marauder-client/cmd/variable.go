@@ -0,0 +1,11 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// VariableCommand constructs the variable subcommand.
+func VariableCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "variable",
+		Short: "The parent command for the variables artefact files deployed as templates are rendered with",
+	}
+}
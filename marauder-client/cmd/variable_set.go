@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+// VariableSetCommand constructs the variable set subcommand.
+func VariableSetCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "set [reference] [key] [value]",
+		Short: "Sets the variable of the specified server, applied when its templates are deployed next",
+		Args:  cobra.ExactArgs(3),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		if err := client.SetServerVariable(ctx, serverUUID, args[1], args[2]); err != nil {
+			return fmt.Errorf("failed to set variable %s of %s: %w", args[1], args[0], err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{set variable %s of %s}", args[1], args[0]))
+
+		return nil
+	}
+
+	return command
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+)
+
+// VariableUnsetCommand constructs the variable unset subcommand.
+func VariableUnsetCommand(
+	ctx context.Context,
+	config *Configuration,
+) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "unset [reference] [key]",
+		Short: "Deletes the variable of the specified server",
+		Args:  cobra.ExactArgs(2),
+	}
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := config.CreateTLSReadyHTTPClient()
+		if err != nil {
+			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
+		}
+
+		serverUUID, err := client.ResolveServerReference(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to fetch server uuid: %w", err)
+		}
+
+		if err := client.DeleteServerVariable(ctx, serverUUID, args[1]); err != nil {
+			return fmt.Errorf("failed to unset variable %s of %s: %w", args[1], args[0], err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{unset variable %s of %s}", args[1], args[0]))
+
+		return nil
+	}
+
+	return command
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	"github.com/spf13/cobra"
+)
+
+// VerifyCommand constructs the verify command, verifying signatures of files offline against an authorized keys file.
+func VerifyCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "verify file [signatureFile]",
+		Short: "Verifies the signature of a file against the keys of an authorized keys file",
+		Long: "Verifies the signature of a file, read from the passed signature file or the file name suffixed with .sig, " +
+			"against the keys of an authorized keys file as used by the controller, including their validity windows and revocations.",
+		Args: cobra.RangeArgs(1, 2),
+	}
+
+	var authorizedKeysPath string
+	command.Flags().StringVarP(&authorizedKeysPath, "keys", "k", "", "the authorized keys file holding the trusted signing keys")
+	_ = command.MarkFlagRequired("keys")
+
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		signatureFileName := args[0] + ".sig"
+		if len(args) > 1 {
+			signatureFileName = args[1]
+		}
+
+		if _, err := os.Stat(filepath.Clean(authorizedKeysPath)); err != nil {
+			return fmt.Errorf("failed to find authorized keys file: %w", err)
+		}
+
+		registry, err := keyauth.NewRegistry(authorizedKeysPath)
+		if err != nil {
+			return fmt.Errorf("failed to load authorized keys: %w", err)
+		}
+
+		signature, err := os.ReadFile(filepath.Clean(signatureFileName))
+		if err != nil {
+			return fmt.Errorf("failed to read signature %s: %w", signatureFileName, err)
+		}
+
+		file, err := os.Open(filepath.Clean(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", args[0], err)
+		}
+
+		defer func() { _ = file.Close() }()
+
+		signer, err := registry.Verify(file, signature, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to verify signature of %s: %w", args[0], err)
+		}
+
+		cmd.PrintErrln(bunt.Sprintf("LimeGreen{good signature by %s (%s)}", signer.Name, signer.ID))
+
+		return nil
+	}
+
+	return command
+}
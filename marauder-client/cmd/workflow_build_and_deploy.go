@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/Goldziher/go-utils/sliceutils"
 	"github.com/gonvenience/bunt"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // WorkflowBuildAndDeployCommand constructs the workflow build and deploy subcommand.
@@ -19,7 +22,7 @@ func WorkflowBuildAndDeployCommand(
 	configuration *Configuration,
 ) *cobra.Command {
 	var (
-		manifestFileLocation       string
+		manifestFileLocations      []string
 		deploymentEnvironment      string
 		restartAffectedServers     bool
 		forceUpdateAffectedServers bool
@@ -28,11 +31,13 @@ func WorkflowBuildAndDeployCommand(
 
 	command := &cobra.Command{
 		Use:   "build-and-deploy [workdir]",
-		Short: "Builds, signs and pushes and deploys the local artefact found in the working directory",
+		Short: "Builds, signs and pushes and deploys the local artefacts found in the working directory",
 		Args:  cobra.MaximumNArgs(1),
 	}
 
-	command.PersistentFlags().StringVarP(&manifestFileLocation, "manifest", "m", ".marauder.json", "location of the manifest file")
+	command.PersistentFlags().StringSliceVarP(
+		&manifestFileLocations, "manifest", "m", []string{".marauder.json"}, "locations of the manifest files or directories of manifest files",
+	)
 	command.PersistentFlags().StringVarP(&deploymentEnvironment, "env", "e", "", "environment to deploy into")
 	command.PersistentFlags().BoolVar(&restartAffectedServers, "restart", false, "restart the servers deployed to")
 	command.PersistentFlags().BoolVar(&forceUpdateAffectedServers, "force", false, "forces an update to the server, overwriting local files")
@@ -40,7 +45,10 @@ func WorkflowBuildAndDeployCommand(
 
 	_ = command.MarkPersistentFlagRequired("env")
 
-	command.RunE = func(cmd *cobra.Command, args []string) error {
+	command.RunE = func(cmd *cobra.Command, args []string) (err error) {
+		ctx, span := tracing.Start(ctx, "workflow build-and-deploy", attribute.String("marauder.environment", deploymentEnvironment))
+		defer func() { tracing.End(span, err) }()
+
 		client, err := configuration.CreateTLSReadyHTTPClient()
 		if err != nil {
 			cmd.PrintErrln(bunt.Sprintf("#c43f43{failed to enable tls: %s}", err))
@@ -51,48 +59,67 @@ func WorkflowBuildAndDeployCommand(
 			workingDirectory = args[0]
 		}
 
-		defer func() { _ = os.Remove("output.tar") }()
-
+		_, buildSpan := tracing.Start(ctx, "build artefacts")
 		//nolint:contextcheck
-		if err := buildArtefactInternalExecute(cmd, configuration, manifestFileLocation, "output.tar", workingDirectory, true); err != nil {
-			return fmt.Errorf("failed to build and sign artefact: %w", err)
-		}
+		builds, err := buildArtefactsInternalExecute(
+			cmd, configuration, manifestFileLocations, "{{.Identifier}}-{{.Version}}-output.tar", workingDirectory, true,
+		)
+		tracing.End(buildSpan, err)
+
+		// Delete tarballs afterwards
+		defer func() {
+			for _, build := range builds {
+				_ = os.Remove(build.TarballFileLocation)
+				_ = os.Remove(build.TarballSignatureLocation)
+			}
+		}()
 
-		tarballLocation, valueFound := cmd.Context().Value(KeyBuildCommandTarballOutput).(TarballBuildResult)
-		if !valueFound {
-			return fmt.Errorf("failed to retrieve tarball location from build logic %v: %w", tarballLocation, ErrContextMissingValue)
+		if err != nil {
+			return fmt.Errorf("failed to build and sign artefacts: %w", err)
 		}
 
-		// Delete tarball afterwards
-		defer func() { _ = os.Remove(tarballLocation.TarballSignatureLocation) }()
-
-		// publish it
-		if err := publishArtefactInternalExecute(
-			ctx,
-			cmd,
-			client,
-			tarballLocation.TarballFileLocation,
-			tarballLocation.TarballSignatureLocation,
-		); err != nil {
-			return fmt.Errorf("failed to publish artefact to controller: %w", err)
+		// publish them, either all artefacts are published or none are.
+		publishedArtefacts, err := publishArtefactsInternalExecute(ctx, cmd, client, builds)
+		if err != nil {
+			return fmt.Errorf("failed to publish artefacts to controller: %w", err)
 		}
 
-		publishedArtefactModel, valueFound := cmd.Context().Value(KeyPublishResultArtefactModel).(networkmodel.ArtefactModel)
-		if !valueFound {
-			return fmt.Errorf("failed to retrieve published artefact from build logic %v: %w", publishedArtefactModel, ErrContextMissingValue)
+		statuses := make([]artefactStatus, 0, len(builds))
+		affectedServers := make([]string, 0)
+		for index, build := range builds {
+			status := artefactStatus{
+				ManifestLocation: build.ManifestLocation,
+				Identifier:       build.Manifest.Identifier,
+				Version:          build.Manifest.Version,
+				Stage:            artefactStagePublished,
+			}
+
+			serverTargets, err := workflowBuildAndDeployDeployPublishedArtefact(ctx, cmd, client, build, publishedArtefacts[index], deploymentEnvironment)
+			if err == nil {
+				status.Stage = artefactStageDeployed
+				affectedServers = append(affectedServers, serverTargets...)
+			}
+
+			status.Err = err
+			statuses = append(statuses, status)
 		}
 
-		if err := workflowBuildAndDeployDeployPublishedArtefact(
+		printArtefactStatuses(cmd, statuses)
+
+		// Servers targeted by multiple artefacts are only operated on once.
+		slices.Sort(affectedServers)
+		if err := operateServerInternalExecute(
 			ctx,
 			cmd,
 			client,
-			tarballLocation,
-			publishedArtefactModel,
-			deploymentEnvironment,
-			restartAffectedServers,
-			forceUpdateAffectedServers,
+			computeLifecycleAction(restartAffectedServers, forceUpdateAffectedServers),
 			delay,
+			slices.Compact(affectedServers),
 		); err != nil {
+			return fmt.Errorf("failed to upgrade affected servers: %w", err)
+		}
+
+		if err := artefactStatusErr(statuses); err != nil {
 			return fmt.Errorf("failed to deploy: %w", err)
 		}
 
@@ -102,7 +129,8 @@ func WorkflowBuildAndDeployCommand(
 	return command
 }
 
-// workflowBuildAndDeployDeployPublishedArtefact deploys a now published artefact to the configured servers and potentially restarts them.
+// workflowBuildAndDeployDeployPublishedArtefact deploys a now published artefact to the configured servers, yielding
+// back the servers deployed to.
 func workflowBuildAndDeployDeployPublishedArtefact(
 	ctx context.Context,
 	cmd *cobra.Command,
@@ -110,10 +138,7 @@ func workflowBuildAndDeployDeployPublishedArtefact(
 	artefact TarballBuildResult,
 	remoteArtefact networkmodel.ArtefactModel,
 	deploymentEnvironment string,
-	restartAffectedServers bool,
-	forceUpdateAffectedServers bool,
-	delay time.Duration,
-) error {
+) ([]string, error) {
 	serverTargets, valueFound := artefact.Manifest.DeploymentTargets[deploymentEnvironment]
 	if !valueFound {
 		serverTargets = make([]string, 0)
@@ -125,7 +150,7 @@ func workflowBuildAndDeployDeployPublishedArtefact(
 		return deploymentEnvironment + "/" + value
 	})
 
-	cmd.PrintErrln(bunt.Sprintf("Gray{deploying to servers: %v}", serverTargets))
+	cmd.PrintErrln(bunt.Sprintf("Gray{deploying %s to servers: %v}", remoteArtefact.Identifier, serverTargets))
 
 	if err := deployArtefactInternalExecute(
 		ctx,
@@ -137,20 +162,10 @@ func workflowBuildAndDeployDeployPublishedArtefact(
 		},
 		serverTargets,
 	); err != nil {
-		return fmt.Errorf("failed to deploy artefact to targeted servers: %w", err)
+		return nil, fmt.Errorf("failed to deploy artefact to targeted servers: %w", err)
 	}
 
-	if err := operateServerInternalExecute(
-		ctx,
-		cmd,
-		client,
-		computeLifecycleAction(restartAffectedServers, forceUpdateAffectedServers),
-		delay,
-		serverTargets,
-	); err != nil {
-		return fmt.Errorf("failed to upgrade affected servers: %w", err)
-	}
-	return nil
+	return serverTargets, nil
 }
 
 // computeLifecycleAction compute which lifecycle action should be applied to a server based on the passed flags.
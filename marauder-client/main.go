@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/knockturnmc/marauder/marauder-client/cmd"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 )
 
 func main() {
@@ -24,14 +25,28 @@ func main() {
 
 	getServerCommand := cmd.GetServerCommand(ctx, &configuration)
 	getServerCommand.AddCommand(cmd.GetServerStateCommand(ctx, &configuration))
+	getServerCommand.AddCommand(cmd.GetServerStatusCommand(ctx, &configuration))
 	getCommand.AddCommand(getServerCommand)
 
 	root.AddCommand(getCommand)
 
+	searchCommand := cmd.SearchCommand()
+	searchCommand.AddCommand(cmd.SearchArtefactsCommand(ctx, &configuration))
+	searchCommand.AddCommand(cmd.SearchServersCommand(ctx, &configuration))
+	root.AddCommand(searchCommand)
+
 	buildCommand := cmd.BuildCommand()
 	buildCommand.AddCommand(cmd.BuildArtefactCommand(&configuration))
 	root.AddCommand(buildCommand)
 
+	validateCommand := cmd.ValidateCommand()
+	validateCommand.AddCommand(cmd.ValidateManifestCommand(ctx, &configuration))
+	validateCommand.AddCommand(cmd.ValidateSchemaCommand())
+	root.AddCommand(validateCommand)
+
+	root.AddCommand(cmd.SignCommand(&configuration))
+	root.AddCommand(cmd.VerifyCommand())
+
 	publish := cmd.PublishCommand()
 	publish.AddCommand(cmd.PublishArtefactCommand(ctx, &configuration))
 	root.AddCommand(publish)
@@ -41,7 +56,9 @@ func main() {
 	root.AddCommand(deployCommand)
 
 	operateCommand := cmd.OperateCommand()
-	operateCommand.AddCommand(cmd.OperateServerCommand(ctx, &configuration))
+	operateServerCommand := cmd.OperateServerCommand(ctx, &configuration)
+	operateServerCommand.AddCommand(cmd.OperateServerPlanCommand(ctx, &configuration))
+	operateCommand.AddCommand(operateServerCommand)
 	root.AddCommand(operateCommand)
 
 	workflowCommand := cmd.WorkflowCommand()
@@ -54,9 +71,39 @@ func main() {
 	manageCommand.AddCommand(cmd.ManageServerToggleSaveCommand(ctx, &configuration))
 	root.AddCommand(manageCommand)
 
+	backupCommand := cmd.BackupCommand()
+	backupCommand.AddCommand(cmd.BackupCreateCommand(ctx, &configuration))
+	backupCommand.AddCommand(cmd.BackupListCommand(ctx, &configuration))
+	backupCommand.AddCommand(cmd.BackupRestoreCommand(ctx, &configuration))
+	root.AddCommand(backupCommand)
+
+	variableCommand := cmd.VariableCommand()
+	variableCommand.AddCommand(cmd.VariableListCommand(ctx, &configuration))
+	variableCommand.AddCommand(cmd.VariableSetCommand(ctx, &configuration))
+	variableCommand.AddCommand(cmd.VariableUnsetCommand(ctx, &configuration))
+	root.AddCommand(variableCommand)
+
+	checkCommand := cmd.CheckCommand()
+	checkCommand.AddCommand(cmd.CheckServerCommand(ctx, &configuration))
+	root.AddCommand(checkCommand)
+
+	diffCommand := cmd.DiffCommand()
+	diffCommand.AddCommand(cmd.DiffServerCommand(ctx, &configuration))
+	root.AddCommand(diffCommand)
+
+	root.AddCommand(cmd.LogsCommand(ctx, &configuration))
+	root.AddCommand(cmd.AttachCommand(ctx, &configuration))
+
 	root.SetOut(os.Stdout) // By default, the output should properly be printed to stdout.
 
-	if err := root.Execute(); err != nil {
-		log.Fatal(err)
+	executeErr := root.Execute()
+
+	// Flush all spans of the command before exiting.
+	if err := tracing.Shutdown(ctx); err != nil {
+		log.Println(err)
+	}
+
+	if executeErr != nil {
+		log.Fatal(executeErr)
 	}
 }
@@ -3,16 +3,20 @@ package builder
 import (
 	"archive/tar"
 	"compress/gzip"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/goreleaser/fileglob"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
@@ -20,12 +24,20 @@ import (
 // CreateArtefactTarball creates a new tar ball given a manifest at the specified target path.
 // The method takes a rootFs file system in which it resolves the ci globs.
 // The target path is relative to the current working directory.
+// Tarballs are reproducible, building the same manifest over the same files yields a byte-identical tarball. All files
+// are written with the timestamp of the build information, or the unix epoch if the manifest does not define any.
 func CreateArtefactTarball(rootFs fs.FS, manifest filemodel.Manifest, writer io.Writer) error {
 	tarballWriter, err := utils.NewFriendlyTarballWriterGZ(writer, gzip.BestCompression)
 	if err != nil {
 		return fmt.Errorf("faild to create friendly tarball writer: %w", err)
 	}
 
+	tarballWriter.WithReproducibleHeaders()
+
+	if manifest.BuildInformation != nil {
+		tarballWriter.WithModTime(manifest.BuildInformation.Timestamp)
+	}
+
 	defer func() { utils.SwallowClose(tarballWriter) }()
 
 	// Include files specified in tarball.
@@ -43,7 +55,7 @@ func CreateArtefactTarball(rootFs fs.FS, manifest filemodel.Manifest, writer io.
 
 	if err := tarballWriter.Write(serialisedManifest, tar.Header{
 		Name: pkg.ManifestFileName,
-		Mode: 0o777,
+		Mode: 0o644,
 	}); err != nil {
 		return fmt.Errorf("failed to write manifest to tarball: %w", err)
 	}
@@ -72,6 +84,10 @@ func IncludeArtefactFiles(
 		return true
 	})
 
+	if err := validateFileReferences(resolvedManifest.Files); err != nil {
+		return filemodel.Manifest{}, fmt.Errorf("failed to validate manifest files: %w", err)
+	}
+
 	// Add files defined in manifest
 	for idx, file := range resolvedManifest.Files {
 		matches, err := fileglob.Glob(file.CISourceGlob, fileglob.WithFs(rootFs))
@@ -79,6 +95,9 @@ func IncludeArtefactFiles(
 			return filemodel.Manifest{}, fmt.Errorf("failed to glob manifest defined file %s: %w", file.CISourceGlob, err)
 		}
 
+		// Write matches in a stable order, keeping the tarball reproducible.
+		slices.Sort(matches)
+
 		// Honour restriction if configured
 		if err := file.Restrictions.ValidateMatchAmount(len(matches)); err != nil {
 			return filemodel.Manifest{}, fmt.Errorf("failed file restriction for %s: %w", file.CISourceGlob, err)
@@ -97,6 +116,40 @@ func IncludeArtefactFiles(
 	return outputManifest, nil
 }
 
+// validateFileReferences validates the restrictions and the deployment configuration of all file references, including
+// that their equality and merge providers are known to the operators, failing the build rather than the deployment.
+func validateFileReferences(files filemodel.FileReferenceCollection) error {
+	if err := files.ValidateDeployments(); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	fileEqualityRegistry := fileeq.DefaultFileEqualityRegistry()
+	fileMergeRegistry := filemerge.DefaultFileMergeRegistry()
+	for _, file := range files {
+		if err := file.Restrictions.Validate(); err != nil {
+			return fmt.Errorf("invalid restrictions of %s: %w", file.Target, err)
+		}
+
+		if file.Deployment == nil {
+			continue
+		}
+
+		if file.Deployment.EqualityProvider != nil {
+			if _, err := fileEqualityRegistry.Lookup(*file.Deployment.EqualityProvider); err != nil {
+				return fmt.Errorf("invalid equality provider of %s: %w", file.Target, err)
+			}
+		}
+
+		if file.Deployment.MergeProvider != nil {
+			if _, found := fileMergeRegistry[*file.Deployment.MergeProvider]; !found {
+				return fmt.Errorf("invalid merge provider %s of %s: %w", *file.Deployment.MergeProvider, file.Target, filemerge.ErrUnknownFileMerge)
+			}
+		}
+	}
+
+	return nil
+}
+
 // includeMatchInTarball includes a single matched file in the rootFs in the tarball writer and the manifest.
 func includeMatchInTarball(
 	rootFs fs.FS,
@@ -111,7 +164,12 @@ func includeMatchInTarball(
 	}
 
 	pathInTarball := filepath.Join(file.Target, relativePath)
-	addedFiles, err := tarballWriter.Add(rootFs, match, pkg.FileParentDirectoryInArtefact+pathInTarball)
+	symlink, err := isPreservedSymlink(rootFs, file, match, pathInTarball)
+	if err != nil {
+		return err
+	}
+
+	addedFiles, err := addMatchToTarball(rootFs, tarballWriter, match, pathInTarball, symlink)
 	if err != nil {
 		return fmt.Errorf("failed to add file %s to tarball: %w", match, err)
 	}
@@ -120,6 +178,10 @@ func includeMatchInTarball(
 		file.MatchedFiles = make(map[string]string)
 	}
 
+	if file.MatchedFileMetadata == nil {
+		file.MatchedFileMetadata = make(map[string]filemodel.FileMetadata)
+	}
+
 	// Write matched files to manifest file
 	for _, addedFile := range addedFiles {
 		addedFileInTarball, ok := addedFile.PathInTarball.Get()
@@ -127,12 +189,109 @@ func includeMatchInTarball(
 			continue
 		}
 
-		hash, err := utils.ComputeSha256ForFile(rootFs, addedFile.PathInRootFS)
+		hash, metadata, err := describeIncludedFile(rootFs, addedFile.PathInRootFS, symlink)
 		if err != nil {
-			return fmt.Errorf("failed to compute hash for included file %s: %w", addedFile.PathInRootFS, err)
+			return err
+		}
+
+		if !symlink && file.Deployment != nil && file.Deployment.Template {
+			if err := validateFileTemplate(rootFs, addedFile.PathInRootFS); err != nil {
+				return err
+			}
 		}
 
 		file.MatchedFiles[addedFileInTarball] = hex.EncodeToString(hash)
+		file.MatchedFileMetadata[addedFileInTarball] = metadata
+	}
+
+	return nil
+}
+
+// isPreservedSymlink returns if the passed match is a symbolic link that the file reference includes as a link.
+// Such links have to point to a relative target inside the server folder, failing the build rather than the deployment.
+func isPreservedSymlink(rootFs fs.FS, file *filemodel.FileReference, match string, pathInTarball string) (bool, error) {
+	if !file.PreserveSymlinks {
+		return false, nil
+	}
+
+	stat, err := fs.Lstat(rootFs, match)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat matched file %s: %w", match, err)
+	}
+
+	if stat.Mode()&fs.ModeSymlink == 0 {
+		return false, nil
+	}
+
+	target, err := fs.ReadLink(rootFs, match)
+	if err != nil {
+		return false, fmt.Errorf("failed to read target of symlink %s: %w", match, err)
+	}
+
+	if !utils.IsLocalSymlinkTarget(strings.TrimPrefix(filepath.ToSlash(pathInTarball), "/"), target) {
+		return false, fmt.Errorf("symlink %s points to %s outside the server folder: %w", match, target, utils.ErrUnsafeSymlink)
+	}
+
+	return true, nil
+}
+
+// addMatchToTarball adds the matched file, folder or symbolic link to the tarball at the passed path in the files folder.
+func addMatchToTarball(
+	rootFs fs.FS,
+	tarballWriter utils.FriendlyTarballWriter,
+	match string,
+	pathInTarball string,
+	symlink bool,
+) ([]utils.WriteResult, error) {
+	if !symlink {
+		return tarballWriter.Add(rootFs, match, pkg.FileParentDirectoryInArtefact+pathInTarball) //nolint:wrapcheck
+	}
+
+	addedFile, err := tarballWriter.AddSymlink(rootFs, match, pkg.FileParentDirectoryInArtefact+pathInTarball)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return []utils.WriteResult{addedFile}, nil
+}
+
+// describeIncludedFile computes the hash and the metadata of a file included in the tarball.
+// The hash of a symbolic link included as a link is computed over its target.
+func describeIncludedFile(rootFs fs.FS, path string, symlink bool) ([]byte, filemodel.FileMetadata, error) {
+	if symlink {
+		target, err := fs.ReadLink(rootFs, path)
+		if err != nil {
+			return nil, filemodel.FileMetadata{}, fmt.Errorf("failed to read target of symlink %s: %w", path, err)
+		}
+
+		hash := sha256.Sum256([]byte(target))
+
+		return hash[:], filemodel.FileMetadata{Mode: 0o777, SymlinkTarget: &target}, nil
+	}
+
+	stat, err := fs.Stat(rootFs, path)
+	if err != nil {
+		return nil, filemodel.FileMetadata{}, fmt.Errorf("failed to stat included file %s: %w", path, err)
+	}
+
+	hash, err := utils.ComputeSha256ForFile(rootFs, path)
+	if err != nil {
+		return nil, filemodel.FileMetadata{}, fmt.Errorf("failed to compute hash for included file %s: %w", path, err)
+	}
+
+	// The mode is normalised like the header of the file, so the manifest does not depend on the umask of the checkout.
+	return hash, filemodel.FileMetadata{Mode: uint32(utils.NormalisedFileMode(stat.Mode()))}, nil
+}
+
+// validateFileTemplate validates that the file included as a template parses, failing the build rather than the deployment.
+func validateFileTemplate(rootFs fs.FS, path string) error {
+	content, err := fs.ReadFile(rootFs, path)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	if _, err := utils.ParseFileTemplate(path, string(content)); err != nil {
+		return fmt.Errorf("invalid template %s: %w", path, err)
 	}
 
 	return nil
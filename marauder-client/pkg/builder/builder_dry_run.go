@@ -0,0 +1,100 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/goreleaser/fileglob"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// The FileReferenceDryRun holds the files a single file reference of a manifest would include in an artefact.
+type FileReferenceDryRun struct {
+	// Reference is the file reference of the manifest.
+	Reference filemodel.FileReference
+
+	// Matches maps the files matched in the root file system to their path in the server folder.
+	Matches map[string]string
+}
+
+// DryRunArtefactFiles validates the file references of the manifest and resolves the files they would include in an
+// artefact without producing a tarball.
+// The dry run of every file reference is yielded back, the problems found across all file references are joined into
+// the returned error.
+func DryRunArtefactFiles(rootFs fs.FS, manifest filemodel.Manifest) ([]FileReferenceDryRun, error) {
+	if err := validateFileReferences(manifest.Files); err != nil {
+		return nil, fmt.Errorf("failed to validate manifest files: %w", err)
+	}
+
+	globCache := utils.NewShortestGlobPathCache()
+	dryRuns := make([]FileReferenceDryRun, 0, len(manifest.Files))
+	problems := make([]error, 0)
+
+	for _, file := range manifest.Files {
+		dryRun, err := dryRunFileReference(rootFs, globCache, file)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("file reference %s: %w", file.Target, err))
+		}
+
+		dryRuns = append(dryRuns, dryRun)
+	}
+
+	return dryRuns, errors.Join(problems...)
+}
+
+// dryRunFileReference resolves the files matched by a single file reference, validating its glob, its restrictions as
+// well as the matched symbolic links and templates like building the artefact would.
+func dryRunFileReference(rootFs fs.FS, globCache *utils.ShortestGlobPathCache, file filemodel.FileReference) (FileReferenceDryRun, error) {
+	dryRun := FileReferenceDryRun{Reference: file, Matches: make(map[string]string)}
+
+	if err := fileglob.ValidPattern(file.CISourceGlob); err != nil {
+		return dryRun, fmt.Errorf("invalid glob %s: %w", file.CISourceGlob, err)
+	}
+
+	matches, err := fileglob.Glob(file.CISourceGlob, fileglob.WithFs(rootFs))
+	if err != nil {
+		return dryRun, fmt.Errorf("failed to glob %s: %w", file.CISourceGlob, err)
+	}
+
+	if err := file.Restrictions.ValidateMatchAmount(len(matches)); err != nil {
+		return dryRun, fmt.Errorf("failed file restriction for %s: %w", file.CISourceGlob, err)
+	}
+
+	for _, match := range matches {
+		relativePath, err := computeRelativePath(globCache, &file, match)
+		if err != nil {
+			return dryRun, err
+		}
+
+		pathInServerFolder := filepath.Join(file.Target, relativePath)
+		symlink, err := isPreservedSymlink(rootFs, &file, match, pathInServerFolder)
+		if err != nil {
+			return dryRun, err
+		}
+
+		if !symlink && file.Deployment != nil && file.Deployment.Template {
+			if err := validateMatchedTemplates(rootFs, match); err != nil {
+				return dryRun, err
+			}
+		}
+
+		dryRun.Matches[match] = filepath.ToSlash(pathInServerFolder)
+	}
+
+	return dryRun, nil
+}
+
+// validateMatchedTemplates validates that the matched file, or all files in the matched folder, parse as templates.
+func validateMatchedTemplates(rootFs fs.FS, match string) error {
+	//nolint:wrapcheck
+	return fs.WalkDir(rootFs, match, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		return validateFileTemplate(rootFs, path)
+	})
+}
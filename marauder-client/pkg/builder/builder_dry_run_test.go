@@ -0,0 +1,83 @@
+package builder_test
+
+import (
+	"testing/fstest"
+
+	"github.com/knockturnmc/marauder/marauder-client/pkg/builder"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dry-running the artefact build", Label("unittest"), func() {
+	var rootFS fstest.MapFS
+
+	BeforeEach(func() {
+		rootFS = fstest.MapFS{
+			"spell-plugin/build/libs/spellcore-1.14.jar": &fstest.MapFile{Data: []byte("plugin")},
+			"config/spellcore/config.yml":                &fstest.MapFile{Data: []byte("motd: {{.Name}}")},
+			"config/spellcore/spells.yml":                &fstest.MapFile{Data: []byte("spells: []")},
+		}
+	})
+
+	It("should resolve the paths of matched files in the server folder", func() {
+		dryRuns, err := builder.DryRunArtefactFiles(rootFS, filemodel.Manifest{
+			Identifier: "spellcore",
+			Version:    "1.14",
+			Files: filemodel.FileReferenceCollection{
+				{Target: "plugins/spellcore.jar", CISourceGlob: "spell-plugin/build/libs/spellcore-*.jar"},
+				{Target: "plugins/spellcore/", CISourceGlob: "config/spellcore/*.yml", Restrictions: &filemodel.FileRestriction{Exact: new(2)}},
+			},
+		})
+
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(dryRuns).To(HaveLen(2))
+		Expect(dryRuns[0].Matches).To(Equal(map[string]string{
+			"spell-plugin/build/libs/spellcore-1.14.jar": "plugins/spellcore.jar",
+		}))
+		Expect(dryRuns[1].Matches).To(Equal(map[string]string{
+			"config/spellcore/config.yml": "plugins/spellcore/config.yml",
+			"config/spellcore/spells.yml": "plugins/spellcore/spells.yml",
+		}))
+	})
+
+	It("should reject inconsistent restrictions", func() {
+		_, err := builder.DryRunArtefactFiles(rootFS, filemodel.Manifest{
+			Files: filemodel.FileReferenceCollection{{
+				Target:       "plugins/spellcore/",
+				CISourceGlob: "config/spellcore/*.yml",
+				Restrictions: &filemodel.FileRestriction{Exact: new(2), Min: new(1)},
+			}},
+		})
+
+		Expect(err).To(MatchError(filemodel.ErrInconsistentFileRestriction))
+	})
+
+	It("should report the problems of all file references", func() {
+		_, err := builder.DryRunArtefactFiles(rootFS, filemodel.Manifest{
+			Files: filemodel.FileReferenceCollection{
+				{Target: "plugins/spellcore.jar", CISourceGlob: "spell-plugin/build/libs/[spellcore-*.jar"},
+				{Target: "plugins/spellcore/", CISourceGlob: "config/spellcore/*.yml", Restrictions: &filemodel.FileRestriction{Max: new(1)}},
+				{Target: "plugins/spellbook.jar", CISourceGlob: "spell-api/build/libs/*.jar", Restrictions: &filemodel.FileRestriction{Min: new(1)}},
+			},
+		})
+
+		Expect(err).To(MatchError(ContainSubstring("invalid glob spell-plugin/build/libs/[spellcore-*.jar")))
+		Expect(err).To(MatchError(filemodel.ErrMaxFileMatchesFailed))
+		Expect(err).To(MatchError(filemodel.ErrMinFileMatchesFailed))
+	})
+
+	It("should validate matched templates", func() {
+		rootFS["config/spellcore/config.yml"] = &fstest.MapFile{Data: []byte("motd: {{.Name")}
+
+		_, err := builder.DryRunArtefactFiles(rootFS, filemodel.Manifest{
+			Files: filemodel.FileReferenceCollection{{
+				Target:       "plugins/spellcore/",
+				CISourceGlob: "config/spellcore/*.yml",
+				Deployment:   &filemodel.FileDeployment{Template: true},
+			}},
+		})
+
+		Expect(err).To(MatchError(ContainSubstring("invalid template config/spellcore/config.yml")))
+	})
+})
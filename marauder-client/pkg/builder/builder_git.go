@@ -3,6 +3,7 @@ package builder
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -49,6 +50,16 @@ func FetchBuildInformation(path string) (filemodel.BuildInformation, error) {
 		buildSpecificVersionFromGit = buildSpecificVersionFromEnv
 	}
 
+	// The build is timestamped with the commit, building the same commit twice yields the same build information.
+	timestamp, found, err := SourceDateEpoch()
+	if err != nil {
+		return filemodel.BuildInformation{}, err
+	}
+
+	if !found {
+		timestamp = commit.Committer.When
+	}
+
 	information := filemodel.BuildInformation{
 		Repository:           remote.Config().URLs[0],
 		Branch:               branchFromGitRef,
@@ -56,9 +67,25 @@ func FetchBuildInformation(path string) (filemodel.BuildInformation, error) {
 		CommitEmail:          commit.Author.Email,
 		CommitHash:           commit.Hash.String(),
 		CommitMessage:        strings.TrimRight(commit.Message, "\n"),
-		Timestamp:            time.Now(),
+		Timestamp:            timestamp.UTC(),
 		BuildSpecificVersion: buildSpecificVersionFromGit,
 	}
 
 	return information, nil
 }
+
+// SourceDateEpoch yields back the timestamp defined by the SOURCE_DATE_EPOCH environment variable in seconds since the
+// unix epoch, reporting if it is defined.
+func SourceDateEpoch() (time.Time, bool, error) {
+	sourceDateEpoch, found := os.LookupEnv(pkg.SourceDateEpochEnvironment)
+	if !found {
+		return time.Time{}, false, nil
+	}
+
+	seconds, err := strconv.ParseInt(sourceDateEpoch, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to parse %s %s: %w", pkg.SourceDateEpochEnvironment, sourceDateEpoch, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), true, nil
+}
@@ -1,14 +1,17 @@
 package builder_test
 
 import (
+	"bytes"
 	"io/fs"
 	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils/mocks"
 
 	"github.com/knockturnmc/marauder/marauder-client/pkg/builder"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	. "github.com/onsi/ginkgo/v2"
@@ -54,5 +57,142 @@ var _ = Describe("Building the artefact", Label("unittest"), func() {
 				Expect(err).To(Not(HaveOccurred()))
 			})
 		})
+
+		Context("for templates", func() {
+			It("should reject templates that do not parse", func() {
+				rootFS["config/motd.yml"] = &fstest.MapFile{Data: []byte("motd: {{.Variables.motd")}
+
+				writer := mocks.NewMockFriendlyTarballWriter(GinkgoT())
+				writer.On("WithFilter", mock.Anything).Return(writer)
+				writer.On("Add", mock.Anything, "config/motd.yml", "files/plugins/motd/config.yml").
+					Return(okayTarballResponseSingleFile)
+
+				_, err := builder.IncludeArtefactFiles(&rootFS, filemodel.Manifest{
+					Identifier: "motd",
+					Version:    "1.0",
+					Files: filemodel.FileReferenceCollection{{
+						Target:       "plugins/motd/config.yml",
+						CISourceGlob: "config/motd.yml",
+						Deployment:   &filemodel.FileDeployment{Template: true},
+					}},
+				}, utils.NewShortestGlobPathCache(), writer)
+
+				Expect(err).To(MatchError(ContainSubstring("invalid template")))
+			})
+		})
+
+		Context("for symlinks", func() {
+			It("should include symlinks as links and record their metadata", func() {
+				rootFS["build/spellcore.jar"] = &fstest.MapFile{Data: []byte("libs/spellcore-1.14.jar"), Mode: fs.ModeSymlink | 0o777}
+				rootFS["build/libs/spellcore-1.14.jar"] = &fstest.MapFile{Data: []byte("plugin"), Mode: 0o644}
+
+				writer := mocks.NewMockFriendlyTarballWriter(GinkgoT())
+				writer.On("WithFilter", mock.Anything).Return(writer)
+				writer.On("AddSymlink", mock.Anything, "build/spellcore.jar", "files/plugins/spellcore.jar").
+					Return(utils.WriteResult{PathInRootFS: "build/spellcore.jar", PathInTarball: mo.Some("files/plugins/spellcore.jar")}, nil)
+
+				manifest, err := builder.IncludeArtefactFiles(&rootFS, filemodel.Manifest{
+					Identifier: "spellcore",
+					Version:    "1.14",
+					Files: filemodel.FileReferenceCollection{{
+						Target:           "plugins/spellcore.jar",
+						CISourceGlob:     "build/spellcore.jar",
+						PreserveSymlinks: true,
+					}},
+				}, utils.NewShortestGlobPathCache(), writer)
+
+				Expect(err).To(Not(HaveOccurred()))
+				Expect(manifest.Files[0].MatchedFileMetadata).To(HaveKeyWithValue(
+					"files/plugins/spellcore.jar",
+					HaveField("SymlinkTarget", HaveValue(Equal("libs/spellcore-1.14.jar"))),
+				))
+			})
+
+			It("should reject symlinks pointing outside the server folder", func() {
+				rootFS["build/libs/spellcore.jar"] = &fstest.MapFile{Data: []byte("../../spellcore-1.14.jar"), Mode: fs.ModeSymlink | 0o777}
+				rootFS["spellcore-1.14.jar"] = &fstest.MapFile{Data: []byte("plugin"), Mode: 0o644}
+
+				writer := mocks.NewMockFriendlyTarballWriter(GinkgoT())
+				writer.On("WithFilter", mock.Anything).Return(writer)
+
+				_, err := builder.IncludeArtefactFiles(&rootFS, filemodel.Manifest{
+					Identifier: "spellcore",
+					Version:    "1.14",
+					Files: filemodel.FileReferenceCollection{{
+						Target:           "plugins/spellcore.jar",
+						CISourceGlob:     "build/libs/spellcore.jar",
+						PreserveSymlinks: true,
+					}},
+				}, utils.NewShortestGlobPathCache(), writer)
+
+				Expect(err).To(MatchError(utils.ErrUnsafeSymlink))
+			})
+		})
+
+		Context("for deployment modes", func() {
+			It("should reject unknown deployment modes", func() {
+				writer := mocks.NewMockFriendlyTarballWriter(GinkgoT())
+				writer.On("WithFilter", mock.Anything).Return(writer)
+
+				_, err := builder.IncludeArtefactFiles(&rootFS, filemodel.Manifest{
+					Identifier: "spellcore",
+					Version:    "1.14",
+					Files: filemodel.FileReferenceCollection{{
+						Target:       "plugins/spellcore/config.yml",
+						CISourceGlob: "config.yml",
+						Deployment:   &filemodel.FileDeployment{Mode: new(filemodel.FileDeploymentMode("overwrite-sometimes"))},
+					}},
+				}, utils.NewShortestGlobPathCache(), writer)
+
+				Expect(err).To(MatchError(filemodel.ErrUnknownFileDeploymentMode))
+			})
+
+			It("should reject unknown equality providers", func() {
+				writer := mocks.NewMockFriendlyTarballWriter(GinkgoT())
+				writer.On("WithFilter", mock.Anything).Return(writer)
+
+				_, err := builder.IncludeArtefactFiles(&rootFS, filemodel.Manifest{
+					Identifier: "spellcore",
+					Version:    "1.14",
+					Files: filemodel.FileReferenceCollection{{
+						Target:       "plugins/spellcore/config.yml",
+						CISourceGlob: "config.yml",
+						Deployment:   &filemodel.FileDeployment{EqualityProvider: new("yaml?skip=config-version")},
+					}},
+				}, utils.NewShortestGlobPathCache(), writer)
+
+				Expect(err).To(MatchError(fileeq.ErrInvalidFileEqualityParameters))
+			})
+		})
+	})
+
+	Describe("creating the artefact tarball", func() {
+		buildTarball := func(modTime time.Time, mode fs.FileMode) []byte {
+			rootFS := fstest.MapFS{
+				"spell-plugin/build/libs/spellcore-1.14.jar": &fstest.MapFile{Data: []byte("plugin"), ModTime: modTime, Mode: mode},
+				"spell-plugin/config/config.yml":             &fstest.MapFile{Data: []byte("config"), ModTime: modTime, Mode: mode},
+				"spell-plugin/config/messages.yml":           &fstest.MapFile{Data: []byte("messages"), ModTime: modTime, Mode: mode},
+			}
+
+			var tarball bytes.Buffer
+			Expect(builder.CreateArtefactTarball(rootFS, filemodel.Manifest{
+				Identifier: "spellcore",
+				Version:    "1.14",
+				Files: filemodel.FileReferenceCollection{
+					{Target: "plugins/spellcore.jar", CISourceGlob: "spell-plugin/build/libs/spellcore-*.jar"},
+					{Target: "plugins/spellcore", CISourceGlob: "spell-plugin/config/*.yml"},
+				},
+				BuildInformation: &filemodel.BuildInformation{Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+			}, &tarball)).To(Succeed())
+
+			return tarball.Bytes()
+		}
+
+		It("should be reproducible regardless of file timestamps and permissions", func() {
+			first := buildTarball(time.Now(), 0o600)
+			second := buildTarball(time.Now().Add(time.Hour), 0o664)
+
+			Expect(first).To(Equal(second))
+		})
 	})
 })
@@ -0,0 +1,11 @@
+// Package schema publishes the json schemas of the files consumed by the marauder client.
+// The schemas are generated from their go types via `marauder validate schema` and committed, allowing editors to
+// validate files like the `.marauder.json` manifest by referencing the published schema.
+package schema
+
+import _ "embed"
+
+// Manifest holds the published json schema of the artefact manifest.
+//
+//go:embed manifest.schema.json
+var Manifest []byte
@@ -0,0 +1,41 @@
+package schema_test
+
+import (
+	"encoding/json"
+
+	"github.com/knockturnmc/marauder/marauder-client/schema"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("The published schemas", Label("unittest"), func() {
+	It("should match the schema generated from the manifest", func() {
+		generated, err := json.Marshal(filemodel.ManifestSchema())
+		Expect(err).To(Not(HaveOccurred()))
+
+		Expect(schema.Manifest).To(MatchJSON(generated), "regenerate the schema via scripts/generateCode.sh")
+	})
+
+	It("should accept valid manifests", func() {
+		Expect(filemodel.ManifestSchema().Validate([]byte(`{
+			"$schema": "https://raw.githubusercontent.com/KnockturnMC/marauder/main/marauder-client/schema/manifest.schema.json",
+			"identifier": "spellcore",
+			"version": "1.14",
+			"requiresRestart": true,
+			"files": [
+				{"target": "plugins/spellcore.jar", "ciSourceGlob": "build/libs/spellcore-*.jar", "restrictions": {"exact": 1}},
+				{"target": "plugins/spellcore/", "ciSourceGlob": "config/*.yml", "deployment": {"mode": "install-once"}}
+			],
+			"deploymentTargets": {"production": ["lobby"]}
+		}`))).To(Succeed())
+	})
+
+	It("should reject typos in the manifest", func() {
+		Expect(filemodel.ManifestSchema().Validate([]byte(`{
+			"identifier": "spellcore",
+			"version": "1.14",
+			"files": [{"target": "plugins/spellcore.jar", "ciSourceGlobs": "build/libs/spellcore-*.jar"}]
+		}`))).To(MatchError(ContainSubstring(`/files/0: unknown property "ciSourceGlobs"`)))
+	})
+})
@@ -14,6 +14,7 @@ import (
 	"github.com/knockturnmc/marauder/marauder-controller/internal/rest"
 	"github.com/knockturnmc/marauder/marauder-controller/pkg/cronjob"
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -50,6 +51,11 @@ func defaultConfiguration() rest.ServerConfiguration {
 				},
 				RemoveAfter: 7 * 24 * time.Hour,
 			},
+			DetectServerDrift: &cronjob.DetectServerDrift{
+				BaseCronjobConfiguration: cronjob.BaseCronjobConfiguration{
+					Every: time.Hour,
+				},
+			},
 		},
 	}
 }
@@ -78,6 +84,12 @@ func ServeCommand() *cobra.Command {
 				return fmt.Errorf("failed to parse configuration file %s: %w", configurationPath, err)
 			}
 		}
+		if err := tracing.Setup(cmd.Context(), "marauder-controller", version, configuration.Tracing); err != nil {
+			return fmt.Errorf("failed to setup tracing: %w", err)
+		}
+
+		defer func() { _ = tracing.Shutdown(context.Background()) }()
+
 		dependencies, err := rest.CreateServerDependencies(version, configuration)
 		if err != nil {
 			return fmt.Errorf("failed to create server dependencies: %w", err)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
 	"github.com/knockturnmc/marauder/marauder-controller/pkg/cronjob"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/operator"
 	"github.com/sirupsen/logrus"
@@ -30,12 +31,18 @@ type FetchedCronjob struct {
 type CronjobWorker struct {
 	DB                  *sqlm.DB
 	OperatorClientCache *operator.ClientCache
+	Metrics             *metrics.Metrics
 	cronjobs            map[cronjob.Type]*FetchedCronjob
 	timerResetChan      chan time.Duration
 }
 
 // NewCronjobWorker constructs a new job worker for the given database and configuration.
-func NewCronjobWorker(db *sqlm.DB, operatorClientCache *operator.ClientCache, executors map[cronjob.Type]CronjobExecutor) *CronjobWorker {
+func NewCronjobWorker(
+	db *sqlm.DB,
+	operatorClientCache *operator.ClientCache,
+	controllerMetrics *metrics.Metrics,
+	executors map[cronjob.Type]CronjobExecutor,
+) *CronjobWorker {
 	preparedCronjobs := make(map[cronjob.Type]*FetchedCronjob)
 	for cronjobType, executor := range executors {
 		preparedCronjobs[cronjobType] = &FetchedCronjob{
@@ -50,6 +57,7 @@ func NewCronjobWorker(db *sqlm.DB, operatorClientCache *operator.ClientCache, ex
 	return &CronjobWorker{
 		DB:                  db,
 		OperatorClientCache: operatorClientCache,
+		Metrics:             controllerMetrics,
 		cronjobs:            preparedCronjobs,
 	}
 }
@@ -106,7 +114,11 @@ func (j *CronjobWorker) runRunnableJobs(ctx context.Context) (time.Duration, err
 		}
 
 		// Execute the cronjob
-		if err := fetchedCronjob.Executor.Execute(ctx, j); err != nil {
+		executionStart := time.Now()
+		err := fetchedCronjob.Executor.Execute(ctx, j)
+		j.Metrics.ObserveCronjobExecution(cronjobType, err, executionStart)
+
+		if err != nil {
 			return 0, fmt.Errorf("failed to execute cronjob %s: %w", cronjobType, err)
 		}
 
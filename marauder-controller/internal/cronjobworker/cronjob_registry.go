@@ -42,6 +42,9 @@ func ComputeCronjobMap(configuration cronjob.CronjobsConfiguration) map[cronjob.
 			configuration.ExecuteScheduledLifecycleActions.Every,
 		)
 	}
+	if configuration.DetectServerDrift != nil {
+		result[cronjob.DetectServerDriftIdentifier] = DetectServerDrift(configuration.DetectServerDrift.Every)
+	}
 
 	return result
 }
@@ -0,0 +1,41 @@
+package cronjobworker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+)
+
+// DetectServerDrift constructs the cronjob executor that verifies the files of all servers against the artefacts in their
+// IS state on their operators and stores the resulting drift.
+// A server failing to be verified does not prevent the remaining servers from being verified.
+func DetectServerDrift(cooldown time.Duration) CronjobExecutor {
+	return SimpleCronjobExecutor{
+		cooldown: cooldown,
+		executionFunction: func(ctx context.Context, worker *CronjobWorker) error {
+			servers, err := access.FetchServers(ctx, worker.DB)
+			if err != nil {
+				return fmt.Errorf("failed to fetch known servers: %w", err)
+			}
+
+			serverErrors := make([]error, 0)
+			for _, server := range servers {
+				operatorClient := worker.OperatorClientCache.GetOrCreateFromRef(server.OperatorRef)
+				drift, err := operatorClient.FetchServerDrift(ctx, server.UUID)
+				if err != nil {
+					serverErrors = append(serverErrors, fmt.Errorf("failed to fetch drift of %s from %s: %w", server.UUID, server.OperatorIdentifier, err))
+					continue
+				}
+
+				if err := access.ReplaceServerDrift(ctx, worker.DB, drift); err != nil {
+					serverErrors = append(serverErrors, fmt.Errorf("failed to store drift of %s: %w", server.UUID, err))
+				}
+			}
+
+			return errors.Join(serverErrors...)
+		},
+	}
+}
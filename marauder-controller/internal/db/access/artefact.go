@@ -2,10 +2,12 @@ package access
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 )
 
@@ -47,37 +49,64 @@ func FetchArtefactVersions(ctx context.Context, db *sqlm.DB, identifier string)
 
 // InsertArtefact inserts an artefact model into the database.
 func InsertArtefact(ctx context.Context, db *sqlm.DB, model networkmodel.ArtefactModelWithBinary) (networkmodel.ArtefactModel, error) {
+	result, err := InsertArtefacts(ctx, db, []networkmodel.ArtefactModelWithBinary{model})
+	if err != nil {
+		return networkmodel.ArtefactModel{}, err
+	}
+
+	return result[0], nil
+}
+
+// InsertArtefacts inserts all passed artefacts into the database in a single transaction.
+// If any of the artefacts cannot be inserted, none of them are.
+func InsertArtefacts(ctx context.Context, db *sqlm.DB, models []networkmodel.ArtefactModelWithBinary) ([]networkmodel.ArtefactModel, error) {
 	transaction, err := db.Beginx()
 	if err != nil {
-		return networkmodel.ArtefactModel{}, fmt.Errorf("failed to begin insertion transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin insertion transaction: %w", err)
 	}
 
 	defer func() { _ = transaction.Rollback() }() // Rollback in case, this explodes. If Commit is called prior, this is a noop.
 
-	var result networkmodel.ArtefactModel
-	if err := transaction.NamedGetContext(
-		ctx, &result, `
-        INSERT INTO artefact (identifier, version, upload_date, requires_restart)
-        VALUES (:identifier, :version, :upload_date, :requires_restart)
-        RETURNING *;`,
-		&model,
-	); err != nil {
-		return networkmodel.ArtefactModel{}, fmt.Errorf("failed to insert artefact: %w", err)
-	}
+	results := make([]networkmodel.ArtefactModel, 0, len(models))
+	for _, model := range models {
+		var result networkmodel.ArtefactModel
+		if err := transaction.NamedGetContext(
+			ctx, &result, `
+            INSERT INTO artefact (
+                identifier, version, upload_date, requires_restart, signer_key_id, signer_name,
+                build_repository, build_branch, build_commit_hash, build_commit_user, build_commit_email
+            )
+            VALUES (
+                :identifier, :version, :upload_date, :requires_restart, :signer_key_id, :signer_name,
+                :build_repository, :build_branch, :build_commit_hash, :build_commit_user, :build_commit_email
+            )
+            RETURNING *;`,
+			&model,
+		); err != nil {
+			return nil, fmt.Errorf("failed to insert artefact %s-%s: %w", model.Identifier, model.Version, err)
+		}
+
+		manifest, err := marshalManifest(model.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := transaction.ExecContext(
+			ctx, `
+            INSERT INTO artefact_file (artefact, tarball, hash, signature, manifest) VALUES ($1, $2, $3, $4, $5);`,
+			result.UUID, model.TarballBlob, model.Hash, model.Signature, manifest,
+		); err != nil {
+			return nil, fmt.Errorf("failed to insert tarball into database for %s: %w", result.UUID, err)
+		}
 
-	if _, err := transaction.ExecContext(
-		ctx, `
-        INSERT INTO artefact_file (artefact, tarball, hash) VALUES ($1, $2, $3);`,
-		result.UUID, model.TarballBlob, model.Hash,
-	); err != nil {
-		return networkmodel.ArtefactModel{}, fmt.Errorf("failed to insert tarball into database for %s: %w", result.UUID, err)
+		results = append(results, result)
 	}
 
 	if err := transaction.Commit(); err != nil {
-		return networkmodel.ArtefactModel{}, fmt.Errorf("failed to commit insertion transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit insertion transaction: %w", err)
 	}
 
-	return result, nil
+	return results, nil
 }
 
 // FetchArtefactTarball fetches a full ArtefactModelWithBinary from the database including the full tarball binary.
@@ -93,6 +122,135 @@ func FetchArtefactTarball(ctx context.Context, db *sqlm.DB, uuid uuid.UUID) (net
 	return result, nil
 }
 
+// FetchArtefactWithHash locates a specific artefact based on its identifier and version in the database, including the
+// hash of its tarball but not the tarball itself.
+func FetchArtefactWithHash(ctx context.Context, db *sqlm.DB, identifier string, version string) (networkmodel.ArtefactModelWithBinary, error) {
+	var result networkmodel.ArtefactModelWithBinary
+	if err := db.GetContext(ctx, &result, `
+        SELECT artefact.*, hash FROM artefact
+            JOIN artefact_file af on artefact.uuid = af.artefact WHERE identifier = $1 AND version = $2
+        `, identifier, version); err != nil {
+		return networkmodel.ArtefactModelWithBinary{}, fmt.Errorf("failed to find artefact with hash: %w", err)
+	}
+
+	return result, nil
+}
+
+// FetchArtefactIntegrity fetches the hash and signature of the tarball of an artefact from the database.
+func FetchArtefactIntegrity(ctx context.Context, db *sqlm.DB, uuid uuid.UUID) (networkmodel.ArtefactIntegrity, error) {
+	var result networkmodel.ArtefactIntegrity
+	if err := db.GetContext(ctx, &result, `
+        SELECT hash, signature, upload_date FROM artefact
+            JOIN artefact_file af on artefact.uuid = af.artefact WHERE af.artefact = $1
+        `, uuid); err != nil {
+		return networkmodel.ArtefactIntegrity{}, fmt.Errorf("failed to fetch artefact integrity from database: %w", err)
+	}
+
+	return result, nil
+}
+
+// FetchArtefactManifest fetches the manifest stored for an artefact from the database.
+// The returned boolean is false if the artefact exists but its manifest was not yet stored.
+// sql.ErrNoRows is returned if no artefact exists with the passed uuid.
+func FetchArtefactManifest(ctx context.Context, db *sqlm.DB, uuid uuid.UUID) (filemodel.Manifest, bool, error) {
+	var manifestJSON []byte
+	if err := db.GetContext(ctx, &manifestJSON, `
+        SELECT manifest FROM artefact_file WHERE artefact = $1
+        `, uuid); err != nil {
+		return filemodel.Manifest{}, false, fmt.Errorf("failed to fetch artefact manifest from database: %w", err)
+	}
+
+	if manifestJSON == nil {
+		return filemodel.Manifest{}, false, nil
+	}
+
+	var manifest filemodel.Manifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return filemodel.Manifest{}, false, fmt.Errorf("failed to unmarshal stored manifest of artefact %s: %w", uuid, err)
+	}
+
+	return manifest, true, nil
+}
+
+// FetchArtefactsWithoutManifest queries the database for the uuids of all artefacts that were uploaded before their
+// manifest was stored. Artefacts whose manifest was marked as unreadable are not included.
+func FetchArtefactsWithoutManifest(ctx context.Context, db *sqlm.DB) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, 0)
+	if err := db.SelectContext(ctx, &result, `
+        SELECT artefact FROM artefact_file WHERE manifest IS NULL AND NOT manifest_unreadable
+        `); err != nil {
+		return nil, fmt.Errorf("failed to find artefacts without manifest: %w", err)
+	}
+
+	return result, nil
+}
+
+// MarkArtefactManifestUnreadable marks the manifest of an artefact as unreadable from its tarball, excluding the artefact
+// from future manifest backfills.
+func MarkArtefactManifestUnreadable(ctx context.Context, db *sqlm.DB, artefact uuid.UUID) error {
+	if _, err := db.ExecContext(ctx, `
+        UPDATE artefact_file SET manifest_unreadable = TRUE WHERE artefact = $1
+        `, artefact); err != nil {
+		return fmt.Errorf("failed to mark manifest of artefact %s as unreadable: %w", artefact, err)
+	}
+
+	return nil
+}
+
+// UpdateArtefactManifest stores the manifest of an artefact and the build information found in it.
+func UpdateArtefactManifest(ctx context.Context, db *sqlm.DB, artefact uuid.UUID, manifest filemodel.Manifest) error {
+	manifestJSON, err := marshalManifest(&manifest)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin manifest update transaction: %w", err)
+	}
+
+	defer func() { _ = transaction.Rollback() }() // Rollback in case, this explodes. If Commit is called prior, this is a noop.
+
+	if _, err := transaction.ExecContext(ctx, `
+        UPDATE artefact_file SET manifest = $2 WHERE artefact = $1
+        `, artefact, manifestJSON); err != nil {
+		return fmt.Errorf("failed to update manifest of artefact %s: %w", artefact, err)
+	}
+
+	build := networkmodel.ArtefactModel{UUID: artefact}.WithBuildInformation(manifest.BuildInformation)
+	if _, err := transaction.NamedExecContext(ctx, `
+        UPDATE artefact SET
+            build_repository = :build_repository,
+            build_branch = :build_branch,
+            build_commit_hash = :build_commit_hash,
+            build_commit_user = :build_commit_user,
+            build_commit_email = :build_commit_email
+        WHERE uuid = :uuid
+        `, build); err != nil {
+		return fmt.Errorf("failed to update build information of artefact %s: %w", artefact, err)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("failed to commit manifest update transaction: %w", err)
+	}
+
+	return nil
+}
+
+// marshalManifest marshals the passed manifest for its JSONB column, yielding back nil for a nil manifest.
+func marshalManifest(manifest *filemodel.Manifest) ([]byte, error) {
+	if manifest == nil {
+		return nil, nil
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	return manifestJSON, nil
+}
+
 // DeleteArtefact deletes an artefact from the database.
 func DeleteArtefact(ctx context.Context, db *sqlm.DB, uuid uuid.UUID) error {
 	if _, err := db.ExecContext(ctx, "DELETE FROM artefact WHERE uuid = $1;", uuid); err != nil {
@@ -0,0 +1,86 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// SearchArtefacts queries the database for all artefacts matching the passed search, newest first.
+func SearchArtefacts(ctx context.Context, db *sqlm.DB, search networkmodel.ArtefactSearch) ([]networkmodel.ArtefactModel, error) {
+	conditions, args := artefactSearchConditions(search, 0)
+
+	result := make([]networkmodel.ArtefactModel, 0)
+	if err := db.SelectContext(ctx, &result, `
+    SELECT artefact.* FROM artefact
+        JOIN artefact_file af ON artefact.uuid = af.artefact
+    WHERE `+conditions+`
+    ORDER BY artefact.upload_date DESC
+    `, args...); err != nil {
+		return result, fmt.Errorf("failed to search artefacts: %w", err)
+	}
+
+	return result, nil
+}
+
+// SearchServers queries the database for all servers whose passed state holds an artefact matching the search.
+func SearchServers(
+	ctx context.Context,
+	db *sqlm.DB,
+	search networkmodel.ArtefactSearch,
+	state networkmodel.ServerStateType,
+) ([]networkmodel.ServerModel, error) {
+	conditions, args := artefactSearchConditions(search, 1)
+
+	result := make([]networkmodel.ServerModel, 0)
+	if err := db.SelectContext(ctx, &result, `
+    SELECT * FROM server WHERE uuid IN (
+        SELECT server_state.server FROM server_state
+            JOIN artefact ON server_state.artefact_uuid = artefact.uuid
+            JOIN artefact_file af ON artefact.uuid = af.artefact
+        WHERE server_state.type = $1 AND `+conditions+`
+    )
+    ORDER BY environment, name
+    `, append([]any{state}, args...)...); err != nil {
+		return result, fmt.Errorf("failed to search servers: %w", err)
+	}
+
+	return fillServerModelRefsSlice(ctx, db, result)
+}
+
+// artefactSearchConditions builds the conditions of a query on the artefact and its artefact_file, aliased af, matching
+// the passed search. The arguments of the conditions are numbered after the passed amount of preceding arguments.
+// An empty search matches all artefacts.
+func artefactSearchConditions(search networkmodel.ArtefactSearch, precedingArgs int) (string, []any) {
+	conditions := []string{"TRUE"}
+	args := make([]any, 0)
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$arg", "$"+strconv.Itoa(precedingArgs+len(args))))
+	}
+
+	if search.CommitHash != "" {
+		// Commit hashes may be abbreviated, the caller is responsible to only pass hex characters.
+		addCondition("artefact.build_commit_hash LIKE $arg::VARCHAR || '%'", strings.ToLower(search.CommitHash))
+	}
+
+	if search.Branch != "" {
+		addCondition("artefact.build_branch = $arg", search.Branch)
+	}
+
+	if search.File != "" {
+		pathInTarball := pkg.FileParentDirectoryInArtefact + strings.TrimPrefix(path.Clean("/"+search.File), "/")
+		// The lax path tolerates manifests without files, which marshal their files as null.
+		addCondition("EXISTS (SELECT 1 FROM jsonb_path_query(af.manifest, 'lax $.files[*]') reference "+
+			"WHERE reference -> 'matchedFiles' -> $arg::VARCHAR IS NOT NULL)", pathInTarball)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,163 @@
+package access_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("searching artefacts on the db", Label("functiontest"), func() {
+	var (
+		spellcoreMain, spellcoreFeature networkmodel.ArtefactModel
+		server                          networkmodel.ServerModel
+	)
+
+	insertBuiltArtefact := func(version string, branch string, commitHash string, file string) networkmodel.ArtefactModel {
+		GinkgoHelper()
+
+		manifest := filemodel.Manifest{
+			Identifier: "spellcore",
+			Version:    version,
+			Files: filemodel.FileReferenceCollection{{
+				Target:       "plugins",
+				MatchedFiles: map[string]string{"files/" + file: "ebbc0ce59cea35533cfb2d63443fb3db650e9d263ba3f91aee70110a108a6ff9"},
+			}},
+			BuildInformation: &filemodel.BuildInformation{Branch: branch, CommitHash: commitHash, CommitUser: "Hermione"},
+		}
+
+		artefact := fullArtefact
+		artefact.Version = version
+		artefact.ArtefactModel = artefact.WithBuildInformation(manifest.BuildInformation)
+		artefact.Manifest = &manifest
+
+		inserted, err := access.InsertArtefact(context.Background(), databaseClient, artefact)
+		Expect(err).To(Not(HaveOccurred()))
+
+		return inserted
+	}
+
+	BeforeEach(func() {
+		databaseClient.MustExec("DELETE FROM server_operator; DELETE FROM server; DELETE FROM server_network; DELETE FROM artefact;")
+		databaseClient.MustExec(fmt.Sprintf(
+			"INSERT INTO server_operator VALUES ('%s', '%s', '%d')",
+			serverModel.OperatorIdentifier,
+			serverModel.OperatorRef.Host,
+			serverModel.OperatorRef.Port,
+		))
+
+		var err error
+		server, err = access.InsertServer(context.Background(), databaseClient, serverModel)
+		Expect(err).To(Not(HaveOccurred()))
+
+		spellcoreMain = insertBuiltArtefact("1.0.0", "main", "3f1c2a9e0b7d4c5a", "plugins/spellcore.jar")
+		spellcoreFeature = insertBuiltArtefact("1.1.0-feature", "feature/wands", "8d4e6b2c1a0f9e7d", "plugins/wands.jar")
+
+		_, err = access.InsertServerState(context.Background(), databaseClient, networkmodel.ServerArtefactStateModel{
+			Server:             server.UUID,
+			ArtefactIdentifier: spellcoreMain.Identifier,
+			ArtefactUUID:       spellcoreMain.UUID,
+			DefinitionDate:     time.Now(),
+			Type:               networkmodel.IS,
+		})
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	Context("when storing the manifest of an artefact", func() {
+		It("should record its build information", func() {
+			Expect(spellcoreMain.BuildBranch).To(Equal(new("main")))
+			Expect(spellcoreMain.BuildCommitHash).To(Equal(new("3f1c2a9e0b7d4c5a")))
+			Expect(spellcoreMain.BuildCommitUser).To(Equal(new("Hermione")))
+			Expect(spellcoreMain.BuildRepository).To(BeNil())
+		})
+
+		It("should serve the stored manifest", func() {
+			manifest, stored, err := access.FetchArtefactManifest(context.Background(), databaseClient, spellcoreMain.UUID)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(stored).To(BeTrue())
+			Expect(manifest.Version).To(Equal("1.0.0"))
+			Expect(manifest.BuildInformation.CommitHash).To(Equal("3f1c2a9e0b7d4c5a"))
+		})
+
+		It("should backfill artefacts uploaded without a stored manifest", func() {
+			databaseClient.MustExec("UPDATE artefact_file SET manifest = NULL WHERE artefact = $1", spellcoreFeature.UUID)
+
+			Expect(access.FetchArtefactsWithoutManifest(context.Background(), databaseClient)).To(ConsistOf(spellcoreFeature.UUID))
+
+			Expect(access.UpdateArtefactManifest(context.Background(), databaseClient, spellcoreFeature.UUID, filemodel.Manifest{
+				Identifier:       "spellcore",
+				Version:          "1.1.0-feature",
+				BuildInformation: &filemodel.BuildInformation{Branch: "feature/broomsticks"},
+			})).To(Succeed())
+
+			Expect(access.FetchArtefactsWithoutManifest(context.Background(), databaseClient)).To(BeEmpty())
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				Branch: "feature/broomsticks",
+			})).To(HaveExactElements(HaveField("UUID", spellcoreFeature.UUID)))
+		})
+
+		It("should not backfill artefacts whose manifest is unreadable again", func() {
+			databaseClient.MustExec("UPDATE artefact_file SET manifest = NULL WHERE artefact = $1", spellcoreFeature.UUID)
+
+			Expect(access.MarkArtefactManifestUnreadable(context.Background(), databaseClient, spellcoreFeature.UUID)).To(Succeed())
+			Expect(access.FetchArtefactsWithoutManifest(context.Background(), databaseClient)).To(BeEmpty())
+		})
+	})
+
+	Context("when searching artefacts", func() {
+		It("should find artefacts by an abbreviated commit hash", func() {
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				CommitHash: "3F1C2A",
+			})).To(HaveExactElements(HaveField("UUID", spellcoreMain.UUID)))
+		})
+
+		It("should find artefacts built from a commit hash in uppercase", func() {
+			spellcoreUppercase := insertBuiltArtefact("1.2.0", "main", "A7B9C3D1E5F2", "plugins/spellcore.jar")
+
+			Expect(spellcoreUppercase.BuildCommitHash).To(Equal(new("a7b9c3d1e5f2")))
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				CommitHash: "a7b9",
+			})).To(HaveExactElements(HaveField("UUID", spellcoreUppercase.UUID)))
+		})
+
+		It("should find artefacts by their branch", func() {
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				Branch: "feature/wands",
+			})).To(HaveExactElements(HaveField("UUID", spellcoreFeature.UUID)))
+		})
+
+		It("should find the artefacts owning a file", func() {
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				File: "/plugins/wands.jar",
+			})).To(HaveExactElements(HaveField("UUID", spellcoreFeature.UUID)))
+		})
+
+		It("should only find artefacts matching all filters", func() {
+			Expect(access.SearchArtefacts(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				Branch: "main",
+				File:   "plugins/wands.jar",
+			})).To(BeEmpty())
+		})
+	})
+
+	Context("when searching servers", func() {
+		It("should find the servers shipping an artefact in their state", func() {
+			servers, err := access.SearchServers(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				CommitHash: "3f1c",
+			}, networkmodel.IS)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(servers).To(HaveExactElements(HaveField("UUID", server.UUID)))
+		})
+
+		It("should not find servers shipping the artefact in another state", func() {
+			Expect(access.SearchServers(context.Background(), databaseClient, networkmodel.ArtefactSearch{
+				CommitHash: "3f1c",
+			}, networkmodel.TARGET)).To(BeEmpty())
+		})
+	})
+})
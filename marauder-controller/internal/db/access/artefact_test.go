@@ -69,6 +69,85 @@ var _ = Describe("managing artefacts on the db", Label("functiontest"), func() {
 			_, errOnDuplicateInsertion := access.InsertArtefact(context.Background(), databaseClient, fullArtefact)
 			Expect(errOnDuplicateInsertion).To(HaveOccurred())
 		})
+
+		It("should record the key that signed it", func() {
+			signedArtefact := fullArtefact
+			signedArtefact.SignerKeyID = new("SHA256:n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg")
+			signedArtefact.SignerName = new("ci@spellcore")
+
+			insertedArtefact, err := access.InsertArtefact(context.Background(), databaseClient, signedArtefact)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(insertedArtefact.SignerKeyID).To(Equal(signedArtefact.SignerKeyID))
+			Expect(insertedArtefact.SignerName).To(Equal(signedArtefact.SignerName))
+		})
+	})
+
+	Context("when fetching an artefact with its hash", func() {
+		It("should include the hash of its tarball", func() {
+			insertedArtefact, err := access.InsertArtefact(context.Background(), databaseClient, fullArtefact)
+			Expect(err).To(Not(HaveOccurred()))
+
+			artefact, err := access.FetchArtefactWithHash(context.Background(), databaseClient, fullArtefact.Identifier, fullArtefact.Version)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(artefact.ArtefactModel).To(BeEquivalentTo(insertedArtefact))
+			Expect(artefact.Hash).To(Equal(fullArtefact.Hash))
+			Expect(artefact.TarballBlob).To(BeNil())
+		})
+
+		It("should fail if the artefact does not exist", func() {
+			_, err := access.FetchArtefactWithHash(context.Background(), databaseClient, fullArtefact.Identifier, fullArtefact.Version)
+			Expect(err).To(MatchError(sql.ErrNoRows))
+		})
+	})
+
+	Context("when fetching the integrity of an artefact", func() {
+		It("should include the hash and signature of its tarball", func() {
+			signedArtefact := fullArtefact
+			signedArtefact.Signature = []byte("signature")
+
+			insertedArtefact, err := access.InsertArtefact(context.Background(), databaseClient, signedArtefact)
+			Expect(err).To(Not(HaveOccurred()))
+
+			integrity, err := access.FetchArtefactIntegrity(context.Background(), databaseClient, insertedArtefact.UUID)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(integrity.Hash).To(Equal(fullArtefact.Hash))
+			Expect(integrity.Signature).To(Equal(signedArtefact.Signature))
+			Expect(integrity.UploadDate).To(BeTemporally("~", insertedArtefact.UploadDate, time.Millisecond))
+		})
+
+		It("should fail if the artefact does not exist", func() {
+			_, err := access.FetchArtefactIntegrity(context.Background(), databaseClient, uuid.New())
+			Expect(err).To(MatchError(sql.ErrNoRows))
+		})
+	})
+
+	Context("inserting multiple artefacts into the database", func() {
+		It("should insert all of them", func() {
+			secondArtefact := fullArtefact
+			secondArtefact.Identifier = "spellbook"
+
+			artefacts, err := access.InsertArtefacts(context.Background(), databaseClient, []networkmodel.ArtefactModelWithBinary{
+				fullArtefact, secondArtefact,
+			})
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(artefacts).To(HaveLen(2))
+			Expect(artefacts[0].Identifier).To(Equal("spellcore"))
+			Expect(artefacts[1].Identifier).To(Equal("spellbook"))
+		})
+
+		It("should insert none of them if one fails", func() {
+			secondArtefact := fullArtefact
+			secondArtefact.Identifier = "spellbook"
+
+			_, err := access.InsertArtefacts(context.Background(), databaseClient, []networkmodel.ArtefactModelWithBinary{
+				secondArtefact, fullArtefact, fullArtefact,
+			})
+			Expect(err).To(HaveOccurred())
+
+			var count int
+			Expect(databaseClient.Get(&count, `SELECT count(*) FROM artefact`)).To(Succeed())
+			Expect(count).To(BeZero())
+		})
 	})
 
 	Context("when fetching a artefact based on its identifier and version", func() {
@@ -24,11 +24,17 @@ func FindServerTargetStateMissMatch(
 		TargetArtefact     *uuid.UUID `db:"target_artefact"`
 		TargetVersion      *string    `db:"target_version"`
 		RequiresRestart    bool       `db:"requires_restart"`
+		AllowFileConflicts bool       `db:"allow_file_conflicts"`
 	}
 
 	result := make([]DBArtefactVersionMissmatchModel, 0)
 	if err := db.SelectContext(ctx, &result, `
-		SELECT * FROM func_find_server_target_state_missmatches($1) WHERE ($2 OR NOT requires_restart)
+		SELECT missmatch.*, COALESCE(target.allow_file_conflicts, FALSE) AS allow_file_conflicts
+		FROM func_find_server_target_state_missmatches($1) missmatch
+			LEFT JOIN server_state target ON target.server = $1
+				AND target.type = 'TARGET'
+				AND target.artefact_identifier = missmatch.artefact_identifier
+		WHERE ($2 OR NOT missmatch.requires_restart)
 		`, server, requiresRestart); err != nil {
 		return nil, fmt.Errorf("failed to execute psql func to fetch missmatch: %w", err)
 	}
@@ -75,6 +81,7 @@ func FindServerTargetStateMissMatch(
 			return networkmodel.ArtefactVersionMissmatch{
 				ArtefactIdentifier: value.ArtefactIdentifier,
 				Missmatch:          missmatch,
+				AllowFileConflicts: value.AllowFileConflicts,
 			}
 		},
 	), nil
@@ -108,11 +115,32 @@ func UpdateDeployment(
 		return networkmodel.ServerArtefactStateModel{}, fmt.Errorf("unknown server state (%s): %w", model.Type, networkmodel.ErrUnknownServerState)
 	}
 
-	if err := db.NamedGetContext(ctx, &model, `
+	allowFileConflicts := model.AllowFileConflicts
+
+	transaction, err := db.Beginx()
+	if err != nil {
+		return networkmodel.ServerArtefactStateModel{}, fmt.Errorf("failed to begin server state transaction: %w", err)
+	}
+
+	defer func() { _ = transaction.Rollback() }() // Rollback in case, this explodes. If Commit is called prior, this is a noop.
+
+	if err := transaction.NamedGetContext(ctx, &model, `
 		SELECT * FROM func_create_server_state(:server, :artefact_identifier, :artefact_uuid, :type) 
 		`, model); err != nil {
 		return networkmodel.ServerArtefactStateModel{}, fmt.Errorf("failed to create new server state: %w", err)
 	}
 
+	if allowFileConflicts {
+		if err := transaction.GetContext(ctx, &model.AllowFileConflicts, `
+		UPDATE server_state SET allow_file_conflicts = TRUE WHERE uuid = $1 RETURNING allow_file_conflicts
+		`, model.UUID); err != nil {
+			return networkmodel.ServerArtefactStateModel{}, fmt.Errorf("failed to allow file conflicts of server state: %w", err)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return networkmodel.ServerArtefactStateModel{}, fmt.Errorf("failed to commit server state transaction: %w", err)
+	}
+
 	return model, nil
 }
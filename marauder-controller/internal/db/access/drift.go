@@ -0,0 +1,71 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// ReplaceServerDrift replaces the stored drift of the server the passed drift was computed for.
+func ReplaceServerDrift(ctx context.Context, db *sqlm.DB, drift networkmodel.ServerDrift) error {
+	transaction, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() { _ = transaction.Rollback() }() // Rollback in case, this explodes. If Commit is called prior, this is a noop.
+
+	if _, err := transaction.NamedExecContext(ctx, `
+            INSERT INTO server_drift_check (server, checked_at)
+            VALUES (:server, :checked_at)
+            ON CONFLICT (server) DO UPDATE SET checked_at = excluded.checked_at;
+            `, drift); err != nil {
+		return fmt.Errorf("failed to upsert drift check of %s: %w", drift.ServerUUID.String(), err)
+	}
+
+	if _, err := transaction.ExecContext(ctx, `
+            DELETE FROM server_drift_file WHERE server = $1
+            `, drift.ServerUUID); err != nil {
+		return fmt.Errorf("failed to delete previous drift of %s: %w", drift.ServerUUID.String(), err)
+	}
+
+	for _, file := range drift.Files {
+		file.Server = drift.ServerUUID
+
+		if _, err := transaction.NamedExecContext(ctx, `
+                INSERT INTO server_drift_file (server, artefact_identifier, artefact_uuid, artefact_version, path, kind, equality_provider, detail)
+                VALUES (:server, :artefact_identifier, :artefact_uuid, :artefact_version, :path, :kind, :equality_provider, :detail);
+                `, file); err != nil {
+			return fmt.Errorf("failed to insert drift of file %s: %w", file.Path, err)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("failed to commit drift transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FetchServerDrift fetches the last stored drift of the passed server.
+// sql.ErrNoRows is returned if the drift of the server was never checked.
+func FetchServerDrift(ctx context.Context, db *sqlm.DB, serverUUID uuid.UUID) (networkmodel.ServerDrift, error) {
+	var result networkmodel.ServerDrift
+	if err := db.GetContext(ctx, &result, `
+    SELECT * FROM server_drift_check WHERE server = $1
+    `, serverUUID); err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to find drift check of %s: %w", serverUUID.String(), err)
+	}
+
+	result.Files = make([]networkmodel.FileDrift, 0)
+	if err := db.SelectContext(ctx, &result.Files, `
+    SELECT * FROM server_drift_file WHERE server = $1 ORDER BY artefact_identifier, path
+    `, serverUUID); err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to fetch drifted files of %s: %w", serverUUID.String(), err)
+	}
+
+	return result, nil
+}
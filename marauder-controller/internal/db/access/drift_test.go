@@ -0,0 +1,78 @@
+package access_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managing server drift", Label("functiontest"), func() {
+	var (
+		server   networkmodel.ServerModel
+		artefact networkmodel.ArtefactModel
+		drift    networkmodel.ServerDrift
+	)
+
+	BeforeEach(func() {
+		databaseClient.MustExec("DELETE FROM server_operator; DELETE FROM server; DELETE FROM server_network; DELETE FROM artefact;")
+		databaseClient.MustExec(fmt.Sprintf(
+			"INSERT INTO server_operator VALUES ('%s', '%s', '%d')",
+			serverModel.OperatorIdentifier,
+			serverModel.OperatorRef.Host,
+			serverModel.OperatorRef.Port,
+		))
+
+		var err error
+		server, err = access.InsertServer(context.Background(), databaseClient, serverModel)
+		Expect(err).To(Not(HaveOccurred()))
+		artefact, err = access.InsertArtefact(context.Background(), databaseClient, fullArtefact)
+		Expect(err).To(Not(HaveOccurred()))
+
+		drift = networkmodel.ServerDrift{
+			ServerUUID: server.UUID,
+			CheckedAt:  time.Now().UTC().Truncate(time.Millisecond),
+			Files: []networkmodel.FileDrift{{
+				Server:             server.UUID,
+				ArtefactIdentifier: artefact.Identifier,
+				ArtefactUUID:       artefact.UUID,
+				ArtefactVersion:    artefact.Version,
+				Path:               "plugins/spellcore/config.yml",
+				Kind:               networkmodel.FileDriftModified,
+				EqualityProvider:   "hash",
+			}},
+		}
+	})
+
+	It("should fail to fetch the drift of a server that was never checked", func() {
+		_, err := access.FetchServerDrift(context.Background(), databaseClient, server.UUID)
+		Expect(err).To(MatchError(sql.ErrNoRows))
+	})
+
+	It("should store and fetch the drift of a server", func() {
+		Expect(access.ReplaceServerDrift(context.Background(), databaseClient, drift)).To(Succeed())
+
+		fetched, err := access.FetchServerDrift(context.Background(), databaseClient, server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(fetched.CheckedAt).To(BeTemporally("==", drift.CheckedAt))
+		Expect(fetched.Files).To(Equal(drift.Files))
+	})
+
+	It("should replace the previously stored drift of a server", func() {
+		Expect(access.ReplaceServerDrift(context.Background(), databaseClient, drift)).To(Succeed())
+
+		drift.CheckedAt = drift.CheckedAt.Add(time.Hour)
+		drift.Files = nil
+		Expect(access.ReplaceServerDrift(context.Background(), databaseClient, drift)).To(Succeed())
+
+		fetched, err := access.FetchServerDrift(context.Background(), databaseClient, server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(fetched.CheckedAt).To(BeTemporally("==", drift.CheckedAt))
+		Expect(fetched.Files).To(BeEmpty())
+	})
+})
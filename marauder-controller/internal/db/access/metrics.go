@@ -0,0 +1,78 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+)
+
+// EnvironmentMissmatchStatistics holds the statistics about missmatches between TARGET and IS state of servers in a single environment.
+type EnvironmentMissmatchStatistics struct {
+	// Environment is the environment the statistics were computed for.
+	Environment string `db:"environment"`
+
+	// MissmatchedServers is the amount of servers in the environment with at least one missmatch.
+	MissmatchedServers int64 `db:"missmatched_servers"`
+
+	// OldestMissmatch is the definition date of the oldest state that is part of a missmatch.
+	OldestMissmatch time.Time `db:"oldest_missmatch"`
+}
+
+// ScheduledLifecycleActionStatistics holds the statistics about the currently scheduled lifecycle actions.
+type ScheduledLifecycleActionStatistics struct {
+	// Pending is the amount of scheduled lifecycle actions that are scheduled to be executed in the future.
+	Pending int64 `db:"pending"`
+
+	// Overdue is the amount of scheduled lifecycle actions that should have been executed already.
+	// As executed actions are removed, these actions either failed to execute or are about to be executed.
+	Overdue int64 `db:"overdue"`
+}
+
+// ArtefactStorageStatistics holds the statistics about the artefacts stored in the database.
+type ArtefactStorageStatistics struct {
+	// Artefacts is the amount of artefacts stored.
+	Artefacts int64 `db:"artefacts"`
+
+	// SizeBytes is the total size of all stored artefact tarballs.
+	SizeBytes int64 `db:"size_bytes"`
+}
+
+// FetchEnvironmentMissmatchStatistics fetches the missmatch statistics of all environments that have at least one missmatch.
+func FetchEnvironmentMissmatchStatistics(ctx context.Context, db *sqlm.DB) ([]EnvironmentMissmatchStatistics, error) {
+	result := make([]EnvironmentMissmatchStatistics, 0)
+	if err := db.SelectContext(ctx, &result, `
+		SELECT * FROM func_find_environment_missmatch_statistics()
+		`); err != nil {
+		return nil, fmt.Errorf("failed to fetch environment missmatch statistics: %w", err)
+	}
+
+	return result, nil
+}
+
+// FetchScheduledLifecycleActionStatistics fetches the statistics about all scheduled lifecycle actions relative to the passed time.
+func FetchScheduledLifecycleActionStatistics(ctx context.Context, db *sqlm.DB, now time.Time) (ScheduledLifecycleActionStatistics, error) {
+	var result ScheduledLifecycleActionStatistics
+	if err := db.GetContext(ctx, &result, `
+		SELECT COUNT(*) FILTER ( WHERE time_of_execution >= $1 ) as pending,
+		       COUNT(*) FILTER ( WHERE time_of_execution < $1 )  as overdue
+		FROM scheduled_lifecycle_actions
+		`, now.UTC()); err != nil {
+		return ScheduledLifecycleActionStatistics{}, fmt.Errorf("failed to fetch scheduled lifecycle action statistics: %w", err)
+	}
+
+	return result, nil
+}
+
+// FetchArtefactStorageStatistics fetches the statistics about the artefacts stored in the database.
+func FetchArtefactStorageStatistics(ctx context.Context, db *sqlm.DB) (ArtefactStorageStatistics, error) {
+	var result ArtefactStorageStatistics
+	if err := db.GetContext(ctx, &result, `
+		SELECT COUNT(*) as artefacts, COALESCE(SUM(pg_column_size(tarball)), 0) as size_bytes FROM artefact_file
+		`); err != nil {
+		return ArtefactStorageStatistics{}, fmt.Errorf("failed to fetch artefact storage statistics: %w", err)
+	}
+
+	return result, nil
+}
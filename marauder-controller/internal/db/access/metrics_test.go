@@ -0,0 +1,69 @@
+package access_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("computing metric statistics", Label("functiontest"), func() {
+	var (
+		server   networkmodel.ServerModel
+		artefact networkmodel.ArtefactModel
+	)
+
+	BeforeEach(func() {
+		databaseClient.MustExec("DELETE FROM server_operator; DELETE FROM server; DELETE FROM server_network; DELETE FROM artefact;")
+		databaseClient.MustExec(fmt.Sprintf(
+			"INSERT INTO server_operator VALUES ('%s', '%s', '%d')",
+			serverModel.OperatorIdentifier,
+			serverModel.OperatorRef.Host,
+			serverModel.OperatorRef.Port,
+		))
+
+		var err error
+		server, err = access.InsertServer(context.Background(), databaseClient, serverModel)
+		Expect(err).To(Not(HaveOccurred()))
+		artefact, err = access.InsertArtefact(context.Background(), databaseClient, fullArtefact)
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	Context("when fetching environment missmatch statistics", func() {
+		It("should not yield environments without missmatches", func() {
+			statistics, err := access.FetchEnvironmentMissmatchStatistics(context.Background(), databaseClient)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(statistics).To(BeEmpty())
+		})
+
+		It("should yield environments with a target state not matching the is state", func() {
+			_, err := access.InsertServerState(context.Background(), databaseClient, networkmodel.ServerArtefactStateModel{
+				Server:             server.UUID,
+				ArtefactIdentifier: artefact.Identifier,
+				ArtefactUUID:       artefact.UUID,
+				DefinitionDate:     time.Now(),
+				Type:               networkmodel.TARGET,
+			})
+			Expect(err).To(Not(HaveOccurred()))
+
+			statistics, err := access.FetchEnvironmentMissmatchStatistics(context.Background(), databaseClient)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(statistics).To(HaveLen(1))
+			Expect(statistics[0].Environment).To(Equal(server.Environment))
+			Expect(statistics[0].MissmatchedServers).To(BeEquivalentTo(1))
+		})
+	})
+
+	Context("when fetching artefact storage statistics", func() {
+		It("should count the stored artefacts", func() {
+			statistics, err := access.FetchArtefactStorageStatistics(context.Background(), databaseClient)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(statistics.Artefacts).To(BeEquivalentTo(1))
+			Expect(statistics.SizeBytes).To(BeNumerically(">", 0))
+		})
+	})
+})
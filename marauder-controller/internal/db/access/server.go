@@ -35,6 +35,18 @@ func FetchServerByNameAndEnv(ctx context.Context, db *sqlm.DB, name string, envi
 	return fillServerModelRefs(ctx, db, result)
 }
 
+// FetchServers queries the database for all servers known to the controller.
+func FetchServers(ctx context.Context, db *sqlm.DB) ([]networkmodel.ServerModel, error) {
+	result := make([]networkmodel.ServerModel, 0)
+	if err := db.SelectContext(ctx, &result, `
+    SELECT * FROM server
+    `); err != nil {
+		return result, fmt.Errorf("failed to find servers: %w", err)
+	}
+
+	return fillServerModelRefsSlice(ctx, db, result)
+}
+
 // FetchServersByName queries the database for a collection of servers by their name.
 func FetchServersByName(ctx context.Context, db *sqlm.DB, name string) ([]networkmodel.ServerModel, error) {
 	result := make([]networkmodel.ServerModel, 0)
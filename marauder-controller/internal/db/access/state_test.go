@@ -105,4 +105,20 @@ var _ = Describe("managing server state", Label("functiontest"), func() {
 			})
 		}
 	})
+
+	Context("when deploying an artefact that was allowed to conflict with other artefacts", func() {
+		It("should store the allowance and expose it on the missmatch", func() {
+			serverState.ArtefactIdentifier = artefact.Identifier
+			serverState.AllowFileConflicts = true
+
+			state, err := access.UpdateDeployment(context.Background(), databaseClient, serverState)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(state.AllowFileConflicts).To(BeTrue())
+
+			missmatches, err := access.FindServerTargetStateMissMatch(context.Background(), databaseClient, server.UUID, true)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(missmatches).To(HaveLen(1))
+			Expect(missmatches[0].AllowFileConflicts).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,46 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// FetchServerVariables fetches all variables defined for the passed server.
+func FetchServerVariables(ctx context.Context, db *sqlm.DB, serverUUID uuid.UUID) ([]networkmodel.ServerVariable, error) {
+	result := make([]networkmodel.ServerVariable, 0)
+	if err := db.SelectContext(ctx, &result, `
+    SELECT * FROM server_variable WHERE server = $1 ORDER BY key
+    `, serverUUID); err != nil {
+		return nil, fmt.Errorf("failed to fetch variables of %s: %w", serverUUID.String(), err)
+	}
+
+	return result, nil
+}
+
+// UpsertServerVariable defines the passed variable for its server, replacing the value of an existing variable with the same key.
+func UpsertServerVariable(ctx context.Context, db *sqlm.DB, variable networkmodel.ServerVariable) error {
+	if _, err := db.NamedExecContext(ctx, `
+            INSERT INTO server_variable (server, key, value)
+            VALUES (:server, :key, :value)
+            ON CONFLICT (server, key) DO UPDATE SET value = excluded.value;
+            `, variable); err != nil {
+		return fmt.Errorf("failed to upsert variable %s of %s: %w", variable.Key, variable.Server.String(), err)
+	}
+
+	return nil
+}
+
+// DeleteServerVariable deletes the variable with the passed key of the server.
+func DeleteServerVariable(ctx context.Context, db *sqlm.DB, serverUUID uuid.UUID, key string) error {
+	if _, err := db.ExecContext(ctx, `
+		DELETE FROM server_variable WHERE server = $1 AND key = $2
+		`, serverUUID, key); err != nil {
+		return fmt.Errorf("failed to delete variable %s of %s: %w", key, serverUUID.String(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package access_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managing server variables", Label("functiontest"), func() {
+	var server networkmodel.ServerModel
+
+	BeforeEach(func() {
+		databaseClient.MustExec("DELETE FROM server_operator; DELETE FROM server; DELETE FROM server_network;")
+		databaseClient.MustExec(fmt.Sprintf(
+			"INSERT INTO server_operator VALUES ('%s', '%s', '%d')",
+			serverModel.OperatorIdentifier,
+			serverModel.OperatorRef.Host,
+			serverModel.OperatorRef.Port,
+		))
+
+		var err error
+		server, err = access.InsertServer(context.Background(), databaseClient, serverModel)
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	It("should store, replace and delete the variables of a server", func() {
+		Expect(access.UpsertServerVariable(context.Background(), databaseClient, networkmodel.ServerVariable{
+			Server: server.UUID, Key: "region", Value: "eu",
+		})).To(Succeed())
+		Expect(access.UpsertServerVariable(context.Background(), databaseClient, networkmodel.ServerVariable{
+			Server: server.UUID, Key: "motd", Value: "hello",
+		})).To(Succeed())
+		Expect(access.UpsertServerVariable(context.Background(), databaseClient, networkmodel.ServerVariable{
+			Server: server.UUID, Key: "region", Value: "us",
+		})).To(Succeed())
+
+		variables, err := access.FetchServerVariables(context.Background(), databaseClient, server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(variables).To(Equal([]networkmodel.ServerVariable{
+			{Server: server.UUID, Key: "motd", Value: "hello"},
+			{Server: server.UUID, Key: "region", Value: "us"},
+		}))
+
+		Expect(access.DeleteServerVariable(context.Background(), databaseClient, server.UUID, "motd")).To(Succeed())
+
+		variables, err = access.FetchServerVariables(context.Background(), databaseClient, server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(variables).To(Equal([]networkmodel.ServerVariable{{Server: server.UUID, Key: "region", Value: "us"}}))
+	})
+})
@@ -11,11 +11,15 @@ import (
 	"github.com/knockturnmc/marauder/marauder-controller/internal/rest/v1/endpoints"
 	"github.com/knockturnmc/marauder/marauder-controller/pkg/cronjob"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/middleware"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/ztrue/shutdown"
 )
 
+// signingKeyReloadInterval is the interval in which the authorized keys file of artefact signers is checked for changes.
+const signingKeyReloadInterval = 10 * time.Second
+
 // The ServerConfiguration struct holds relevant configuration values for the rest server.
 type ServerConfiguration struct {
 	Host string `yaml:"host"`
@@ -34,16 +38,23 @@ type ServerConfiguration struct {
 	TLS utils.TLSConfiguration `yaml:"tls"`
 
 	KnownClientKeysFile string `yaml:"knownClientKeysFile"`
+
+	// AllowConsoleAttachWithoutClientCertificate allows clients to attach to server consoles without authenticating via mutual tls.
+	// This should only be enabled if the controller is not reachable by untrusted clients.
+	AllowConsoleAttachWithoutClientCertificate bool `yaml:"allowConsoleAttachWithoutClientCertificate"`
+
+	Tracing tracing.Configuration `yaml:"tracing"`
 }
 
 // StartMarauderControllerServer starts the marauder controller server instance.
 func StartMarauderControllerServer(configuration ServerConfiguration, dependencies ServerDependencies) error {
 	server := gin.New()
+	server.ContextWithFallback = true // handlers pass the gin context on, which should yield the traced request context.
 	if err := server.SetTrustedProxies(nil); err != nil {
 		return fmt.Errorf("failed to set server trusted proxies: %w", err)
 	}
 
-	configureRouterGroup(server, dependencies)
+	configureRouterGroup(server, configuration, dependencies)
 
 	logrus.Info("staring server on port ", configuration.Port)
 	engine := &http.Server{
@@ -54,6 +65,8 @@ func StartMarauderControllerServer(configuration ServerConfiguration, dependenci
 	}
 
 	startCronjobWorker(dependencies)
+	startSigningKeyWatcher(dependencies)
+	startManifestBackfill(dependencies)
 
 	var serveErr error
 	if engine.TLSConfig != nil {
@@ -81,32 +94,75 @@ func startCronjobWorker(dependencies ServerDependencies) {
 	shutdown.Add(cronjobWorkerCancel) // shutdown worker on shutdown
 }
 
+// startSigningKeyWatcher starts hot-reloading the signing key registry passed in the server dependencies.
+func startSigningKeyWatcher(dependencies ServerDependencies) {
+	watcherContext, watcherCancel := context.WithCancel(context.Background())
+	go dependencies.SigningKeys.Watch(watcherContext, signingKeyReloadInterval)
+
+	shutdown.Add(watcherCancel) // stop watching on shutdown
+}
+
+// startManifestBackfill starts storing the manifests of artefacts uploaded before manifests were stored in the
+// background.
+func startManifestBackfill(dependencies ServerDependencies) {
+	backfillContext, backfillCancel := context.WithCancel(context.Background())
+	go func() {
+		if err := backfillArtefactManifests(backfillContext, dependencies.DatabaseHandle); err != nil {
+			logrus.Errorf("failed to backfill artefact manifests: %s", err)
+		}
+	}()
+
+	shutdown.Add(backfillCancel) // stop backfilling on shutdown
+}
+
 // configureRouterGroup configures the router for the engine, specifically all its endpoints.
-func configureRouterGroup(server *gin.Engine, dependencies ServerDependencies) {
+func configureRouterGroup(server *gin.Engine, configuration ServerConfiguration, dependencies ServerDependencies) {
 	logrus.Debug("registering middleware on gin server")
 	server.Use(gin.LoggerWithFormatter(middleware.RequestLoggerFormatter()))
 	server.Use(gin.Recovery())
 	server.Use(cors.Default())
+	server.Use(middleware.Tracing())
 	server.Use(middleware.ErrorHandler())
+	server.Use(dependencies.Metrics.Middleware())
 
 	logrus.Debug("registering routs on gin server")
+	server.GET("/metrics", gin.WrapH(dependencies.Metrics.Handler()))
+
 	group := server.Group("/v1")
 	group.GET("/version", endpoints.VersionGet(dependencies.Version))
 
-	group.POST("/artefact", endpoints.ArtefactUploadGet(dependencies.DatabaseHandle, dependencies.ArtefactValidator))
+	group.POST("/artefact", endpoints.ArtefactUploadGet(
+		dependencies.DatabaseHandle,
+		dependencies.ArtefactValidator,
+		dependencies.Metrics,
+	))
+	group.POST("/artefacts", endpoints.ArtefactsUploadPost(
+		dependencies.DatabaseHandle,
+		dependencies.ArtefactValidator,
+		dependencies.Metrics,
+	))
 	group.GET("/artefact/:uuid", endpoints.ArtefactUUIDGet(dependencies.DatabaseHandle))
 	group.GET("/artefact/:uuid/download", endpoints.ArtefactUUIDDownloadGet(dependencies.DatabaseHandle))
 	group.GET("/artefact/:uuid/download/manifest", endpoints.ArtefactUUIDDownloadManifestGet(dependencies.DatabaseHandle))
+	group.GET("/artefact/:uuid/integrity", endpoints.ArtefactUUIDIntegrityGet(dependencies.DatabaseHandle))
 	group.GET("/artefacts/:identifier", endpoints.ArtefactsIdentifierGet(dependencies.DatabaseHandle))
 	group.GET("/artefacts/:identifier/:version", endpoints.ArtefactIdentifierVersionGet(dependencies.DatabaseHandle))
+	group.GET("/search/artefacts", endpoints.SearchArtefactsGet(dependencies.DatabaseHandle))
+	group.GET("/search/servers", endpoints.SearchServersGet(dependencies.DatabaseHandle))
 
 	group.GET("/server/:uuid", endpoints.ServerUUIDGet(dependencies.DatabaseHandle))
+	group.GET("/server/:uuid/status", endpoints.ServerUUIDStatusGet(dependencies.DatabaseHandle, dependencies.OperatorClientCache))
+	group.GET("/server/:uuid/drift", endpoints.ServerUUIDDriftGet(dependencies.DatabaseHandle))
+	group.GET("/server/:uuid/variables", endpoints.ServerUUIDVariablesGet(dependencies.DatabaseHandle))
+	group.PUT("/server/:uuid/variables/:key", endpoints.ServerUUIDVariablePut(dependencies.DatabaseHandle))
+	group.DELETE("/server/:uuid/variables/:key", endpoints.ServerUUIDVariableDelete(dependencies.DatabaseHandle))
 	group.GET("/servers/:environment", endpoints.ServersEnvironmentGet(dependencies.DatabaseHandle))
 	group.GET("/servers/:environment/:name", endpoints.ServersEnvironmentNameGet(dependencies.DatabaseHandle))
 
 	group.GET("/server/:uuid/state/", endpoints.ServerStateGet(dependencies.DatabaseHandle))
 	group.GET("/server/:uuid/state/update", endpoints.ServerUpdate(dependencies.DatabaseHandle))
 	group.GET("/server/:uuid/state/:state", endpoints.ServerStateGet(dependencies.DatabaseHandle))
+	group.GET("/server/:uuid/state/:state/conflicts", endpoints.ServerStateConflictsGet(dependencies.DatabaseHandle))
 	group.PATCH("/server/:uuid/state/:state", endpoints.ServerDeploymentPatch(dependencies.DatabaseHandle))
 	group.DELETE("/server/:uuid/state/:state", endpoints.ServerDeploymentPatch(dependencies.DatabaseHandle))
 
@@ -115,6 +171,11 @@ func configureRouterGroup(server *gin.Engine, dependencies ServerDependencies) {
 		dependencies.OperatorClientCache,
 		dependencies.CronjobWorker,
 	))
+	group.GET("/operator/:server/attach", endpoints.OperationServerAttach(
+		dependencies.DatabaseHandle,
+		dependencies.OperatorClientCache,
+		configuration.AllowConsoleAttachWithoutClientCertificate,
+	))
 	group.Any("/operator/:server/proxy/*path", endpoints.OperationServerProxy(
 		dependencies.DatabaseHandle,
 		dependencies.OperatorClientCache,
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/artefact"
+	"github.com/sirupsen/logrus"
+)
+
+// backfillArtefactManifests reads the manifest of every artefact that does not have one stored from its tarball and
+// stores it alongside its build information.
+// Artefacts whose manifest cannot be read are marked as such, so they are not read again on every startup.
+// Tarballs are read one at a time, as they are held in memory in full.
+func backfillArtefactManifests(ctx context.Context, db *sqlm.DB) error {
+	artefacts, err := access.FetchArtefactsWithoutManifest(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to fetch artefacts to backfill: %w", err)
+	}
+
+	if len(artefacts) == 0 {
+		return nil
+	}
+
+	logrus.Infof("backfilling manifests of %d artefacts", len(artefacts))
+
+	for _, artefactUUID := range artefacts {
+		tarball, err := access.FetchArtefactTarball(ctx, db, artefactUUID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch tarball of artefact %s: %w", artefactUUID, err)
+		}
+
+		manifest, err := artefact.ReadManifestFromTarball(bytes.NewReader(tarball.TarballBlob))
+		if err != nil || manifest == nil {
+			// A single broken artefact should not prevent the others from being backfilled.
+			logrus.Warnf("failed to read manifest of artefact %s, skipping it: %v", artefactUUID, err)
+
+			if err := access.MarkArtefactManifestUnreadable(ctx, db, artefactUUID); err != nil {
+				return fmt.Errorf("failed to mark manifest of artefact %s as unreadable: %w", artefactUUID, err)
+			}
+
+			continue
+		}
+
+		if err := access.UpdateArtefactManifest(ctx, db, artefactUUID, *manifest); err != nil {
+			return fmt.Errorf("failed to store manifest of artefact %s: %w", artefactUUID, err)
+		}
+	}
+
+	logrus.Infof("backfilled manifests of %d artefacts", len(artefacts))
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/knockturnmc/marauder/marauder-controller/internal/cronjobworker"
 	"github.com/knockturnmc/marauder/marauder-controller/pkg/artefact"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/operator"
@@ -28,6 +29,9 @@ type ServerDependencies struct {
 	// The ArtefactValidator used by the server to validate uploaded artefacts.
 	ArtefactValidator artefact.Validator
 
+	// SigningKeys is the registry of keys the artefact validator accepts signatures of.
+	SigningKeys *keyauth.Registry
+
 	// CronjobWorker is the cronjob worker the controller server uses.
 	CronjobWorker *cronjobworker.CronjobWorker
 
@@ -36,6 +40,9 @@ type ServerDependencies struct {
 
 	// The TLSConfig for the server if tls is enabled.
 	TLSConfig *tls.Config
+
+	// Metrics holds the prometheus metrics exposed by the controller.
+	Metrics *metrics.Metrics
 }
 
 // CreateServerDependencies creates the server configuration for the server based on the configuration.
@@ -52,9 +59,9 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 	}
 
 	logrus.Debug("loading known public keys of artefact signers")
-	keys, err := keyauth.ParseKnownPublicKeys(configuration.KnownClientKeysFile)
+	keys, err := keyauth.NewRegistry(configuration.KnownClientKeysFile)
 	if err != nil {
-		return ServerDependencies{}, fmt.Errorf("failed to parse authorizsed keys: %w", err)
+		return ServerDependencies{}, fmt.Errorf("failed to load signing key registry: %w", err)
 	}
 
 	logrus.Debug("connecting to database")
@@ -88,9 +95,20 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 		tlsConfiguration.ClientCAs = tlsConfiguration.RootCAs
 	}
 
+	logrus.Debug("registering metrics")
+	controllerMetrics, err := metrics.NewMetrics()
+	if err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to create metrics: %w", err)
+	}
+
+	if err := controllerMetrics.Register(metrics.NewDatabaseCollector(wrappedDatabaseHandle)); err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
 	cronjobWorker := cronjobworker.NewCronjobWorker(
 		wrappedDatabaseHandle,
 		operatorClientCache,
+		controllerMetrics,
 		cronjobworker.ComputeCronjobMap(configuration.Cronjobs),
 	)
 
@@ -98,8 +116,10 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 		Version:             version,
 		DatabaseHandle:      wrappedDatabaseHandle,
 		ArtefactValidator:   artefact.NewWorkedBasedValidator(artefactValidatorDispatcher, keys),
+		SigningKeys:         keys,
 		OperatorClientCache: operatorClientCache,
 		CronjobWorker:       cronjobWorker,
 		TLSConfig:           tlsConfiguration,
+		Metrics:             controllerMetrics,
 	}, nil
 }
@@ -1,9 +1,13 @@
 package endpoints
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,7 +16,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
 	"github.com/knockturnmc/marauder/marauder-controller/pkg/artefact"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	libMetrics "github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
@@ -22,8 +28,14 @@ import (
 func ArtefactUploadGet(
 	db *sqlm.DB,
 	validator artefact.Validator,
+	controllerMetrics *metrics.Metrics,
 ) gin.HandlerFunc {
 	return func(context *gin.Context) {
+		uploadStart := time.Now()
+		outcome := libMetrics.OutcomeFailure
+
+		defer func() { controllerMetrics.ObserveArtefactUpload(outcome, time.Since(uploadStart)) }()
+
 		pathToArtefact, err := saveUploadInto(context, "artefact", os.TempDir()+"/marauder", "artefact-*.tar.gz")
 		if err != nil {
 			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to save artefact file: %w", err)))
@@ -40,36 +52,26 @@ func ArtefactUploadGet(
 
 		defer func() { _ = os.Remove(pathToSignature) }()
 
-		validationResult := <-validator.SubmitArtefact(pathToArtefact, pathToSignature)
-		if validationResult.Err != nil {
-			_ = context.Error(response.RestErrorFromErr(
-				http.StatusBadRequest, fmt.Errorf("uploaded artefact did not validate: %w", validationResult.Err),
-			))
+		artefactModel, restErr := validateUploadedArtefact(validator, controllerMetrics, pathToArtefact, pathToSignature)
+		if restErr != nil {
+			_ = context.Error(restErr)
+			return
+		}
 
+		existingArtefact, found, restErr := findUploadedArtefact(context, db, artefactModel)
+		if restErr != nil {
+			_ = context.Error(restErr)
 			return
 		}
 
-		artefactBytes, err := os.ReadFile(filepath.Clean(pathToArtefact))
-		if err != nil {
-			_ = context.Error(response.RestErrorFromErr(
-				http.StatusInternalServerError,
-				fmt.Errorf("failed to read entire artefact file into memory: %w", err),
-			))
+		if found {
+			outcome = libMetrics.OutcomeSuccess
+			context.JSONP(http.StatusOK, existingArtefact)
 
 			return
 		}
 
-		manifest := validationResult.Value.Manifest
-		insertArtefact, err := access.InsertArtefact(context, db, networkmodel.ArtefactModelWithBinary{
-			ArtefactModel: networkmodel.ArtefactModel{
-				Identifier:      manifest.Identifier,
-				Version:         manifest.Version,
-				UploadDate:      time.Now(),
-				RequiresRestart: utils.OrElse(manifest.RequiresRestart, true),
-			},
-			TarballBlob: artefactBytes,
-			Hash:        validationResult.Value.ArtefactHash,
-		})
+		insertArtefact, err := access.InsertArtefact(context, db, artefactModel)
 		if err != nil {
 			_ = context.Error(response.RestErrorFrom(
 				access.RestErrFromAccessErr(err),
@@ -80,10 +82,83 @@ func ArtefactUploadGet(
 			return
 		}
 
+		outcome = libMetrics.OutcomeSuccess
 		context.JSONP(http.StatusOK, insertArtefact)
 	}
 }
 
+// validateUploadedArtefact validates the uploaded artefact against its signature and reads it into the artefact model
+// inserted into the database.
+func validateUploadedArtefact(
+	validator artefact.Validator,
+	controllerMetrics *metrics.Metrics,
+	pathToArtefact, pathToSignature string,
+) (networkmodel.ArtefactModelWithBinary, *response.RestRequestError) {
+	validationStart := time.Now()
+	validationResult := <-validator.SubmitArtefact(pathToArtefact, pathToSignature)
+	controllerMetrics.ObserveArtefactValidation(validationResult.Err, time.Since(validationStart))
+
+	if validationResult.Err != nil {
+		return networkmodel.ArtefactModelWithBinary{}, response.RestErrorFromErr(
+			http.StatusBadRequest, fmt.Errorf("uploaded artefact did not validate: %w", validationResult.Err),
+		)
+	}
+
+	artefactBytes, err := os.ReadFile(filepath.Clean(pathToArtefact))
+	if err != nil {
+		return networkmodel.ArtefactModelWithBinary{}, response.RestErrorFromErr(
+			http.StatusInternalServerError,
+			fmt.Errorf("failed to read entire artefact file into memory: %w", err),
+		)
+	}
+
+	manifest := validationResult.Value.Manifest
+
+	return networkmodel.ArtefactModelWithBinary{
+		ArtefactModel: networkmodel.ArtefactModel{
+			Identifier:      manifest.Identifier,
+			Version:         manifest.Version,
+			UploadDate:      time.Now(),
+			RequiresRestart: utils.OrElse(manifest.RequiresRestart, true),
+			SignerKeyID:     &validationResult.Value.Signer.ID,
+			SignerName:      &validationResult.Value.Signer.Name,
+		}.WithBuildInformation(manifest.BuildInformation),
+		TarballBlob: artefactBytes,
+		Hash:        validationResult.Value.ArtefactHash,
+		Signature:   validationResult.Value.Signature,
+		Manifest:    &manifest,
+	}, nil
+}
+
+// findUploadedArtefact looks up an already uploaded artefact with the identifier and version of the passed artefact.
+// An existing artefact with an identical hash is yielded back, making repeated uploads of a reproducible artefact
+// idempotent, while an existing artefact with a different hash is a conflict.
+func findUploadedArtefact(
+	ctx context.Context,
+	db *sqlm.DB,
+	artefactModel networkmodel.ArtefactModelWithBinary,
+) (networkmodel.ArtefactModel, bool, *response.RestRequestError) {
+	existingArtefact, err := access.FetchArtefactWithHash(ctx, db, artefactModel.Identifier, artefactModel.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return networkmodel.ArtefactModel{}, false, nil
+		}
+
+		return networkmodel.ArtefactModel{}, false, response.RestErrorFromErr(
+			http.StatusInternalServerError, fmt.Errorf("failed to look up existing artefact: %w", err),
+		)
+	}
+
+	if !bytes.Equal(existingArtefact.Hash, artefactModel.Hash) {
+		return networkmodel.ArtefactModel{}, false, response.RestErrorFromDescription(
+			http.StatusConflict,
+			fmt.Sprintf("artefact %s-%s already exists with a different hash", artefactModel.Identifier, artefactModel.Version),
+		)
+	}
+
+	return existingArtefact.ArtefactModel, true, nil
+}
+
 // saveUploadInto saves the artefact passed into the parent path using the passed pattern as a file name
 // which will be expanded by os.CreateTemp.
 // The method returns the full path to the saved file.
@@ -93,6 +168,13 @@ func saveUploadInto(context *gin.Context, formName string, parentPath string, pa
 		return "", fmt.Errorf("failed to find form file for form name %s: %w", formName, err)
 	}
 
+	return saveFormFileInto(header, parentPath, pattern)
+}
+
+// saveFormFileInto saves the passed uploaded form file into the parent path using the passed pattern as a file name,
+// which will be expanded by os.CreateTemp.
+// The method returns the full path to the saved file.
+func saveFormFileInto(header *multipart.FileHeader, parentPath string, pattern string) (string, error) {
 	open, err := header.Open()
 	if err != nil {
 		return "", fmt.Errorf("failed to open multipart upload: %w", err)
@@ -2,7 +2,9 @@ package endpoints
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -16,6 +18,9 @@ import (
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
 )
 
+// errTarballWithoutManifest is returned if the tarball of an artefact does not contain a manifest.
+var errTarballWithoutManifest = errors.New("tarball without manifest")
+
 // ArtefactUUIDDownloadManifestGet creates the get endpoint that may be used to download the manifest of an artefact from the controller.
 func ArtefactUUIDDownloadManifestGet(
 	db *sqlm.DB,
@@ -28,33 +33,48 @@ func ArtefactUUIDDownloadManifestGet(
 			return
 		}
 
-		tarball, err := access.FetchArtefactTarball(context, db, artefactID)
+		manifest, err := fetchArtefactManifest(context, db, artefactID)
 		if err != nil {
 			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
 				sql.ErrNoRows: {ResponseCode: http.StatusNotFound, Description: "could not find artefact " + artefactID.String()},
-			}, fmt.Errorf("failed to fetch artefact: %w", err)))
+			}, fmt.Errorf("failed to fetch manifest: %w", err)))
 
 			return
 		}
 
-		manifest, err := readManifestFromTarball(&tarball)
-		if err != nil {
-			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to read amnifest from artefact: %w", err)))
-			return
-		}
+		context.JSON(http.StatusOK, manifest)
+	}
+}
 
-		// Tarball simply did not contain manifest
-		if manifest == nil {
-			_ = context.Error(response.RestErrorFromDescription(
-				http.StatusInternalServerError,
-				fmt.Sprintf("artefact %s did not contain manifest file!", artefactID),
-			))
+// fetchArtefactManifest fetches the manifest stored for the artefact.
+// The manifest of artefacts uploaded before manifests were stored is read from their tarball until backfilled.
+// sql.ErrNoRows is returned if no artefact exists with the passed uuid.
+func fetchArtefactManifest(ctx context.Context, db *sqlm.DB, artefactID uuid.UUID) (filemodel.Manifest, error) {
+	manifest, stored, err := access.FetchArtefactManifest(ctx, db, artefactID)
+	if err != nil {
+		return filemodel.Manifest{}, fmt.Errorf("failed to fetch stored manifest: %w", err)
+	}
 
-			return
-		}
+	if stored {
+		return manifest, nil
+	}
 
-		context.JSON(http.StatusOK, manifest)
+	tarball, err := access.FetchArtefactTarball(ctx, db, artefactID)
+	if err != nil {
+		return filemodel.Manifest{}, fmt.Errorf("failed to fetch artefact: %w", err)
+	}
+
+	tarballManifest, err := readManifestFromTarball(&tarball)
+	if err != nil {
+		return filemodel.Manifest{}, fmt.Errorf("failed to read amnifest from artefact: %w", err)
 	}
+
+	// Tarball simply did not contain manifest
+	if tarballManifest == nil {
+		return filemodel.Manifest{}, fmt.Errorf("artefact %s: %w", artefactID, errTarballWithoutManifest)
+	}
+
+	return *tarballManifest, nil
 }
 
 // readManifestFromTarball reads the manifest from the passed artefact model with binary.
@@ -0,0 +1,39 @@
+package endpoints
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ArtefactUUIDIntegrityGet creates the get endpoint serving the hash and signature of an artefact's tarball, allowing
+// downloaders to verify the tarball themselves.
+func ArtefactUUIDIntegrityGet(
+	db *sqlm.DB,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		artefactUUID := context.Param("uuid")
+		artefactID, err := uuid.Parse(artefactUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		integrity, err := access.FetchArtefactIntegrity(context, db, artefactID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				sql.ErrNoRows: {ResponseCode: http.StatusNotFound, Description: "could not find artefact " + artefactID.String()},
+			}, fmt.Errorf("failed to fetch artefact integrity: %w", err)))
+
+			return
+		}
+
+		context.JSONP(http.StatusOK, integrity)
+	}
+}
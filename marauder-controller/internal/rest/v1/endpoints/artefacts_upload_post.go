@@ -0,0 +1,131 @@
+package endpoints
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/artefact"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/metrics"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	libMetrics "github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ArtefactsUploadPost creates the upload endpoint to which multiple artefacts can be uploaded at once.
+// Each artefact is uploaded under the repeated artefact form file, its signature under the repeated signature form file
+// at the same position.
+// The upload is all-or-nothing, if any artefact does not validate or cannot be inserted, none of them are published.
+// Artefacts already uploaded with an identical hash are yielded back as is.
+func ArtefactsUploadPost(
+	db *sqlm.DB,
+	validator artefact.Validator,
+	controllerMetrics *metrics.Metrics,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		uploadStart := time.Now()
+		outcome := libMetrics.OutcomeFailure
+
+		defer func() { controllerMetrics.ObserveArtefactUpload(outcome, time.Since(uploadStart)) }()
+
+		form, err := context.MultipartForm()
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusBadRequest, fmt.Errorf("failed to parse multipart form: %w", err)))
+			return
+		}
+
+		artefactFiles, signatureFiles := form.File["artefact"], form.File["signature"]
+		if len(artefactFiles) == 0 || len(artefactFiles) != len(signatureFiles) {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("expected a signature for each of the artefacts, got %d artefacts and %d signatures", len(artefactFiles), len(signatureFiles)),
+			))
+
+			return
+		}
+
+		artefacts := make([]networkmodel.ArtefactModel, len(artefactFiles))
+		artefactModels := make([]networkmodel.ArtefactModelWithBinary, 0, len(artefactFiles))
+		insertedIndices := make([]int, 0, len(artefactFiles))
+		uploadedArtefacts := make(map[string]struct{}, len(artefactFiles))
+		for index := range artefactFiles {
+			artefactModel, restErr := validateUploadedFormFiles(validator, controllerMetrics, artefactFiles[index], signatureFiles[index])
+			if restErr != nil {
+				_ = context.Error(restErr)
+				return
+			}
+
+			key := artefactModel.Identifier + "-" + artefactModel.Version
+			if _, found := uploadedArtefacts[key]; found {
+				_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "artefact "+key+" was uploaded twice"))
+				return
+			}
+
+			uploadedArtefacts[key] = struct{}{}
+
+			existingArtefact, found, restErr := findUploadedArtefact(context, db, artefactModel)
+			if restErr != nil {
+				_ = context.Error(restErr)
+				return
+			}
+
+			if found {
+				artefacts[index] = existingArtefact
+				continue
+			}
+
+			artefactModels = append(artefactModels, artefactModel)
+			insertedIndices = append(insertedIndices, index)
+		}
+
+		insertedArtefacts, err := access.InsertArtefacts(context, db, artefactModels)
+		if err != nil {
+			_ = context.Error(response.RestErrorFrom(
+				access.RestErrFromAccessErr(err),
+				"failed to insert artefacts into db",
+				fmt.Errorf("failed to upload artefacts to database: %w", err),
+			))
+
+			return
+		}
+
+		for insertedIndex, index := range insertedIndices {
+			artefacts[index] = insertedArtefacts[insertedIndex]
+		}
+
+		outcome = libMetrics.OutcomeSuccess
+		context.JSONP(http.StatusOK, artefacts)
+	}
+}
+
+// validateUploadedFormFiles saves the uploaded artefact and signature form files to disk and validates them.
+func validateUploadedFormFiles(
+	validator artefact.Validator,
+	controllerMetrics *metrics.Metrics,
+	artefactFile, signatureFile *multipart.FileHeader,
+) (networkmodel.ArtefactModelWithBinary, *response.RestRequestError) {
+	pathToArtefact, err := saveFormFileInto(artefactFile, os.TempDir()+"/marauder", "artefact-*.tar.gz")
+	if err != nil {
+		return networkmodel.ArtefactModelWithBinary{}, response.RestErrorFromErr(
+			http.StatusInternalServerError, fmt.Errorf("failed to save artefact file: %w", err),
+		)
+	}
+
+	defer func() { _ = os.Remove(pathToArtefact) }()
+
+	pathToSignature, err := saveFormFileInto(signatureFile, os.TempDir()+"/marauder", "signature-*.sig")
+	if err != nil {
+		return networkmodel.ArtefactModelWithBinary{}, response.RestErrorFromErr(
+			http.StatusInternalServerError, fmt.Errorf("failed to save signature file: %w", err),
+		)
+	}
+
+	defer func() { _ = os.Remove(pathToSignature) }()
+
+	return validateUploadedArtefact(validator, controllerMetrics, pathToArtefact, pathToSignature)
+}
@@ -0,0 +1,89 @@
+package endpoints
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/operator"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// consoleUpgrader upgrades console attach requests to websockets.
+var consoleUpgrader = websocket.Upgrader{ReadBufferSize: 4096, WriteBufferSize: 4096}
+
+// OperationServerAttach creates the endpoint tunneling a console attach websocket to the operator of the server.
+// As an attached console grants full control over the server, only clients authenticated via mutual tls may attach unless
+// allowWithoutClientCertificate is set.
+func OperationServerAttach(
+	db *sqlm.DB,
+	operatorClientCache *operator.ClientCache,
+	allowWithoutClientCertificate bool,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		clientAuthenticated := context.Request.TLS != nil && len(context.Request.TLS.VerifiedChains) > 0
+		if !clientAuthenticated && !allowWithoutClientCertificate {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusForbidden,
+				"attaching to a console requires a client authenticated via mutual tls",
+			))
+
+			return
+		}
+
+		serverUUIDAsString := context.Param("server")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse server uuid "+err.Error()))
+			return
+		}
+
+		server, err := access.FetchServer(context, db, serverUUID)
+		if err != nil {
+			_ = context.Error(
+				response.RestErrorFromKnownErr(
+					map[error]response.KnownErr{
+						sql.ErrNoRows: {ResponseCode: http.StatusNotFound, Description: "could not find server " + serverUUID.String()},
+					},
+					fmt.Errorf("failed to fetch server %s: %w", serverUUID, err),
+				),
+			)
+
+			return
+		}
+
+		operatorConn, err := operatorClientCache.GetOrCreateFromRef(server.OperatorRef).AttachConsole(context, server.UUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusBadGateway,
+				fmt.Errorf("failed to attach to console on operator: %w", err),
+			))
+
+			return
+		}
+
+		defer func() { _ = operatorConn.Close() }()
+
+		// The upgrader responds to the request itself on failure.
+		clientConn, err := consoleUpgrader.Upgrade(context.Writer, context.Request, nil)
+		if err != nil {
+			logrus.Warn("failed to upgrade console attach request of server ", serverUUIDAsString, " ", err)
+			return
+		}
+
+		defer func() { _ = clientConn.Close() }()
+
+		logrus.Info("client ", context.ClientIP(), " attached to console of server ", server.Environment, "/", server.Name)
+
+		if err := utils.TunnelWebsockets(clientConn, operatorConn); err != nil {
+			logrus.Warn("console tunnel of server ", serverUUIDAsString, " failed ", err)
+		}
+	}
+}
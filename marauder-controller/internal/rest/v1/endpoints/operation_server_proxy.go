@@ -3,7 +3,6 @@ package endpoints
 import (
 	"database/sql"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +11,7 @@ import (
 	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/operator"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
 
 func OperationServerProxy(
@@ -44,11 +44,16 @@ func OperationServerProxy(
 		// Fetch operator client
 		operatorClient := operatorClientCache.GetOrCreate(server.OperatorIdentifier, server.OperatorRef.Host, server.OperatorRef.Port)
 
-		// Execute request
+		// Execute request, forwarding the query parameters to the operator.
+		proxiedPath := context.Param("path")
+		if context.Request.URL.RawQuery != "" {
+			proxiedPath += "?" + context.Request.URL.RawQuery
+		}
+
 		operatorResp, err := operatorClient.DoHTTPRequest(
 			context,
 			context.Request.Method,
-			context.Param("path"),
+			proxiedPath,
 			context.Request.Body,
 			operator.None,
 		)
@@ -70,8 +75,9 @@ func OperationServerProxy(
 			}
 		}
 
+		// Flush while copying, the operator might stream its response, e.g. followed logs.
 		context.Status(operatorResp.StatusCode)
-		if _, err := io.Copy(context.Writer, operatorResp.Body); err != nil {
+		if _, err := utils.CopyAndFlush(context.Writer, operatorResp.Body); err != nil {
 			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to copy operator response: %w", err)))
 			return
 		}
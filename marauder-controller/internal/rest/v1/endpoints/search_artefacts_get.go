@@ -0,0 +1,61 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// SearchArtefactsGet creates the get endpoint that searches artefacts by the commit and branch they were built from
+// or by a file they own.
+func SearchArtefactsGet(
+	db *sqlm.DB,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		search, restErr := parseArtefactSearch(context)
+		if restErr != nil {
+			_ = context.Error(restErr)
+			return
+		}
+
+		artefacts, err := access.SearchArtefacts(context, db, search)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to search artefacts: %w", err)))
+			return
+		}
+
+		context.JSONP(http.StatusOK, artefacts)
+	}
+}
+
+// parseArtefactSearch parses the artefact search from the query parameters of the request.
+// At least one filter has to be defined, as searching all artefacts is not a search.
+func parseArtefactSearch(context *gin.Context) (networkmodel.ArtefactSearch, *response.RestRequestError) {
+	search := networkmodel.ArtefactSearch{
+		CommitHash: context.Query("commit"),
+		Branch:     context.Query("branch"),
+		File:       context.Query("file"),
+	}
+
+	if search.IsEmpty() {
+		return networkmodel.ArtefactSearch{}, response.RestErrorFromDescription(
+			http.StatusBadRequest,
+			"at least one of the commit, branch or file query parameters is required",
+		)
+	}
+
+	if strings.Trim(search.CommitHash, "0123456789abcdefABCDEF") != "" {
+		return networkmodel.ArtefactSearch{}, response.RestErrorFromDescription(
+			http.StatusBadRequest,
+			"commit "+search.CommitHash+" is not a hexadecimal commit hash",
+		)
+	}
+
+	return search, nil
+}
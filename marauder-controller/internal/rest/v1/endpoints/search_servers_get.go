@@ -0,0 +1,41 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// SearchServersGet creates the get endpoint that searches servers shipping an artefact matching the artefact search in
+// their state, the IS state unless the state query parameter defines another.
+func SearchServersGet(
+	db *sqlm.DB,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		state := networkmodel.ServerStateType(strings.ToUpper(context.DefaultQuery("state", string(networkmodel.IS))))
+		if !networkmodel.KnownServerStateType(state) {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, fmt.Sprintf("unknown state %s", state)))
+			return
+		}
+
+		search, restErr := parseArtefactSearch(context)
+		if restErr != nil {
+			_ = context.Error(restErr)
+			return
+		}
+
+		servers, err := access.SearchServers(context, db, search, state)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to search servers: %w", err)))
+			return
+		}
+
+		context.JSONP(http.StatusOK, servers)
+	}
+}
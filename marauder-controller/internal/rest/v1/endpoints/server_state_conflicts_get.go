@@ -0,0 +1,84 @@
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ServerStateConflictsGet creates the get endpoint that lists the files shipped by multiple artefacts of a server's state.
+func ServerStateConflictsGet(
+	db *sqlm.DB,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverID, err := uuid.Parse(context.Param("uuid"))
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		state := networkmodel.ServerStateType(strings.ToUpper(context.Param("state")))
+		if !networkmodel.KnownServerStateType(state) {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, fmt.Sprintf("unknown state %s", state)))
+			return
+		}
+
+		conflicts, err := findServerFileConflicts(context, db, serverID, state, nil)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to find file conflicts: %w", err)))
+			return
+		}
+
+		context.JSONP(http.StatusOK, conflicts)
+	}
+}
+
+// findServerFileConflicts finds the files shipped by multiple artefacts of the server's state.
+// If an artefact is passed, it replaces the artefact with its identifier in the state, allowing to find the conflicts
+// a new state would introduce.
+func findServerFileConflicts(
+	ctx context.Context,
+	db *sqlm.DB,
+	server uuid.UUID,
+	state networkmodel.ServerStateType,
+	replacingArtefact *networkmodel.ArtefactModel,
+) ([]networkmodel.FileConflict, error) {
+	artefacts, err := access.FetchServerArtefactsByState(ctx, db, server, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artefacts of server state: %w", err)
+	}
+
+	manifests := make([]filemodel.Manifest, 0, len(artefacts)+1)
+	for _, stateArtefact := range artefacts {
+		if replacingArtefact != nil && stateArtefact.Identifier == replacingArtefact.Identifier {
+			continue
+		}
+
+		manifest, err := fetchArtefactManifest(ctx, db, stateArtefact.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch manifest of %s: %w", stateArtefact.Identifier, err)
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	if replacingArtefact != nil {
+		manifest, err := fetchArtefactManifest(ctx, db, replacingArtefact.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch manifest of %s: %w", replacingArtefact.Identifier, err)
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return networkmodel.FindFileConflicts(manifests), nil
+}
@@ -89,12 +89,20 @@ func updateOrInsertState(
 		return
 	}
 
+	if state == networkmodel.TARGET && !updateRequest.AllowFileConflicts {
+		if restErr := checkNoFileConflicts(context, db, serverID, artefactByUUID); restErr != nil {
+			_ = context.Error(restErr)
+			return
+		}
+	}
+
 	deployment, err := access.UpdateDeployment(context, db, networkmodel.ServerArtefactStateModel{
 		Server:             serverID,
 		ArtefactIdentifier: updateRequest.ArtefactIdentifier,
 		ArtefactUUID:       *updateRequest.ArtefactUUID,
 		DefinitionDate:     time.Now(),
 		Type:               state,
+		AllowFileConflicts: state == networkmodel.TARGET && updateRequest.AllowFileConflicts,
 	})
 	if err != nil {
 		_ = context.Error(
@@ -109,3 +117,34 @@ func updateOrInsertState(
 
 	context.JSONP(http.StatusOK, deployment)
 }
+
+// checkNoFileConflicts checks that the artefact does not ship files shipped by the other artefacts of the server's
+// TARGET state, which would be overwritten by its installation and deleted by its uninstallation.
+func checkNoFileConflicts(
+	context *gin.Context,
+	db *sqlm.DB,
+	serverID uuid.UUID,
+	artefact networkmodel.ArtefactModel,
+) *response.RestRequestError {
+	conflicts, err := findServerFileConflicts(context, db, serverID, networkmodel.TARGET, &artefact)
+	if err != nil {
+		return response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to find file conflicts: %w", err))
+	}
+
+	conflictDescriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		if conflict.Involves(artefact.Identifier) {
+			conflictDescriptions = append(conflictDescriptions, conflict.String())
+		}
+	}
+
+	if len(conflictDescriptions) == 0 {
+		return nil
+	}
+
+	return response.RestErrorFromDescription(http.StatusConflict, fmt.Sprintf(
+		"artefact %s ships files already shipped by other artefacts of the server, allow file conflicts to deploy it anyway: %s",
+		artefact.Identifier,
+		strings.Join(conflictDescriptions, ", "),
+	))
+}
@@ -0,0 +1,50 @@
+package endpoints
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/operator"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ServerUUIDStatusGet creates the get endpoint that may be used to fetch the live runtime status of a server from its operator.
+func ServerUUIDStatusGet(
+	db *sqlm.DB,
+	operatorClientCache *operator.ClientCache,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUID := context.Param("uuid")
+		serverID, err := uuid.Parse(serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		server, err := access.FetchServer(context, db, serverID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				sql.ErrNoRows: {ResponseCode: http.StatusNotFound, Description: "failed to find server " + serverID.String()},
+			}, fmt.Errorf("failed to fetch server: %w", err)))
+
+			return
+		}
+
+		status, err := operatorClientCache.GetOrCreateFromRef(server.OperatorRef).FetchServerStatus(context, serverID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusBadGateway,
+				fmt.Errorf("failed to fetch status of server %s from operator: %w", serverID, err),
+			))
+
+			return
+		}
+
+		context.JSONP(http.StatusOK, status)
+	}
+}
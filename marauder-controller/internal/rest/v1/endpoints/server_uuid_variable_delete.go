@@ -0,0 +1,31 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ServerUUIDVariableDelete creates the delete endpoint that may be used to delete a variable of a server.
+func ServerUUIDVariableDelete(db *sqlm.DB) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUID := context.Param("uuid")
+		serverID, err := uuid.Parse(serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		if err := access.DeleteServerVariable(context, db, serverID, context.Param("key")); err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to delete server variable: %w", err)))
+			return
+		}
+
+		context.Status(http.StatusOK)
+	}
+}
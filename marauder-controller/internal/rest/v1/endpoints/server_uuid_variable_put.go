@@ -0,0 +1,51 @@
+package endpoints
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ServerUUIDVariablePut creates the put endpoint that may be used to define the value of a variable of a server.
+func ServerUUIDVariablePut(db *sqlm.DB) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUID := context.Param("uuid")
+		serverID, err := uuid.Parse(serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		updateRequest := networkmodel.UpdateServerVariableRequest{}
+		if err := context.Bind(&updateRequest); err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, fmt.Errorf("failed to bind body: %w", err).Error()))
+			return
+		}
+
+		if _, err := access.FetchServer(context, db, serverID); err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				sql.ErrNoRows: {ResponseCode: http.StatusNotFound, Description: "failed to find server " + serverID.String()},
+			}, fmt.Errorf("failed to fetch server: %w", err)))
+
+			return
+		}
+
+		if err := access.UpsertServerVariable(context, db, networkmodel.ServerVariable{
+			Server: serverID,
+			Key:    context.Param("key"),
+			Value:  updateRequest.Value,
+		}); err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to define server variable: %w", err)))
+			return
+		}
+
+		context.Status(http.StatusOK)
+	}
+}
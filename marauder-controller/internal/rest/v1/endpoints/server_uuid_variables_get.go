@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+)
+
+// ServerUUIDVariablesGet creates the get endpoint that may be used to fetch all variables defined for a server.
+func ServerUUIDVariablesGet(db *sqlm.DB) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUID := context.Param("uuid")
+		serverID, err := uuid.Parse(serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		variables, err := access.FetchServerVariables(context, db, serverID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to fetch server variables: %w", err)))
+			return
+		}
+
+		context.JSONP(http.StatusOK, variables)
+	}
+}
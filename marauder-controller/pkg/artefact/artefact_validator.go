@@ -6,17 +6,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/worker"
-	"golang.org/x/crypto/ssh"
 )
 
 var (
-	// ErrUnknownSignature is returned if a signature on an artefact is unknown to the validator.
-	ErrUnknownSignature = errors.New("unknown signature")
-
 	// ErrManifestMissing is yielded back if the manifest is missing from an artefact.
 	ErrManifestMissing = errors.New("manifest missing")
 
@@ -29,6 +27,12 @@ var (
 type ValidationResult struct {
 	Manifest     filemodel.Manifest
 	ArtefactHash []byte
+
+	// Signer is the key that signed the artefact.
+	Signer keyauth.SigningKey
+
+	// Signature is the verified signature of the artefact.
+	Signature []byte
 }
 
 // The Validator is a worker queue responsible for validating a newly uploaded artefact.
@@ -41,12 +45,13 @@ type Validator interface {
 
 // WorkedBasedValidator represents a artefact validator based on a worker.Dispatcher instance.
 type WorkedBasedValidator struct {
-	dispatcher      *worker.Dispatcher[ValidationResult]
-	knownPublicKeys []ssh.PublicKey
+	dispatcher *worker.Dispatcher[ValidationResult]
+	keys       *keyauth.Registry
 }
 
-func NewWorkedBasedValidator(dispatcher *worker.Dispatcher[ValidationResult], knownPublicKeys []ssh.PublicKey) *WorkedBasedValidator {
-	return &WorkedBasedValidator{dispatcher: dispatcher, knownPublicKeys: knownPublicKeys}
+// NewWorkedBasedValidator creates a new validator verifying artefact signatures against the keys of the registry.
+func NewWorkedBasedValidator(dispatcher *worker.Dispatcher[ValidationResult], keys *keyauth.Registry) *WorkedBasedValidator {
+	return &WorkedBasedValidator{dispatcher: dispatcher, keys: keys}
 }
 
 // SubmitArtefact submits the artefact and its signature to the validator.
@@ -64,7 +69,7 @@ func (w *WorkedBasedValidator) SubmitArtefact(artefactPath, signaturePath string
 
 		defer func() { _ = artefactFile.Close() }()
 
-		artefactHash, err := w.verifyArtefactSignature(artefactFile, signature)
+		artefactHash, signer, err := w.verifyArtefactSignature(artefactFile, signature)
 		if err != nil {
 			return ValidationResult{}, fmt.Errorf("failed to verify artefact signature: %w", err)
 		}
@@ -77,35 +82,33 @@ func (w *WorkedBasedValidator) SubmitArtefact(artefactPath, signaturePath string
 		return ValidationResult{
 			Manifest:     manifest,
 			ArtefactHash: artefactHash,
+			Signer:       signer,
+			Signature:    signature,
 		}, nil
 	})
 }
 
 // verifyArtefactSignature verifies the uploaded signature against the artefact file by checking if the signature is
 // a) valid for the artefact file.
-// b) belongs to a known public key of marauderctl.
-func (w *WorkedBasedValidator) verifyArtefactSignature(artefact *os.File, signatureBytes []byte) ([]byte, error) {
+// b) belongs to a key of marauderctl's registry that is neither revoked nor outside its validity window.
+func (w *WorkedBasedValidator) verifyArtefactSignature(artefact *os.File, signatureBytes []byte) ([]byte, keyauth.SigningKey, error) {
 	if _, err := artefact.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("failed to reset artefact file ref to start: %w", err)
-	}
-
-	var signature ssh.Signature
-	if err := ssh.Unmarshal(signatureBytes, &signature); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal signature bytes: %w", err)
+		return nil, keyauth.SigningKey{}, fmt.Errorf("failed to reset artefact file ref to start: %w", err)
 	}
 
 	sha256, err := utils.ComputeSha256(artefact)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute sha256 hash for artefact tarball: %w", err)
+		return nil, keyauth.SigningKey{}, fmt.Errorf("failed to compute sha256 hash for artefact tarball: %w", err)
 	}
 
-	for _, key := range w.knownPublicKeys {
-		if err := key.Verify(sha256, &signature); err != nil {
-			continue
-		}
+	if _, err := artefact.Seek(0, io.SeekStart); err != nil {
+		return nil, keyauth.SigningKey{}, fmt.Errorf("failed to reset artefact file ref to start: %w", err)
+	}
 
-		return sha256, nil // Return null if a key verified.
+	signer, err := w.keys.Verify(artefact, signatureBytes, time.Now())
+	if err != nil {
+		return nil, keyauth.SigningKey{}, fmt.Errorf("failed to verify signature against known keys: %w", err)
 	}
 
-	return nil, fmt.Errorf("did not find signature in %d known signatures: %w", len(w.knownPublicKeys), ErrUnknownSignature)
+	return sha256, signer, nil
 }
@@ -2,6 +2,7 @@ package artefact
 
 import (
 	"archive/tar"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -98,7 +99,21 @@ func readTarballForValidation(tarReader *tar.Reader) (filemodel.Manifest, map[st
 			return filemodel.Manifest{}, nil, fmt.Errorf("failed to read header from artefact tarball: %w", err)
 		}
 
-		if header.Name == pkg.ManifestFileName {
+		// Reject entries that would be deployed outside the server folder before the artefact reaches any operator.
+		if err := utils.ValidateArtefactEntry(header); err != nil {
+			return filemodel.Manifest{}, nil, fmt.Errorf("failed to validate artefact entry: %w", err)
+		}
+
+		if _, duplicate := includedFiles[header.Name]; duplicate {
+			return filemodel.Manifest{}, nil, fmt.Errorf("entry %s is included twice: %w", header.Name, utils.ErrUnsafeArtefactEntry)
+		}
+
+		switch {
+		case header.Name == pkg.ManifestFileName:
+			if manifest != nil {
+				return filemodel.Manifest{}, nil, fmt.Errorf("entry %s is included twice: %w", header.Name, utils.ErrUnsafeArtefactEntry)
+			}
+
 			manifestBytes, err := io.ReadAll(tarReader)
 			if err != nil {
 				return filemodel.Manifest{}, nil, fmt.Errorf("failed to read byte of amnifest file from tarball: %w", err)
@@ -108,7 +123,11 @@ func readTarballForValidation(tarReader *tar.Reader) (filemodel.Manifest, map[st
 			if err := json.Unmarshal(manifestBytes, manifest); err != nil {
 				return filemodel.Manifest{}, nil, fmt.Errorf("failed to parse manifest file: %w", err)
 			}
-		} else {
+		case header.Typeflag == tar.TypeSymlink:
+			// The hash of symbolic links is computed over their target.
+			linkHash := sha256.Sum256([]byte(header.Linkname))
+			includedFiles[header.Name] = hex.EncodeToString(linkHash[:])
+		default:
 			fileHash, err := utils.ComputeSha256(tarReader)
 			if err != nil {
 				return filemodel.Manifest{}, nil, fmt.Errorf("failed to compute hash for file %s: %w", header.Name, err)
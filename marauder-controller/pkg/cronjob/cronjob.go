@@ -9,6 +9,7 @@ const (
 	RemoveHistoricIdentifier                   Type = "removeHistoric"
 	ClearOperatorCacheIdentifier               Type = "clearOperatorCaches"
 	ExecuteScheduledLifecycleActionsIdentifier Type = "executeScheduledLifecycleActions"
+	DetectServerDriftIdentifier                Type = "detectServerDrift"
 )
 
 // Type is a specific cronjob type runnable by marauder.
@@ -29,6 +30,7 @@ type CronjobsConfiguration struct {
 	RemoveHistoric                   *RemoveHistoric                   `yaml:"removeHistoric,omitempty"`
 	ClearOperatorCaches              *ClearOperatorCaches              `yaml:"clearOperatorCaches,omitempty"`
 	ExecuteScheduledLifecycleActions *ExecuteScheduledLifecycleActions `yaml:"executeScheduledLifecycleActions"`
+	DetectServerDrift                *DetectServerDrift                `yaml:"detectServerDrift,omitempty"`
 }
 
 // BaseCronjobConfiguration defines a base struct for all cronjobs configurations.
@@ -62,6 +64,11 @@ type ExecuteScheduledLifecycleActions struct {
 	BaseCronjobConfiguration `yaml:",inline"`
 }
 
+// DetectServerDrift holds the configuration for the cronjob that verifies the files of all servers against their IS state.
+type DetectServerDrift struct {
+	BaseCronjobConfiguration `yaml:",inline"`
+}
+
 // Execution represents a cronjob the controller should execute on a regular basis.
 type Execution struct {
 	NextExecution time.Time `db:"next_execution"`
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-controller/internal/db/access"
+	"github.com/knockturnmc/marauder/marauder-controller/sqlm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// databaseCollectionTimeout defines the timeout for querying all statistics from the database on a single collection.
+const databaseCollectionTimeout = 10 * time.Second
+
+// The DatabaseCollector collects the business metrics of the controller from its database on each collection.
+type DatabaseCollector struct {
+	db *sqlm.DB
+
+	missmatchedServers     *prometheus.Desc
+	oldestMissmatchAge     *prometheus.Desc
+	scheduledActions       *prometheus.Desc
+	storedArtefacts        *prometheus.Desc
+	artefactStorageSize    *prometheus.Desc
+	databaseScrapeFailures prometheus.Counter
+}
+
+// NewDatabaseCollector creates a new collector for the business metrics found in the passed database.
+func NewDatabaseCollector(db *sqlm.DB) *DatabaseCollector {
+	return &DatabaseCollector{
+		db: db,
+		missmatchedServers: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "missmatched_servers"),
+			"Number of servers in an environment whose TARGET state does not match their IS state.",
+			[]string{"environment"}, nil,
+		),
+		oldestMissmatchAge: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "oldest_missmatch_age_seconds"),
+			"Age of the oldest state that is part of a missmatch between TARGET and IS state in an environment.",
+			[]string{"environment"}, nil,
+		),
+		scheduledActions: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "scheduled_lifecycle_actions"),
+			"Number of scheduled lifecycle actions that are pending or overdue, the latter indicating a failed execution.",
+			[]string{"state"}, nil,
+		),
+		storedArtefacts: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "stored_artefacts"),
+			"Number of artefacts stored by the controller.",
+			nil, nil,
+		),
+		artefactStorageSize: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "", "artefact_storage_size_bytes"),
+			"Total size of all artefact tarballs stored by the controller.",
+			nil, nil,
+		),
+		databaseScrapeFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "database_scrape_failures_total",
+			Help:      "Number of failed queries while collecting metrics from the database.",
+		}),
+	}
+}
+
+func (c *DatabaseCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.missmatchedServers
+	descs <- c.oldestMissmatchAge
+	descs <- c.scheduledActions
+	descs <- c.storedArtefacts
+	descs <- c.artefactStorageSize
+	c.databaseScrapeFailures.Describe(descs)
+}
+
+func (c *DatabaseCollector) Collect(metricsChan chan<- prometheus.Metric) {
+	ctx, cancelFunction := context.WithTimeout(context.Background(), databaseCollectionTimeout)
+	defer cancelFunction()
+
+	now := time.Now()
+
+	if missmatchStatistics, err := access.FetchEnvironmentMissmatchStatistics(ctx, c.db); err != nil {
+		c.recordFailure(err)
+	} else {
+		for _, statistic := range missmatchStatistics {
+			metricsChan <- prometheus.MustNewConstMetric(
+				c.missmatchedServers, prometheus.GaugeValue, float64(statistic.MissmatchedServers), statistic.Environment,
+			)
+			metricsChan <- prometheus.MustNewConstMetric(
+				c.oldestMissmatchAge, prometheus.GaugeValue, now.Sub(statistic.OldestMissmatch).Seconds(), statistic.Environment,
+			)
+		}
+	}
+
+	if actionStatistics, err := access.FetchScheduledLifecycleActionStatistics(ctx, c.db, now); err != nil {
+		c.recordFailure(err)
+	} else {
+		metricsChan <- prometheus.MustNewConstMetric(c.scheduledActions, prometheus.GaugeValue, float64(actionStatistics.Pending), "pending")
+		metricsChan <- prometheus.MustNewConstMetric(c.scheduledActions, prometheus.GaugeValue, float64(actionStatistics.Overdue), "overdue")
+	}
+
+	if storageStatistics, err := access.FetchArtefactStorageStatistics(ctx, c.db); err != nil {
+		c.recordFailure(err)
+	} else {
+		metricsChan <- prometheus.MustNewConstMetric(c.storedArtefacts, prometheus.GaugeValue, float64(storageStatistics.Artefacts))
+		metricsChan <- prometheus.MustNewConstMetric(c.artefactStorageSize, prometheus.GaugeValue, float64(storageStatistics.SizeBytes))
+	}
+
+	c.databaseScrapeFailures.Collect(metricsChan)
+}
+
+// recordFailure records a failed query while collecting metrics.
+func (c *DatabaseCollector) recordFailure(err error) {
+	logrus.Warn("failed to collect metrics from database ", err)
+	c.databaseScrapeFailures.Inc()
+}
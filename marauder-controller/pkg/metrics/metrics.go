@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/knockturnmc/marauder/marauder-controller/pkg/cronjob"
+	libMetrics "github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the namespace all metrics of the controller are exposed under.
+const Namespace = "marauder_controller"
+
+// The Metrics struct holds all metrics exposed by the controller.
+// All observation methods are safe to call on a nil instance, in which case they are a NOOP.
+type Metrics struct {
+	*libMetrics.Registry
+
+	requestDuration            *prometheus.HistogramVec
+	cronjobDuration            *prometheus.HistogramVec
+	cronjobLastSuccess         *prometheus.GaugeVec
+	artefactUploadDuration     *prometheus.HistogramVec
+	artefactValidationDuration *prometheus.HistogramVec
+}
+
+// NewMetrics creates and registers all metrics of the controller on a new registry.
+func NewMetrics() (*Metrics, error) {
+	registry, err := libMetrics.NewRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	metrics := &Metrics{
+		Registry: registry,
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of http requests served by the controller by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		cronjobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "cronjob_duration_seconds",
+			Help:      "Duration of cronjob executions by cronjob and outcome.",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600},
+		}, []string{"cronjob", "outcome"}),
+		cronjobLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "cronjob_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful execution of a cronjob.",
+		}, []string{"cronjob"}),
+		artefactUploadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "artefact_upload_duration_seconds",
+			Help:      "Duration of artefact uploads, including their validation and storage, by outcome.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"outcome"}),
+		artefactValidationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "artefact_validation_duration_seconds",
+			Help:      "Duration of uploaded artefact validations, including the time spent queued for a validation worker, by outcome.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"outcome"}),
+	}
+
+	if err := metrics.Register(
+		metrics.requestDuration,
+		metrics.cronjobDuration,
+		metrics.cronjobLastSuccess,
+		metrics.artefactUploadDuration,
+		metrics.artefactValidationDuration,
+	); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// Middleware creates the gin middleware recording the duration of all requests served by the controller.
+// On a nil instance, the middleware records nothing.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	if m == nil {
+		return func(context *gin.Context) { context.Next() }
+	}
+
+	return func(context *gin.Context) {
+		requestStart := time.Now()
+		context.Next()
+
+		route := context.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.requestDuration.WithLabelValues(route, context.Request.Method, strconv.Itoa(context.Writer.Status())).
+			Observe(time.Since(requestStart).Seconds())
+	}
+}
+
+// ObserveCronjobExecution records the execution of a cronjob.
+func (m *Metrics) ObserveCronjobExecution(cronjobType cronjob.Type, err error, executionStart time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.cronjobDuration.WithLabelValues(string(cronjobType), libMetrics.Outcome(err)).Observe(time.Since(executionStart).Seconds())
+	if err == nil {
+		m.cronjobLastSuccess.WithLabelValues(string(cronjobType)).SetToCurrentTime()
+	}
+}
+
+// ObserveArtefactUpload records the upload of an artefact.
+func (m *Metrics) ObserveArtefactUpload(outcome string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.artefactUploadDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// ObserveArtefactValidation records the validation of an uploaded artefact.
+func (m *Metrics) ObserveArtefactValidation(err error, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.artefactValidationDuration.WithLabelValues(libMetrics.Outcome(err)).Observe(duration.Seconds())
+}
@@ -0,0 +1,30 @@
+--
+-- Function to query the missmatch statistics between the TARGET and IS state of all servers, grouped by their environment.
+-- Returns the environment, the amount of servers that have at least one missmatch and the definition date of the oldest
+-- state that is part of a missmatch in said environment.
+-- Environments without any missmatch are not returned.
+--
+CREATE FUNCTION func_find_environment_missmatch_statistics()
+	RETURNS TABLE
+			(
+				environment         VARCHAR,
+				missmatched_servers BIGINT,
+				oldest_missmatch    TIMESTAMPTZ
+			)
+AS
+$$
+BEGIN
+	RETURN QUERY SELECT server.environment,
+						COUNT(DISTINCT server.uuid) as missmatched_servers,
+						MIN(COALESCE(target_state.definition_date, is_state.definition_date))::TIMESTAMPTZ as oldest_missmatch
+				 FROM server
+						  CROSS JOIN LATERAL func_find_server_target_state_missmatches(server.uuid) missmatch
+						  LEFT JOIN server_state_target target_state ON
+							 target_state.server = server.uuid
+						 AND target_state.artefact_identifier = missmatch.artefact_identifier
+						  LEFT JOIN server_state_is is_state ON
+							 is_state.server = server.uuid
+						 AND is_state.artefact_identifier = missmatch.artefact_identifier
+				 GROUP BY server.environment;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,29 @@
+-- The server drift check table holds the last time the files of a server were verified against the artefacts in its IS state.
+CREATE TABLE IF NOT EXISTS server_drift_check
+(
+	server     UUID      NOT NULL,
+	checked_at TIMESTAMP NOT NULL,
+
+	CONSTRAINT pk_server_drift_check PRIMARY KEY (server),
+	CONSTRAINT fk_server_drift_check_server FOREIGN KEY (server) REFERENCES server (uuid)
+		ON DELETE CASCADE
+);
+
+-- The server drift file table holds every file found to be modified or missing during the last check of a server.
+CREATE TABLE IF NOT EXISTS server_drift_file
+(
+	server              UUID    NOT NULL,
+	artefact_identifier VARCHAR NOT NULL,
+	artefact_uuid       UUID    NOT NULL,
+	artefact_version    VARCHAR NOT NULL,
+	path                VARCHAR NOT NULL,
+	kind                VARCHAR NOT NULL,
+	equality_provider   VARCHAR NOT NULL,
+	detail              VARCHAR NOT NULL DEFAULT '',
+
+	CONSTRAINT pk_server_drift_file PRIMARY KEY (server, artefact_identifier, path),
+	CONSTRAINT fk_server_drift_file_check FOREIGN KEY (server) REFERENCES server_drift_check (server)
+		ON DELETE CASCADE,
+	CONSTRAINT fk_server_drift_file_artefact_uuid FOREIGN KEY (artefact_uuid) REFERENCES artefact (uuid)
+		ON DELETE CASCADE
+);
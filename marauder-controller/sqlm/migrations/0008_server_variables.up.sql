@@ -0,0 +1,11 @@
+-- The server variable table holds the per-server variables available to files of artefacts deployed as templates.
+CREATE TABLE IF NOT EXISTS server_variable
+(
+	server UUID    NOT NULL,
+	key    VARCHAR NOT NULL,
+	value  VARCHAR NOT NULL,
+
+	CONSTRAINT pk_server_variable PRIMARY KEY (server, key),
+	CONSTRAINT fk_server_variable_server FOREIGN KEY (server) REFERENCES server (uuid)
+		ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+-- The signer columns record the key that signed an artefact. Artefacts uploaded before they were introduced hold none.
+ALTER TABLE artefact
+	ADD COLUMN IF NOT EXISTS signer_key_id VARCHAR,
+	ADD COLUMN IF NOT EXISTS signer_name   VARCHAR;
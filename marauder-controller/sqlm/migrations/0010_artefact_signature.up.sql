@@ -0,0 +1,4 @@
+-- The signature column holds the signature an artefact was uploaded with, allowing operators to verify the tarball
+-- themselves. Artefacts uploaded before it was introduced hold none.
+ALTER TABLE artefact_file
+	ADD COLUMN IF NOT EXISTS signature BYTEA;
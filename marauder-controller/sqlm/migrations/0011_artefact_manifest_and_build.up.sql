@@ -0,0 +1,19 @@
+-- The manifest column holds the manifest of an artefact, allowing it to be served and queried without reading the tarball.
+-- Artefacts uploaded before it was introduced are backfilled by the controller on startup.
+-- The manifest unreadable column marks artefacts whose manifest could not be read from their tarball when backfilling
+-- their manifest, so the controller does not attempt to read them again on every startup.
+ALTER TABLE artefact_file
+	ADD COLUMN IF NOT EXISTS manifest            JSONB,
+	ADD COLUMN IF NOT EXISTS manifest_unreadable BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The build columns hold the build information of an artefact's manifest. Artefacts built without build information
+-- hold none.
+ALTER TABLE artefact
+	ADD COLUMN IF NOT EXISTS build_repository   VARCHAR,
+	ADD COLUMN IF NOT EXISTS build_branch       VARCHAR,
+	ADD COLUMN IF NOT EXISTS build_commit_hash  VARCHAR,
+	ADD COLUMN IF NOT EXISTS build_commit_user  VARCHAR,
+	ADD COLUMN IF NOT EXISTS build_commit_email VARCHAR;
+
+CREATE INDEX IF NOT EXISTS idx_artefact_build_commit_hash ON artefact (build_commit_hash varchar_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_artefact_build_branch ON artefact (build_branch);
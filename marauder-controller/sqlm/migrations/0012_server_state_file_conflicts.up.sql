@@ -0,0 +1,4 @@
+-- The allow file conflicts column marks TARGET states whose artefact was explicitly allowed to ship files shipped by
+-- other artefacts on the server.
+ALTER TABLE server_state
+	ADD COLUMN IF NOT EXISTS allow_file_conflicts BOOLEAN NOT NULL DEFAULT FALSE;
@@ -22,3 +22,7 @@ const MarauderEnvironmentBranchOverride = "MARAUDER_BRANCH_OVERRIDE"
 // The MarauderEnvironmentBuildSpecificVersionOverride constant defines the environment name that may be defined when calling the
 // marauder client to overwrite the build-specific version.
 const MarauderEnvironmentBuildSpecificVersionOverride = "MARAUDER_BUILD_SPECIFIC_VERSION_OVERRIDE"
+
+// The SourceDateEpochEnvironment constant defines the environment name that may be defined when calling the marauder
+// client to fix the timestamp of a build, as specified by https://reproducible-builds.org/specs/source-date-epoch/.
+const SourceDateEpochEnvironment = "SOURCE_DATE_EPOCH"
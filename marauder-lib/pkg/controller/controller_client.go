@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/samber/mo"
@@ -42,18 +43,71 @@ type Client interface {
 	// FetchServerStateArtefacts fetches the artefacts defined for the specific state on the given server.
 	FetchServerStateArtefacts(ctx context.Context, server uuid.UUID, state networkmodel.ServerStateType) ([]networkmodel.ArtefactModel, error)
 
+	// FetchServerStatus fetches the live runtime status of the server from its operator.
+	FetchServerStatus(ctx context.Context, server uuid.UUID) (networkmodel.ServerRuntimeStatus, error)
+
+	// FetchServerVariables fetches all variables defined for the passed server.
+	FetchServerVariables(ctx context.Context, server uuid.UUID) ([]networkmodel.ServerVariable, error)
+
+	// SetServerVariable defines the value of the variable with the passed key for the server.
+	SetServerVariable(ctx context.Context, server uuid.UUID, key string, value string) error
+
+	// DeleteServerVariable deletes the variable with the passed key of the server.
+	DeleteServerVariable(ctx context.Context, server uuid.UUID, key string) error
+
 	// FetchMissmatchesFor fetches all outstanding missmatches for a server by its uuid.
 	FetchMissmatchesFor(ctx context.Context, server uuid.UUID, requiresRestart bool) ([]networkmodel.ArtefactVersionMissmatch, error)
 
 	// FetchManifest fetches a manifest based on its uuid.
 	FetchManifest(ctx context.Context, artefact uuid.UUID) (filemodel.Manifest, error)
 
+	// FetchArtefactIntegrity fetches the hash and signature of an artefact's tarball based on its uuid.
+	FetchArtefactIntegrity(ctx context.Context, artefact uuid.UUID) (networkmodel.ArtefactIntegrity, error)
+
+	// FetchServerFileConflicts fetches the files shipped by multiple artefacts of the passed state of the server.
+	FetchServerFileConflicts(ctx context.Context, server uuid.UUID, state networkmodel.ServerStateType) ([]networkmodel.FileConflict, error)
+
+	// SearchArtefacts searches artefacts by the commit and branch they were built from or by a file they own.
+	SearchArtefacts(ctx context.Context, search networkmodel.ArtefactSearch) ([]networkmodel.ArtefactModel, error)
+
+	// SearchServers searches servers whose passed state holds an artefact matching the search.
+	SearchServers(
+		ctx context.Context,
+		search networkmodel.ArtefactSearch,
+		state networkmodel.ServerStateType,
+	) ([]networkmodel.ServerModel, error)
+
 	// ManageServerPlayers fetches all players currently on the passed server.
 	ManageServerPlayers(ctx context.Context, server uuid.UUID) ([]networkmodel.ManagementPlayer, error)
 
 	// ManageServerToggleSave fetches all players currently on the passed server.
 	ManageServerToggleSave(ctx context.Context, server uuid.UUID, shouldSave bool) error
 
+	// FetchServerLogs opens a stream of the container logs of the passed server from its operator.
+	// The caller is responsible for closing the returned stream.
+	FetchServerLogs(ctx context.Context, server uuid.UUID, options networkmodel.ServerLogOptions) (io.ReadCloser, error)
+
+	// AttachConsole attaches to the console of the passed server, tunneled through the controller via a websocket.
+	AttachConsole(ctx context.Context, server uuid.UUID) (*websocket.Conn, error)
+
+	// FetchServerDrift fetches the drift of the passed server found during its last check by the controller.
+	FetchServerDrift(ctx context.Context, server uuid.UUID) (networkmodel.ServerDrift, error)
+
+	// ComputeServerDrift computes the current drift of the passed server on its operator.
+	ComputeServerDrift(ctx context.Context, server uuid.UUID) (networkmodel.ServerDrift, error)
+
+	// PlanServerUpdate computes the changes the passed update lifecycle action would perform on the server on its operator.
+	PlanServerUpdate(ctx context.Context, server uuid.UUID, action networkmodel.LifecycleAction) (networkmodel.UpdatePlan, error)
+
+	// CreateServerBackup creates a backup of the passed servers data folder on its operator.
+	CreateServerBackup(ctx context.Context, server uuid.UUID) (networkmodel.ServerBackup, error)
+
+	// FetchServerBackups fetches all backups of the passed server stored on its operator.
+	FetchServerBackups(ctx context.Context, server uuid.UUID) ([]networkmodel.ServerBackup, error)
+
+	// RestoreServerBackup restores the backup with the passed name into the data folder of the passed server.
+	RestoreServerBackup(ctx context.Context, server uuid.UUID, backup string) error
+
 	// ExecuteActionOn posts a lifecycle action to the operator of the server for the given server.
 	ExecuteActionOn(ctx context.Context, server uuid.UUID, action networkmodel.LifecycleAction, delay time.Duration) error
 
@@ -61,6 +115,11 @@ type Client interface {
 	// The method returns the status code of the response for further usage.
 	PublishArtefact(ctx context.Context, artefact, signature io.Reader) (networkmodel.ArtefactModel, mo.Option[int], error)
 
+	// PublishArtefacts publishes all passed artefacts to the controller at once.
+	// Either all artefacts are published or none of them are.
+	// The method returns the status code of the response for further usage.
+	PublishArtefacts(ctx context.Context, artefacts []ArtefactUpload) ([]networkmodel.ArtefactModel, mo.Option[int], error)
+
 	// UpdateState attempts to update the controller about a servers new state for the specific artefact.
 	UpdateState(ctx context.Context, server uuid.UUID, state networkmodel.ServerStateType, request networkmodel.UpdateServerStateRequest) error
 }
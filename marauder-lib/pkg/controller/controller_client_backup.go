@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// CreateServerBackup creates a backup of the passed servers data folder on its operator.
+func (h *HTTPClient) CreateServerBackup(ctx context.Context, server uuid.UUID) (networkmodel.ServerBackup, error) {
+	resp, err := utils.PerformHTTPRequest(
+		ctx,
+		h.Client,
+		http.MethodPost,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/backups", h.ControllerURL, server, server),
+		"application/json",
+		&bytes.Buffer{},
+	)
+	if err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to post http: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	backup, err := utils.HTTPResponseBind(resp, networkmodel.ServerBackup{})
+	if err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	return backup, nil
+}
+
+// FetchServerBackups fetches all backups of the passed server stored on its operator.
+func (h *HTTPClient) FetchServerBackups(ctx context.Context, server uuid.UUID) ([]networkmodel.ServerBackup, error) {
+	response, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/backups", h.ControllerURL, server, server),
+		make([]networkmodel.ServerBackup, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return response, nil
+}
+
+// RestoreServerBackup restores the backup with the passed name into the data folder of the passed server.
+func (h *HTTPClient) RestoreServerBackup(ctx context.Context, server uuid.UUID, backup string) error {
+	resp, err := utils.PerformHTTPRequest(
+		ctx,
+		h.Client,
+		http.MethodPost,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/backups/%s/restore", h.ControllerURL, server, server, url.PathEscape(backup)),
+		"application/json",
+		&bytes.Buffer{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to post http: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := utils.IsOkayStatusCodeOrErrorWithBody(resp); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return nil
+}
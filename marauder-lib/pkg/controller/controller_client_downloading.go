@@ -15,6 +15,10 @@ type DownloadingClient interface {
 	// DownloadArtefact downloads the artefact specified with the given uuid to the local cache folder and
 	// returns the full path to the downloaded file.
 	DownloadArtefact(ctx context.Context, artefactUUID uuid.UUID) (string, error)
+
+	// EvictArtefact removes the artefact specified with the given uuid from the local cache folder, e.g. because the
+	// cached file turned out to be corrupted.
+	EvictArtefact(artefactUUID uuid.UUID) error
 }
 
 // DownloadingHTTPClient is a http based implementation of the DownloadService interface.
@@ -28,7 +32,7 @@ func (h *DownloadingHTTPClient) DownloadArtefact(ctx context.Context, artefactUU
 	downloadedFile, err := h.DownloadService.Download(
 		ctx,
 		h.ControllerURL+"/artefact/"+artefactUUID.String()+"/download",
-		artefactUUID.String()+".tar.gz",
+		artefactCacheFilename(artefactUUID),
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to download artefact: %w", err)
@@ -36,3 +40,16 @@ func (h *DownloadingHTTPClient) DownloadArtefact(ctx context.Context, artefactUU
 
 	return downloadedFile, nil
 }
+
+func (h *DownloadingHTTPClient) EvictArtefact(artefactUUID uuid.UUID) error {
+	if err := h.DownloadService.Evict(artefactCacheFilename(artefactUUID)); err != nil {
+		return fmt.Errorf("failed to evict artefact: %w", err)
+	}
+
+	return nil
+}
+
+// artefactCacheFilename computes the name the artefact with the passed uuid is cached under.
+func artefactCacheFilename(artefactUUID uuid.UUID) string {
+	return artefactUUID.String() + ".tar.gz"
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// FetchServerDrift fetches the drift of the passed server found during its last check by the controller.
+func (h *HTTPClient) FetchServerDrift(ctx context.Context, server uuid.UUID) (networkmodel.ServerDrift, error) {
+	drift, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/server/%s/drift", h.ControllerURL, server),
+		networkmodel.ServerDrift{},
+	)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return drift, nil
+}
+
+// ComputeServerDrift computes the current drift of the passed server on its operator.
+func (h *HTTPClient) ComputeServerDrift(ctx context.Context, server uuid.UUID) (networkmodel.ServerDrift, error) {
+	drift, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/drift", h.ControllerURL, server, server),
+		networkmodel.ServerDrift{},
+	)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return drift, nil
+}
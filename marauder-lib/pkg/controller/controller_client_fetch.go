@@ -124,3 +124,84 @@ func (h *HTTPClient) FetchManifest(ctx context.Context, artefact uuid.UUID) (fil
 
 	return manifest, nil
 }
+
+// FetchArtefactIntegrity fetches the hash and signature of an artefact's tarball from the controller given the uuid.
+func (h *HTTPClient) FetchArtefactIntegrity(ctx context.Context, artefact uuid.UUID) (networkmodel.ArtefactIntegrity, error) {
+	integrity, err := utils.HTTPGetAndBind(ctx, h.Client, h.ControllerURL+"/artefact/"+artefact.String()+"/integrity", networkmodel.ArtefactIntegrity{})
+	if err != nil {
+		return networkmodel.ArtefactIntegrity{}, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return integrity, nil
+}
+
+// FetchServerFileConflicts fetches the files shipped by multiple artefacts of the passed state of the server.
+func (h *HTTPClient) FetchServerFileConflicts(
+	ctx context.Context,
+	server uuid.UUID,
+	state networkmodel.ServerStateType,
+) ([]networkmodel.FileConflict, error) {
+	conflicts, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/server/%s/state/%s/conflicts", h.ControllerURL, server, state),
+		make([]networkmodel.FileConflict, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// SearchArtefacts searches artefacts on the controller by the commit and branch they were built from or by a file they own.
+func (h *HTTPClient) SearchArtefacts(ctx context.Context, search networkmodel.ArtefactSearch) ([]networkmodel.ArtefactModel, error) {
+	artefacts, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/search/artefacts?%s", h.ControllerURL, search.QueryParameters().Encode()),
+		make([]networkmodel.ArtefactModel, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return artefacts, nil
+}
+
+// SearchServers searches servers on the controller whose passed state holds an artefact matching the search.
+func (h *HTTPClient) SearchServers(
+	ctx context.Context,
+	search networkmodel.ArtefactSearch,
+	state networkmodel.ServerStateType,
+) ([]networkmodel.ServerModel, error) {
+	parameters := search.QueryParameters()
+	parameters.Set("state", string(state))
+
+	servers, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/search/servers?%s", h.ControllerURL, parameters.Encode()),
+		make([]networkmodel.ServerModel, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return servers, nil
+}
+
+// FetchServerStatus fetches the live runtime status of the server from its operator via the controller.
+func (h *HTTPClient) FetchServerStatus(ctx context.Context, server uuid.UUID) (networkmodel.ServerRuntimeStatus, error) {
+	status, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/server/%s/status", h.ControllerURL, server),
+		networkmodel.ServerRuntimeStatus{},
+	)
+	if err != nil {
+		return networkmodel.ServerRuntimeStatus{}, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return status, nil
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
@@ -53,3 +56,35 @@ func (h *HTTPClient) ManageServerToggleSave(ctx context.Context, server uuid.UUI
 	}
 	return nil
 }
+
+// FetchServerLogs opens a stream of the container logs of the passed server from its operator.
+func (h *HTTPClient) FetchServerLogs(ctx context.Context, server uuid.UUID, options networkmodel.ServerLogOptions) (io.ReadCloser, error) {
+	resp, err := utils.PerformHTTPRequest(
+		ctx,
+		h.Client,
+		http.MethodGet,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/logs?%s", h.ControllerURL, server, server, options.QueryParameters().Encode()),
+		"application/json",
+		&bytes.Buffer{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get http: %w", err)
+	}
+
+	if err := utils.IsOkayStatusCodeOrErrorWithBody(resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch server logs: %w", err)
+	}
+
+	return resp.Body, nil
+}
+
+// AttachConsole attaches to the console of the passed server, tunneled through the controller via a websocket.
+func (h *HTTPClient) AttachConsole(ctx context.Context, server uuid.UUID) (*websocket.Conn, error) {
+	conn, err := utils.DialWebsocket(ctx, h.Client, fmt.Sprintf("%s/operator/%s/attach", h.ControllerURL, server))
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to console: %w", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// PlanServerUpdate computes the changes the passed update lifecycle action would perform on the server on its operator.
+func (h *HTTPClient) PlanServerUpdate(
+	ctx context.Context,
+	server uuid.UUID,
+	action networkmodel.LifecycleAction,
+) (networkmodel.UpdatePlan, error) {
+	plan, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/operator/%s/proxy/server/%s/plan?action=%s", h.ControllerURL, server, server, url.QueryEscape(string(action))),
+		networkmodel.UpdatePlan{},
+	)
+	if err != nil {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return plan, nil
+}
@@ -14,40 +14,73 @@ import (
 	"github.com/samber/mo"
 )
 
+// The ArtefactUpload holds the readers of an artefact and its signature published to the controller.
+type ArtefactUpload struct {
+	Artefact  io.Reader
+	Signature io.Reader
+}
+
 // PublishArtefact publishes the artefact read from the given readers to the controller.
 // The method returns the status code of the response for further usage.
 func (h *HTTPClient) PublishArtefact(ctx context.Context, artefact, signature io.Reader) (networkmodel.ArtefactModel, mo.Option[int], error) {
-	// Create multipart writer
-	var body bytes.Buffer
-	multipartWriter := multipart.NewWriter(&body)
+	var artefactResult networkmodel.ArtefactModel
 
-	// Write artefact
-	err := utils.WriteFileToMultipart(multipartWriter, artefact, "artefact")
+	statusCode, err := h.postArtefactUploads(ctx, "/artefact", []ArtefactUpload{{Artefact: artefact, Signature: signature}}, &artefactResult)
 	if err != nil {
-		return networkmodel.ArtefactModel{}, mo.None[int](), fmt.Errorf("failed to write artefact to request body: %w", err)
+		return networkmodel.ArtefactModel{}, statusCode, err
 	}
 
-	// Write signature
-	err = utils.WriteFileToMultipart(multipartWriter, signature, "signature")
+	return artefactResult, statusCode, nil
+}
+
+// PublishArtefacts publishes all passed artefacts to the controller at once.
+// Either all artefacts are published or none of them are.
+// The method returns the status code of the response for further usage.
+func (h *HTTPClient) PublishArtefacts(ctx context.Context, artefacts []ArtefactUpload) ([]networkmodel.ArtefactModel, mo.Option[int], error) {
+	artefactResults := make([]networkmodel.ArtefactModel, 0, len(artefacts))
+
+	statusCode, err := h.postArtefactUploads(ctx, "/artefacts", artefacts, &artefactResults)
 	if err != nil {
-		return networkmodel.ArtefactModel{}, mo.None[int](), fmt.Errorf("failed to write signature to request body: %w", err)
+		return nil, statusCode, err
+	}
+
+	return artefactResults, statusCode, nil
+}
+
+// postArtefactUploads posts the passed artefacts and their signatures as a multipart form to the passed endpoint of the
+// controller and binds the response into the passed result.
+func (h *HTTPClient) postArtefactUploads(ctx context.Context, endpoint string, artefacts []ArtefactUpload, result any) (mo.Option[int], error) {
+	// Create multipart writer
+	var body bytes.Buffer
+	multipartWriter := multipart.NewWriter(&body)
+
+	for _, artefact := range artefacts {
+		// Write artefact
+		if err := utils.WriteFileToMultipart(multipartWriter, artefact.Artefact, "artefact"); err != nil {
+			return mo.None[int](), fmt.Errorf("failed to write artefact to request body: %w", err)
+		}
+
+		// Write signature
+		if err := utils.WriteFileToMultipart(multipartWriter, artefact.Signature, "signature"); err != nil {
+			return mo.None[int](), fmt.Errorf("failed to write signature to request body: %w", err)
+		}
 	}
 
 	// Close the writer to finalise writing and flush to the bytes.Buffer
 	if err := multipartWriter.Close(); err != nil {
-		return networkmodel.ArtefactModel{}, mo.None[int](), fmt.Errorf("failed to close multipart writer: %w", err)
+		return mo.None[int](), fmt.Errorf("failed to close multipart writer: %w", err)
 	}
 
 	response, err := utils.PerformHTTPRequest(
 		ctx,
 		h.Client,
 		http.MethodPost,
-		h.ControllerURL+"/artefact",
+		h.ControllerURL+endpoint,
 		multipartWriter.FormDataContentType(),
 		&body,
 	)
 	if err != nil {
-		return networkmodel.ArtefactModel{}, mo.None[int](), fmt.Errorf("failed to post to controller: %w", err)
+		return mo.None[int](), fmt.Errorf("failed to post to controller: %w", err)
 	}
 
 	// Close response body
@@ -55,17 +88,16 @@ func (h *HTTPClient) PublishArtefact(ctx context.Context, artefact, signature io
 
 	bodyBytes, _ := io.ReadAll(response.Body)
 	if !utils.IsOkayStatusCode(response.StatusCode) {
-		return networkmodel.ArtefactModel{}, mo.Some(response.StatusCode), fmt.Errorf(
-			"received non-okay status code (%s): %w", string(bodyBytes), err,
+		return mo.Some(response.StatusCode), fmt.Errorf(
+			"received non-okay status code (%s): %w", string(bodyBytes), utils.ErrBadStatusCode,
 		)
 	}
 
-	var artefactResult networkmodel.ArtefactModel
-	if err := json.Unmarshal(bodyBytes, &artefactResult); err != nil {
-		return networkmodel.ArtefactModel{}, mo.Some(response.StatusCode), fmt.Errorf(
+	if err := json.Unmarshal(bodyBytes, result); err != nil {
+		return mo.Some(response.StatusCode), fmt.Errorf(
 			"failed to unmarshal controller result %s: %w", string(bodyBytes), err,
 		)
 	}
 
-	return artefactResult, mo.Some(response.StatusCode), nil
+	return mo.Some(response.StatusCode), nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
 
@@ -41,7 +42,7 @@ func (h *HTTPClient) UpdateState(
 
 	httpReq.Header.Set("Content-Type", "application/json") // Set content type as json, we are patching a json body in.
 
-	httpResp, err := h.Do(httpReq)
+	httpResp, err := tracing.DoHTTPRequest(h.Client, httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute http patch request: %w", err)
 	}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// FetchServerVariables fetches all variables defined for the passed server.
+func (h *HTTPClient) FetchServerVariables(ctx context.Context, server uuid.UUID) ([]networkmodel.ServerVariable, error) {
+	variables, err := utils.HTTPGetAndBind(
+		ctx,
+		h.Client,
+		fmt.Sprintf("%s/server/%s/variables", h.ControllerURL, server),
+		make([]networkmodel.ServerVariable, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed http get: %w", err)
+	}
+
+	return variables, nil
+}
+
+// SetServerVariable defines the value of the variable with the passed key for the server.
+func (h *HTTPClient) SetServerVariable(ctx context.Context, server uuid.UUID, key string, value string) error {
+	body, err := json.Marshal(networkmodel.UpdateServerVariableRequest{Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal variable request: %w", err)
+	}
+
+	resp, err := utils.PerformHTTPRequest(
+		ctx,
+		h.Client,
+		http.MethodPut,
+		fmt.Sprintf("%s/server/%s/variables/%s", h.ControllerURL, server, url.PathEscape(key)),
+		"application/json",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put http: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := utils.IsOkayStatusCodeOrErrorWithBody(resp); err != nil {
+		return fmt.Errorf("failed to set variable: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteServerVariable deletes the variable with the passed key of the server.
+func (h *HTTPClient) DeleteServerVariable(ctx context.Context, server uuid.UUID, key string) error {
+	resp, err := utils.PerformHTTPRequest(
+		ctx,
+		h.Client,
+		http.MethodDelete,
+		fmt.Sprintf("%s/server/%s/variables/%s", h.ControllerURL, server, url.PathEscape(key)),
+		"application/json",
+		&bytes.Buffer{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete http: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := utils.IsOkayStatusCodeOrErrorWithBody(resp); err != nil {
+		return fmt.Errorf("failed to delete variable: %w", err)
+	}
+
+	return nil
+}
@@ -6,14 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"net/url"
 	"reflect"
+	"strings"
 
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/pelletier/go-toml/v2"
 	"gopkg.in/yaml.v3"
 )
 
-// ErrUnknownFileEquality may be returned if a file equality is not found for a string identifier in a FileEqualityRegistry.
-var ErrUnknownFileEquality = errors.New("unknown file equality")
+var (
+	// ErrUnknownFileEquality may be returned if a file equality is not found for a string identifier in a FileEqualityRegistry.
+	ErrUnknownFileEquality = errors.New("unknown file equality")
+
+	// ErrInvalidFileEqualityParameters may be returned if the parameters of a file equality identifier are unknown or not
+	// supported by the file equality.
+	ErrInvalidFileEqualityParameters = errors.New("invalid file equality parameters")
+)
 
 // The FileEqualityRegistry allows map-like access to file equality implementations for later consumption.
 type FileEqualityRegistry map[string]FileEquality
@@ -21,13 +31,58 @@ type FileEqualityRegistry map[string]FileEquality
 // DefaultFileEqualityRegistry constructs a new, default filled, file equality registry.
 func DefaultFileEqualityRegistry() FileEqualityRegistry {
 	return FileEqualityRegistry{
-		"hash": Sha256SumFileEquality{},
-		"noop": NOOPFileEquality{},
-		"json": JSONFileEquality{},
-		"yaml": YAMLFileEquality{},
+		"hash":       Sha256SumFileEquality{},
+		"noop":       NOOPFileEquality{},
+		"json":       JSONFileEquality{},
+		"yaml":       YAMLFileEquality{},
+		"toml":       TOMLFileEquality{},
+		"properties": PropertiesFileEquality{},
 	}
 }
 
+// Lookup looks up the file equality for the passed identifier.
+// Identifiers may parametrise the file equality in a query like syntax, e.g. `yaml?ignore=metrics.uuid,config-version`
+// compares yaml files while ignoring the keys `uuid` nested in `metrics` and `config-version`.
+func (f FileEqualityRegistry) Lookup(identifier string) (FileEquality, error) {
+	name, rawParameters, parametrised := strings.Cut(identifier, "?")
+
+	fileEquality, found := f[name]
+	if !found {
+		return nil, fmt.Errorf("%s is an unknown file equality: %w", name, ErrUnknownFileEquality)
+	}
+
+	if !parametrised {
+		return fileEquality, nil
+	}
+
+	parameters, err := url.ParseQuery(rawParameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse parameters of %s: %w", identifier, errors.Join(ErrInvalidFileEqualityParameters, err))
+	}
+
+	ignoredKeys := make([]string, 0)
+	for parameter, values := range parameters {
+		if parameter != "ignore" {
+			return nil, fmt.Errorf("%s is an unknown parameter of %s: %w", parameter, name, ErrInvalidFileEqualityParameters)
+		}
+
+		for _, value := range values {
+			for key := range strings.SplitSeq(value, ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					ignoredKeys = append(ignoredKeys, key)
+				}
+			}
+		}
+	}
+
+	keyIgnoringFileEquality, ok := fileEquality.(KeyIgnoringFileEquality)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support ignoring keys: %w", name, ErrInvalidFileEqualityParameters)
+	}
+
+	return keyIgnoringFileEquality.IgnoringKeys(ignoredKeys), nil
+}
+
 // The FileEquality interface defines a single function that determines if two files, based on their content
 // are to be considered equal.
 type FileEquality interface {
@@ -39,6 +94,14 @@ type FileEquality interface {
 	Equals(first io.Reader, second io.Reader) (bool, error)
 }
 
+// The KeyIgnoringFileEquality is a FileEquality of structured files that can ignore keys of the files when comparing them.
+type KeyIgnoringFileEquality interface {
+	FileEquality
+
+	// IgnoringKeys yields back a copy of the file equality that ignores the passed keys.
+	IgnoringKeys(keys []string) FileEquality
+}
+
 // The Sha256SumFileEquality compares two files via their sha256hash.
 type Sha256SumFileEquality struct{}
 
@@ -62,7 +125,10 @@ func (n NOOPFileEquality) Equals(_ io.Reader, _ io.Reader) (bool, error) {
 
 // The JSONFileEquality compares files by parsing them as json.
 // If either file cannot be parsed as json comparison errors (not fails).
-type JSONFileEquality struct{}
+type JSONFileEquality struct {
+	// IgnoredKeys holds the dot separated paths of the keys ignored in the comparison.
+	IgnoredKeys []string
+}
 
 func (j JSONFileEquality) Equals(first io.Reader, second io.Reader) (bool, error) {
 	return compareReadersByCmp(first, second, func(reader io.Reader) (any, error) {
@@ -71,13 +137,20 @@ func (j JSONFileEquality) Equals(first io.Reader, second io.Reader) (bool, error
 			return nil, fmt.Errorf("failed to decode json: %w", err)
 		}
 
-		return result, nil
+		return withoutKeys(result, j.IgnoredKeys), nil
 	}, reflect.DeepEqual)
 }
 
+func (j JSONFileEquality) IgnoringKeys(keys []string) FileEquality {
+	return JSONFileEquality{IgnoredKeys: keys}
+}
+
 // The YAMLFileEquality compares files by parsing them as yaml.
 // If either file cannot be parsed as yaml comparison errors (not fails).
-type YAMLFileEquality struct{}
+type YAMLFileEquality struct {
+	// IgnoredKeys holds the dot separated paths of the keys ignored in the comparison.
+	IgnoredKeys []string
+}
 
 func (y YAMLFileEquality) Equals(first io.Reader, second io.Reader) (bool, error) {
 	return compareReadersByCmp(first, second, func(reader io.Reader) (any, error) {
@@ -86,10 +159,87 @@ func (y YAMLFileEquality) Equals(first io.Reader, second io.Reader) (bool, error
 			return nil, fmt.Errorf("failed to decode yaml: %w", err)
 		}
 
-		return result, nil
+		return withoutKeys(result, y.IgnoredKeys), nil
+	}, reflect.DeepEqual)
+}
+
+func (y YAMLFileEquality) IgnoringKeys(keys []string) FileEquality {
+	return YAMLFileEquality{IgnoredKeys: keys}
+}
+
+// The TOMLFileEquality compares files by parsing them as toml.
+// If either file cannot be parsed as toml comparison errors (not fails).
+type TOMLFileEquality struct {
+	// IgnoredKeys holds the dot separated paths of the keys ignored in the comparison.
+	IgnoredKeys []string
+}
+
+func (t TOMLFileEquality) Equals(first io.Reader, second io.Reader) (bool, error) {
+	return compareReadersByCmp(first, second, func(reader io.Reader) (any, error) {
+		result := make(map[string]any)
+		if err := toml.NewDecoder(reader).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode toml: %w", err)
+		}
+
+		return withoutKeys(result, t.IgnoredKeys), nil
 	}, reflect.DeepEqual)
 }
 
+func (t TOMLFileEquality) IgnoringKeys(keys []string) FileEquality {
+	return TOMLFileEquality{IgnoredKeys: keys}
+}
+
+// The PropertiesFileEquality compares files by parsing them as java properties files.
+// Properties are flat, ignored keys hence match the full key of a property, e.g. `server-port`.
+// If either file cannot be parsed as properties comparison errors (not fails).
+type PropertiesFileEquality struct {
+	// IgnoredKeys holds the keys of the properties ignored in the comparison.
+	IgnoredKeys []string
+}
+
+func (p PropertiesFileEquality) Equals(first io.Reader, second io.Reader) (bool, error) {
+	return compareReadersByCmp(first, second, func(reader io.Reader) (map[string]string, error) {
+		result, err := parseProperties(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode properties: %w", err)
+		}
+
+		for _, key := range p.IgnoredKeys {
+			delete(result, key)
+		}
+
+		return result, nil
+	}, maps.Equal)
+}
+
+func (p PropertiesFileEquality) IgnoringKeys(keys []string) FileEquality {
+	return PropertiesFileEquality{IgnoredKeys: keys}
+}
+
+// withoutKeys removes the keys at the passed dot separated paths from the decoded value.
+func withoutKeys(value any, keys []string) any {
+	for _, key := range keys {
+		removeKey(value, strings.Split(key, "."))
+	}
+
+	return value
+}
+
+// removeKey removes the key at the passed path segments from the decoded value if present.
+func removeKey(value any, segments []string) {
+	mapValue, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if len(segments) == 1 {
+		delete(mapValue, segments[0])
+		return
+	}
+
+	removeKey(mapValue[segments[0]], segments[1:])
+}
+
 // compareReadersBy is a utility method that compares two readers by mapping them via the passed mapper function and comparing their result.
 func compareReadersBy[T comparable](first io.Reader, second io.Reader, mapper func(reader io.Reader) (T, error)) (bool, error) {
 	return compareReadersByCmp(first, second, mapper, func(first T, second T) bool {
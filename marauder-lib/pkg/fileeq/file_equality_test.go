@@ -0,0 +1,110 @@
+package fileeq_test
+
+import (
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Comparing files", Label("unittest"), func() {
+	registry := fileeq.DefaultFileEqualityRegistry()
+
+	equals := func(identifier string, first string, second string) (bool, error) {
+		fileEquality, err := registry.Lookup(identifier)
+		Expect(err).To(Not(HaveOccurred()))
+
+		return fileEquality.Equals(strings.NewReader(first), strings.NewReader(second))
+	}
+
+	Describe("looking up file equalities", func() {
+		It("should fail on unknown file equalities", func() {
+			_, err := registry.Lookup("xml")
+			Expect(err).To(MatchError(fileeq.ErrUnknownFileEquality))
+		})
+
+		It("should fail on unknown parameters", func() {
+			_, err := registry.Lookup("yaml?skip=config-version")
+			Expect(err).To(MatchError(fileeq.ErrInvalidFileEqualityParameters))
+		})
+
+		It("should fail on file equalities that cannot ignore keys", func() {
+			_, err := registry.Lookup("hash?ignore=config-version")
+			Expect(err).To(MatchError(fileeq.ErrInvalidFileEqualityParameters))
+		})
+
+		It("should parametrise file equalities with the ignored keys", func() {
+			Expect(registry.Lookup("yaml?ignore=metrics.uuid, config-version")).To(Equal(fileeq.YAMLFileEquality{
+				IgnoredKeys: []string{"metrics.uuid", "config-version"},
+			}))
+		})
+	})
+
+	Describe("as yaml", func() {
+		It("should ignore the passed nested keys", func() {
+			Expect(equals(
+				"yaml?ignore=metrics.uuid,config-version",
+				"config-version: 3\nmetrics:\n  enabled: true\n  uuid: a\n",
+				"config-version: 4\nmetrics:\n  enabled: true\n  uuid: b\n",
+			)).To(BeTrue())
+
+			Expect(equals(
+				"yaml?ignore=metrics.uuid",
+				"metrics:\n  enabled: true\n  uuid: a\n",
+				"metrics:\n  enabled: false\n  uuid: b\n",
+			)).To(BeFalse())
+		})
+	})
+
+	Describe("as json", func() {
+		It("should ignore the passed keys", func() {
+			Expect(equals("json?ignore=generated", `{"a": 1, "generated": "monday"}`, `{"generated": "tuesday", "a": 1}`)).To(BeTrue())
+		})
+	})
+
+	Describe("as toml", func() {
+		It("should compare files semantically", func() {
+			Expect(equals("toml", "[server]\nport = 25565\nname = \"lobby\"\n", "[server]\nname = \"lobby\"\nport = 25565\n")).To(BeTrue())
+			Expect(equals("toml", "[server]\nport = 25565\n", "[server]\nport = 25566\n")).To(BeFalse())
+		})
+
+		It("should ignore the passed nested keys", func() {
+			Expect(equals("toml?ignore=server.seed", "[server]\nport = 1\nseed = 2\n", "[server]\nport = 1\nseed = 3\n")).To(BeTrue())
+		})
+
+		It("should error on files that do not parse", func() {
+			_, err := equals("toml", "[server\n", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("as properties", func() {
+		It("should compare files semantically", func() {
+			Expect(equals(
+				"properties",
+				"#Minecraft server properties\n#Mon Jan 01 00:00:00 UTC 2024\nmotd=A Minecraft Server\nserver-port=25565\n",
+				"server-port = 25565\n! other comment\n\nmotd:A Minecraft Server\n",
+			)).To(BeTrue())
+
+			Expect(equals("properties", "server-port=25565\n", "server-port=25566\n")).To(BeFalse())
+		})
+
+		It("should handle line continuations and escapes", func() {
+			Expect(equals(
+				"properties",
+				"motd=Hello \\\n    World\ngreeting=caf\\u00e9\nkey\\ with\\ spaces=value\n",
+				"motd=Hello World\ngreeting=café\nkey\\ with\\ spaces value\n",
+			)).To(BeTrue())
+		})
+
+		It("should ignore the passed keys", func() {
+			Expect(equals("properties?ignore=level-seed", "level-seed=1\npvp=true\n", "level-seed=2\npvp=true\n")).To(BeTrue())
+		})
+
+		It("should error on malformed unicode escapes", func() {
+			_, err := equals("properties", "motd=\\u00zz\n", "")
+			Expect(err).To(MatchError(fileeq.ErrInvalidPropertiesEscape))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package fileeq
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPropertiesEscape is returned if a properties file contains a malformed unicode escape sequence.
+var ErrInvalidPropertiesEscape = errors.New("invalid properties escape")
+
+// parseProperties parses the passed reader as a java properties file into a map of its keys to their values.
+// Comments, blank lines, line continuations and escape sequences are handled as defined by java.util.Properties.
+func parseProperties(reader io.Reader) (map[string]string, error) {
+	result := make(map[string]string)
+
+	scanner := bufio.NewScanner(reader)
+	logicalLine := ""
+	continued := false
+
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if !continued && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		logicalLine += line
+		if continued = endsWithContinuation(logicalLine); continued {
+			logicalLine = logicalLine[:len(logicalLine)-1]
+			continue
+		}
+
+		key, value, err := parsePropertyLine(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+		logicalLine = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read properties: %w", err)
+	}
+
+	if logicalLine != "" {
+		key, value, err := parsePropertyLine(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// endsWithContinuation returns if the line ends in an odd amount of backslashes, continuing it on the next line.
+func endsWithContinuation(line string) bool {
+	backslashes := len(line) - len(strings.TrimRight(line, "\\"))
+	return backslashes%2 == 1
+}
+
+// parsePropertyLine parses a single logical line of a properties file into its unescaped key and value.
+func parsePropertyLine(line string) (string, string, error) {
+	keyEnd := len(line)
+	for index := 0; index < len(line); index++ {
+		if line[index] == '\\' {
+			index++
+			continue
+		}
+
+		if strings.ContainsRune("=: \t\f", rune(line[index])) {
+			keyEnd = index
+			break
+		}
+	}
+
+	rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	key, err := unescapeProperty(line[:keyEnd])
+	if err != nil {
+		return "", "", err
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// unescapeProperty resolves the escape sequences of a key or value in a properties file.
+func unescapeProperty(escaped string) (string, error) {
+	if !strings.Contains(escaped, "\\") {
+		return escaped, nil
+	}
+
+	var builder strings.Builder
+	for index := 0; index < len(escaped); index++ {
+		if escaped[index] != '\\' || index+1 == len(escaped) {
+			builder.WriteByte(escaped[index])
+			continue
+		}
+
+		index++
+		switch escaped[index] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if index+4 >= len(escaped) {
+				return "", fmt.Errorf("truncated unicode escape in %q: %w", escaped, ErrInvalidPropertiesEscape)
+			}
+
+			codePoint, err := strconv.ParseUint(escaped[index+1:index+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed unicode escape in %q: %w", escaped, errors.Join(ErrInvalidPropertiesEscape, err))
+			}
+
+			builder.WriteRune(rune(codePoint))
+			index += 4
+		default:
+			builder.WriteByte(escaped[index])
+		}
+	}
+
+	return builder.String(), nil
+}
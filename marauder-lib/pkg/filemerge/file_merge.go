@@ -0,0 +1,217 @@
+package filemerge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Goldziher/go-utils/maputils"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownFileMerge may be returned if a file merge is not found for a string identifier in a FileMergeRegistry.
+	ErrUnknownFileMerge = errors.New("unknown file merge")
+
+	// ErrMergeConflict is returned if a three-way merge found keys changed both locally and by the new version of a file.
+	ErrMergeConflict = errors.New("merge conflict")
+)
+
+// The FileMergeRegistry allows map-like access to file merge implementations for later consumption.
+type FileMergeRegistry map[string]FileMerge
+
+// DefaultFileMergeRegistry constructs a new, default filled, file merge registry.
+func DefaultFileMergeRegistry() FileMergeRegistry {
+	return FileMergeRegistry{
+		"json": JSONFileMerge{},
+		"yaml": YAMLFileMerge{},
+	}
+}
+
+// The MergeResult holds the result of a three-way merge of a file.
+type MergeResult struct {
+	// Content is the merged content of the file.
+	Content []byte
+
+	// Conflicts holds the dot separated paths of all keys that were changed both locally and by the new version of the
+	// file to different values. Conflicts are resolved in favour of the new version.
+	Conflicts []string
+}
+
+// The FileMerge interface defines a three-way merge of structured files.
+type FileMerge interface {
+	// Merge merges the local changes made to the base file into the target file, the new version of the base file.
+	// Keys only changed locally keep their local value, keys changed by the target take the value of the target.
+	// If either file cannot be parsed, an error is returned.
+	Merge(base []byte, local []byte, target []byte) (MergeResult, error)
+}
+
+// The JSONFileMerge merges files by parsing them as json.
+// Files changed both locally and by the target are re-serialised, formatting of the files is not preserved.
+type JSONFileMerge struct{}
+
+func (j JSONFileMerge) Merge(base []byte, local []byte, target []byte) (MergeResult, error) {
+	return mergeBy(base, local, target, func(content []byte) (any, error) {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+
+		var result any
+		if err := decoder.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode json: %w", err)
+		}
+
+		return result, nil
+	}, func(value any) ([]byte, error) {
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode json: %w", err)
+		}
+
+		return append(content, '\n'), nil
+	})
+}
+
+// The YAMLFileMerge merges files by parsing them as yaml.
+// Files changed both locally and by the target are re-serialised, comments and formatting of the files are not preserved.
+type YAMLFileMerge struct{}
+
+func (y YAMLFileMerge) Merge(base []byte, local []byte, target []byte) (MergeResult, error) {
+	return mergeBy(base, local, target, func(content []byte) (any, error) {
+		var result any
+		if err := yaml.NewDecoder(bytes.NewReader(content)).Decode(&result); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode yaml: %w", err)
+		}
+
+		return result, nil
+	}, func(value any) ([]byte, error) {
+		var buffer bytes.Buffer
+
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(value); err != nil {
+			return nil, fmt.Errorf("failed to encode yaml: %w", err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode yaml: %w", err)
+		}
+
+		return buffer.Bytes(), nil
+	})
+}
+
+// mergeBy is a utility method that three-way merges the passed files by decoding them via the passed decoder.
+// If only one side changed the base, its content is yielded back as is, only merged values are encoded via the passed encoder.
+func mergeBy(
+	base []byte,
+	local []byte,
+	target []byte,
+	decode func(content []byte) (any, error),
+	encode func(value any) ([]byte, error),
+) (MergeResult, error) {
+	baseValue, err := decode(base)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to decode base file: %w", err)
+	}
+
+	localValue, err := decode(local)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to decode local file: %w", err)
+	}
+
+	targetValue, err := decode(target)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to decode target file: %w", err)
+	}
+
+	switch {
+	case reflect.DeepEqual(localValue, baseValue), reflect.DeepEqual(localValue, targetValue):
+		return MergeResult{Content: target, Conflicts: make([]string, 0)}, nil
+	case reflect.DeepEqual(targetValue, baseValue):
+		return MergeResult{Content: local, Conflicts: make([]string, 0)}, nil
+	}
+
+	conflicts := make([]string, 0)
+	merged, _ := mergeValues("", baseValue, true, localValue, true, targetValue, true, &conflicts)
+
+	content, err := encode(merged)
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	return MergeResult{Content: content, Conflicts: conflicts}, nil
+}
+
+// mergeValues three-way merges a single value found at the passed path, yielding back the merged value and if it is present.
+// Each value is accompanied by a flag indicating if the value is present, distinguishing absent keys from null values.
+//
+//nolint:cyclop
+func mergeValues(
+	path string,
+	base any, inBase bool,
+	local any, inLocal bool,
+	target any, inTarget bool,
+	conflicts *[]string,
+) (any, bool) {
+	localUnchanged := inLocal == inBase && reflect.DeepEqual(local, base)
+	targetUnchanged := inTarget == inBase && reflect.DeepEqual(target, base)
+
+	switch {
+	case localUnchanged:
+		return target, inTarget
+	case targetUnchanged:
+		return local, inLocal
+	case inLocal == inTarget && reflect.DeepEqual(local, target):
+		return target, inTarget
+	}
+
+	baseMap, baseIsMap := base.(map[string]any)
+	localMap, localIsMap := local.(map[string]any)
+	targetMap, targetIsMap := target.(map[string]any)
+	if !inBase {
+		baseMap, baseIsMap = make(map[string]any), true
+	}
+
+	if !baseIsMap || !localIsMap || !targetIsMap {
+		*conflicts = append(*conflicts, pathOrRoot(path))
+		return target, inTarget
+	}
+
+	keys := append(append(maputils.Keys(baseMap), maputils.Keys(localMap)...), maputils.Keys(targetMap)...)
+	slices.Sort(keys)
+
+	merged := make(map[string]any)
+	for _, key := range slices.Compact(keys) {
+		baseValue, keyInBase := baseMap[key]
+		localValue, keyInLocal := localMap[key]
+		targetValue, keyInTarget := targetMap[key]
+
+		value, present := mergeValues(
+			strings.TrimPrefix(path+"."+key, "."),
+			baseValue, keyInBase,
+			localValue, keyInLocal,
+			targetValue, keyInTarget,
+			conflicts,
+		)
+		if present {
+			merged[key] = value
+		}
+	}
+
+	return merged, true
+}
+
+// pathOrRoot yields back the passed path or a marker for the root of the file if the path is empty.
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+
+	return path
+}
@@ -0,0 +1,91 @@
+package filemerge_test
+
+import (
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merging files", Label("unittest"), func() {
+	Describe("as yaml", func() {
+		merge := filemerge.YAMLFileMerge{}
+		base := []byte("# defaults\nmotd: hello\nslots: 20\ndatabase:\n  host: localhost\n  port: 5432\n")
+
+		It("should yield back the target verbatim if nothing changed locally", func() {
+			target := []byte("# new defaults\nmotd: hello\nslots: 30\n")
+
+			result, err := merge.Merge(base, []byte("motd: hello\nslots: 20\ndatabase: {host: localhost, port: 5432}\n"), target)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Content).To(Equal(target))
+			Expect(result.Conflicts).To(BeEmpty())
+		})
+
+		It("should yield back the local file verbatim if the target did not change", func() {
+			local := []byte("# tuned\nmotd: welcome\nslots: 20\ndatabase:\n  host: db\n  port: 5432\n")
+
+			result, err := merge.Merge(base, local, base)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Content).To(Equal(local))
+		})
+
+		It("should keep local changes to keys the target did not touch", func() {
+			local := []byte("motd: welcome\nslots: 20\ndatabase:\n  host: db\n  port: 5432\n")
+			target := []byte("motd: hello\nslots: 20\ndatabase:\n  host: localhost\n  port: 5433\n  pool: 10\n")
+
+			result, err := merge.Merge(base, local, target)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Conflicts).To(BeEmpty())
+			Expect(string(result.Content)).To(MatchYAML("motd: welcome\nslots: 20\ndatabase:\n  host: db\n  port: 5433\n  pool: 10\n"))
+		})
+
+		It("should apply keys removed by either side", func() {
+			local := []byte("motd: hello\ndatabase:\n  host: db\n  port: 5432\n")
+			target := []byte("motd: hello\nslots: 20\ndatabase:\n  host: localhost\n")
+
+			result, err := merge.Merge(base, local, target)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(string(result.Content)).To(MatchYAML("motd: hello\ndatabase:\n  host: db\n"))
+		})
+
+		It("should report conflicts and resolve them in favour of the target", func() {
+			local := []byte("motd: welcome\nslots: 50\ndatabase:\n  host: localhost\n  port: 5432\n")
+			target := []byte("motd: hello\nslots: 30\ndatabase:\n  host: localhost\n  port: 6543\n")
+
+			result, err := merge.Merge(base, local, target)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Conflicts).To(Equal([]string{"slots"}))
+			Expect(string(result.Content)).To(MatchYAML("motd: welcome\nslots: 30\ndatabase:\n  host: localhost\n  port: 6543\n"))
+		})
+
+		It("should fail on files that do not parse", func() {
+			_, err := merge.Merge(base, []byte("motd: [unclosed"), base)
+			Expect(err).To(MatchError(ContainSubstring("local file")))
+		})
+	})
+
+	Describe("as json", func() {
+		merge := filemerge.JSONFileMerge{}
+
+		It("should keep local changes to keys the target did not touch", func() {
+			result, err := merge.Merge(
+				[]byte(`{"motd": "hello", "limits": {"slots": 20, "ratio": 0.5}}`),
+				[]byte(`{"motd": "welcome", "limits": {"slots": 20, "ratio": 0.5}}`),
+				[]byte(`{"motd": "hello", "limits": {"slots": 30, "ratio": 0.5}, "pvp": true}`),
+			)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Conflicts).To(BeEmpty())
+			Expect(string(result.Content)).To(MatchJSON(`{"motd": "welcome", "limits": {"slots": 30, "ratio": 0.5}, "pvp": true}`))
+		})
+
+		It("should report conflicting nested keys by their path", func() {
+			result, err := merge.Merge(
+				[]byte(`{"limits": {"slots": 20}}`),
+				[]byte(`{"limits": {"slots": 50}}`),
+				[]byte(`{"limits": {"slots": 30}}`),
+			)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(result.Conflicts).To(Equal([]string{"limits.slots"}))
+			Expect(string(result.Content)).To(MatchJSON(`{"limits": {"slots": 30}}`))
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/jsonschema"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type color string
+
+func (c color) EnumValues() []string {
+	return []string{"red", "green"}
+}
+
+type embeddedSpell struct {
+	School string `json:"school"`
+}
+
+type spell struct {
+	embeddedSpell
+
+	Name      string             `json:"name"`
+	Color     *color             `json:"color,omitempty"`
+	Mana      uint32             `json:"mana,omitempty"`
+	Range     float64            `json:"range,omitempty"`
+	Tags      []string           `json:"tags,omitempty"`
+	Effects   map[string]int     `json:"effects,omitempty"`
+	CastAt    time.Time          `json:"castAt,omitzero"`
+	Ignored   string             `json:"-"`
+	internal  string             //nolint:unused
+	Reagents  map[string]reagent `json:"reagents,omitempty"`
+	Stackable bool               `json:"stackable,omitempty"`
+}
+
+type reagent struct {
+	Amount int `json:"amount"`
+}
+
+var _ = Describe("JSON schemas", Label("unittest"), func() {
+	schema := jsonschema.Generate(reflect.TypeFor[spell]())
+
+	Describe("generating a schema", func() {
+		It("should describe the fields of structs by their json name", func() {
+			Expect(schema.Type).To(Equal("object"))
+			Expect(schema.Properties).To(HaveKey("school"))
+			Expect(schema.Properties).To(Not(HaveKey("Ignored")))
+			Expect(schema.Properties).To(Not(HaveKey("internal")))
+			Expect(schema.Required).To(Equal([]string{"name", "school"}))
+			Expect(schema.AdditionalProperties).To(BeFalse())
+		})
+
+		It("should restrict enumerated types to their values", func() {
+			Expect(schema.Properties["color"].Enum).To(Equal([]string{"red", "green"}))
+		})
+
+		It("should describe special types", func() {
+			Expect(schema.Properties["mana"].Minimum).To(HaveValue(Equal(0)))
+			Expect(schema.Properties["castAt"].Format).To(Equal("date-time"))
+			Expect(schema.Properties["effects"].AdditionalProperties).To(Equal(&jsonschema.Schema{Type: "integer"}))
+		})
+	})
+
+	Describe("validating a document", func() {
+		It("should accept conforming documents", func() {
+			Expect(schema.Validate([]byte(`{
+				"name": "lumos", "school": "charms", "color": "red", "mana": 4, "range": 2.5, "tags": ["light"],
+				"effects": {"light": 1}, "castAt": "2024-01-02T03:04:05Z", "reagents": {"wand": {"amount": 1}}, "stackable": true
+			}`))).To(Succeed())
+		})
+
+		It("should report all violations by their json pointer", func() {
+			err := schema.Validate([]byte(`{
+				"nmae": "lumos", "school": 1, "color": "blue", "mana": -1, "range": "far", "tags": [true],
+				"effects": {"light": 1.5}, "castAt": "yesterday", "reagents": {"wand/oak": {}}, "stackable": "yes"
+			}`))
+
+			Expect(err).To(MatchError(jsonschema.ErrSchemaViolation))
+			Expect(err.Error()).To(And(
+				ContainSubstring(`<root>: missing property "name"`),
+				ContainSubstring(`<root>: unknown property "nmae"`),
+				ContainSubstring("/school: expected a string"),
+				ContainSubstring(`/color: "blue" is not one of`),
+				ContainSubstring("/mana: -1 is less than 0"),
+				ContainSubstring("/range: expected a number"),
+				ContainSubstring("/tags/0: expected a string"),
+				ContainSubstring("/effects/light: 1.5 is not an integer"),
+				ContainSubstring(`/castAt: "yesterday" is not a date-time`),
+				ContainSubstring(`/reagents/wand~1oak: missing property "amount"`),
+				ContainSubstring("/stackable: expected a boolean"),
+			))
+		})
+
+		It("should fail on malformed documents", func() {
+			Expect(schema.Validate([]byte(`{"name": `))).To(MatchError(ContainSubstring("failed to decode json document")))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package jsonschema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Draft is the json schema dialect generated schemas declare.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// The Enumerated interface may be implemented by string based types that only allow a fixed set of values.
+// The generated schema of such types restricts them to the yielded values.
+type Enumerated interface {
+	EnumValues() []string
+}
+
+// The Schema type represents the subset of a json schema marauder generates for its go types.
+type Schema struct {
+	Schema string `json:"$schema,omitempty"`
+	ID     string `json:"$id,omitempty"`
+	Title  string `json:"title,omitempty"`
+
+	Type    string   `json:"type,omitempty"`
+	Format  string   `json:"format,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+	Minimum *int     `json:"minimum,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+
+	// AdditionalProperties is either false, rejecting unknown properties of an object, or the schema of all values of a map.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	enumeratedType = reflect.TypeFor[Enumerated]()
+)
+
+// Generate generates the json schema of the passed go type based on the json tags of its fields.
+// Struct fields not tagged with omitempty or omitzero are required and unknown properties of structs are rejected.
+func Generate(goType reflect.Type) *Schema {
+	if goType.Kind() == reflect.Pointer {
+		return Generate(goType.Elem())
+	}
+
+	if goType == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if goType.Kind() == reflect.String && goType.Implements(enumeratedType) {
+		enumerated, _ := reflect.Zero(goType).Interface().(Enumerated)
+		return &Schema{Type: "string", Enum: enumerated.EnumValues()}
+	}
+
+	switch goType.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: new(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: Generate(goType.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: Generate(goType.Elem())}
+	case reflect.Struct:
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema), Required: make([]string, 0), AdditionalProperties: false}
+		generateProperties(goType, schema)
+		slices.Sort(schema.Required)
+
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+// generateProperties generates the properties of all exported fields of the passed struct type into the passed schema.
+// Fields of embedded structs without a json name are promoted into the schema like encoding/json does.
+func generateProperties(structType reflect.Type, schema *Schema) {
+	for field := range structType.Fields() {
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			generateProperties(field.Type, schema)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = Generate(field.Type)
+		optionList := strings.Split(options, ",")
+		if !slices.Contains(optionList, "omitempty") && !slices.Contains(optionList, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSchemaViolation is returned if a json document does not conform to a schema.
+var ErrSchemaViolation = errors.New("schema violation")
+
+// Validate validates the passed json document against the schema.
+// All violations found in the document are joined into the returned error, each prefixed by the json pointer to the
+// offending value.
+func (s *Schema) Validate(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("failed to decode json document: %w", err)
+	}
+
+	violations := make([]error, 0)
+	s.validateValue("", value, &violations)
+
+	return errors.Join(violations...)
+}
+
+// validateValue validates a single decoded value found at the passed json pointer, appending all violations found.
+//
+//nolint:cyclop
+func (s *Schema) validateValue(pointer string, value any, violations *[]error) {
+	violation := func(format string, args ...any) {
+		*violations = append(*violations, fmt.Errorf("%s: %s: %w", pointerOrRoot(pointer), fmt.Sprintf(format, args...), ErrSchemaViolation))
+	}
+
+	switch s.Type {
+	case "":
+		return
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			violation("expected an object")
+			return
+		}
+
+		s.validateObject(pointer, object, violations)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			violation("expected an array")
+			return
+		}
+
+		for index, item := range array {
+			s.Items.validateValue(pointer+"/"+strconv.Itoa(index), item, violations)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			violation("expected a string")
+			return
+		}
+
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, text) {
+			violation("%q is not one of %q", text, s.Enum)
+		}
+
+		if _, err := time.Parse(time.RFC3339, text); s.Format == "date-time" && err != nil {
+			violation("%q is not a date-time", text)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			violation("expected a boolean")
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			violation("expected a number")
+			return
+		}
+
+		parsed, err := number.Float64()
+		switch {
+		case err != nil:
+			violation("%s is not a number", number)
+		case s.Type == "integer" && parsed != math.Trunc(parsed):
+			violation("%s is not an integer", number)
+		case s.Minimum != nil && parsed < float64(*s.Minimum):
+			violation("%s is less than %d", number, *s.Minimum)
+		}
+	}
+}
+
+// validateObject validates the properties of the passed object, appending all violations found.
+func (s *Schema) validateObject(pointer string, object map[string]any, violations *[]error) {
+	for _, required := range s.Required {
+		if _, found := object[required]; !found {
+			*violations = append(*violations, fmt.Errorf("%s: missing property %q: %w", pointerOrRoot(pointer), required, ErrSchemaViolation))
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		propertyPointer := pointer + "/" + escapePointerToken(key)
+
+		if property, found := s.Properties[key]; found {
+			property.validateValue(propertyPointer, object[key], violations)
+			continue
+		}
+
+		switch additional := s.AdditionalProperties.(type) {
+		case *Schema:
+			additional.validateValue(propertyPointer, object[key], violations)
+		case bool:
+			if !additional {
+				*violations = append(*violations, fmt.Errorf("%s: unknown property %q: %w", pointerOrRoot(pointer), key, ErrSchemaViolation))
+			}
+		}
+	}
+}
+
+// escapePointerToken escapes a single reference token of a json pointer as defined by RFC 6901.
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// pointerOrRoot yields back the passed json pointer or a marker for the root of the document if the pointer is empty.
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "<root>"
+	}
+
+	return pointer
+}
@@ -0,0 +1,383 @@
+package keyauth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// ErrUnknownSigningKey is returned if a signature was not created by any key known to the registry.
+	ErrUnknownSigningKey = errors.New("unknown signing key")
+
+	// ErrSigningKeyRevoked is returned if a signature was created by a revoked key.
+	ErrSigningKeyRevoked = errors.New("signing key revoked")
+
+	// ErrSigningKeyNotValid is returned if a signature is verified outside the validity window of the key that created it.
+	ErrSigningKeyNotValid = errors.New("signing key not valid")
+
+	// ErrInvalidKeyOption is returned if a key in an authorized keys file defines an option the registry cannot parse.
+	ErrInvalidKeyOption = errors.New("invalid key option")
+
+	// ErrDuplicateSigningKey is returned if an authorized keys file defines the same key more than once.
+	ErrDuplicateSigningKey = errors.New("duplicate signing key")
+)
+
+// keyTimeLayouts are the layouts accepted for the validity window options of a key, mirroring the ones accepted by
+// openssh for its allowed signers.
+var keyTimeLayouts = []string{"20060102", "200601021504", "20060102150405"}
+
+// The SigningKey struct represents a single key known to the registry.
+type SigningKey struct {
+	// ID is the id of the key, see KeyID.
+	ID string
+
+	// Name is a human-readable name of the key's owner, taken from the comment of the key.
+	// If the key has no comment, its name is its ID.
+	Name string
+
+	// PublicKey is the public key itself.
+	PublicKey ssh.PublicKey
+
+	// ValidAfter is the time after which the key's signatures are accepted, if defined.
+	ValidAfter *time.Time
+
+	// ValidBefore is the time before which the key's signatures are accepted, if defined.
+	ValidBefore *time.Time
+
+	// Revoked marks keys whose signatures are no longer accepted at all.
+	Revoked bool
+}
+
+// ValidAt validates that the key may be used to verify signatures at the passed time.
+func (s SigningKey) ValidAt(at time.Time) error {
+	if s.Revoked {
+		return fmt.Errorf("key %s (%s): %w", s.Name, s.ID, ErrSigningKeyRevoked)
+	}
+
+	if s.ValidAfter != nil && at.Before(*s.ValidAfter) {
+		return fmt.Errorf("key %s (%s) is valid after %s: %w", s.Name, s.ID, s.ValidAfter.Format(time.RFC3339), ErrSigningKeyNotValid)
+	}
+
+	if s.ValidBefore != nil && !at.Before(*s.ValidBefore) {
+		return fmt.Errorf("key %s (%s) expired at %s: %w", s.Name, s.ID, s.ValidBefore.Format(time.RFC3339), ErrSigningKeyNotValid)
+	}
+
+	return nil
+}
+
+// ParseSigningKeys parses the signing keys from an ssh-like authorized_keys file.
+// Next to the key and its comment, which serves as the key's name, each line may define the options
+// valid-after="YYYYMMDD[HHMM[SS]]" and valid-before="YYYYMMDD[HHMM[SS]]", both in UTC, as well as revoked.
+func ParseSigningKeys(reader io.Reader) ([]SigningKey, error) {
+	keys := make([]SigningKey, 0)
+	knownKeyIDs := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		publicKey, comment, options, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse authorized key %s: %w", scanner.Text(), err)
+		}
+
+		key := SigningKey{ID: KeyID(publicKey), Name: comment, PublicKey: publicKey}
+		if key.Name == "" {
+			key.Name = key.ID
+		}
+
+		if err := parseSigningKeyOptions(&key, options); err != nil {
+			return nil, fmt.Errorf("failed to parse options of key %s: %w", key.Name, err)
+		}
+
+		if _, found := knownKeyIDs[key.ID]; found {
+			return nil, fmt.Errorf("key %s (%s): %w", key.Name, key.ID, ErrDuplicateSigningKey)
+		}
+
+		knownKeyIDs[key.ID] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read authorized keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// parseSigningKeyOptions parses the options of a single line in an authorized keys file into the passed key.
+func parseSigningKeyOptions(key *SigningKey, options []string) error {
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "revoked":
+			key.Revoked = true
+		case "valid-after":
+			validAfter, err := parseKeyTime(value)
+			if err != nil {
+				return err
+			}
+
+			key.ValidAfter = &validAfter
+		case "valid-before":
+			validBefore, err := parseKeyTime(value)
+			if err != nil {
+				return err
+			}
+
+			key.ValidBefore = &validBefore
+		default:
+			return fmt.Errorf("unknown option %s: %w", name, ErrInvalidKeyOption)
+		}
+	}
+
+	return nil
+}
+
+// parseKeyTime parses the value of a validity window option in one of the keyTimeLayouts.
+func parseKeyTime(value string) (time.Time, error) {
+	value = strings.TrimSuffix(value, "Z")
+	for _, layout := range keyTimeLayouts {
+		if len(layout) != len(value) {
+			continue
+		}
+
+		parsed, err := time.ParseInLocation(layout, value, time.UTC)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse time %s: %w: %w", value, ErrInvalidKeyOption, err)
+		}
+
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("time %s is not formatted YYYYMMDD[HHMM[SS]]: %w", value, ErrInvalidKeyOption)
+}
+
+// The Registry holds the signing keys known to marauder, loaded from an authorized keys file.
+// The registry is safe for concurrent use and may reload its keys when the file changes.
+type Registry struct {
+	authorizedKeysPath string
+
+	mutex sync.RWMutex
+	keys  map[string]SigningKey
+
+	// loadedFile describes the authorized keys file the keys were loaded from, nil if it did not exist.
+	loadedFile fs.FileInfo
+	loaded     bool
+}
+
+// NewRegistry creates a new registry from the authorized keys file at the passed path and loads its keys.
+// The path is expanded using utils.EvaluateFilePathTemplate. A missing file yields an empty registry.
+func NewRegistry(authorizedKeysPath string) (*Registry, error) {
+	authorizedKeysPath, err := utils.EvaluateFilePathTemplate(authorizedKeysPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse authorized key path: %w", err)
+	}
+
+	registry := &Registry{authorizedKeysPath: filepath.Clean(authorizedKeysPath), keys: make(map[string]SigningKey)}
+	if _, err := registry.Reload(); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// NewRegistryFromKeys creates a new registry holding the passed keys.
+// The registry is not backed by a file and hence never reloads.
+func NewRegistryFromKeys(keys []SigningKey) *Registry {
+	registry := &Registry{keys: make(map[string]SigningKey, len(keys))}
+	for _, key := range keys {
+		registry.keys[key.ID] = key
+	}
+
+	return registry
+}
+
+// Keys yields back all keys currently known to the registry.
+func (r *Registry) Keys() []SigningKey {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make([]SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b SigningKey) int { return strings.Compare(a.ID, b.ID) })
+
+	return keys
+}
+
+// Reload reloads the keys of the registry from its authorized keys file if the file changed since it was last loaded.
+// The returned boolean indicates if the keys were reloaded. If the file cannot be parsed, the previously loaded keys
+// are kept.
+func (r *Registry) Reload() (bool, error) {
+	if r.authorizedKeysPath == "" {
+		return false, nil
+	}
+
+	stat, err := os.Stat(r.authorizedKeysPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to stat authorized key file: %w", err)
+	}
+
+	r.mutex.RLock()
+	unchanged := r.loaded && sameFile(r.loadedFile, stat)
+	r.mutex.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	keys := make([]SigningKey, 0)
+	if stat != nil {
+		authorizedKeys, err := os.ReadFile(r.authorizedKeysPath)
+		if err != nil {
+			return false, fmt.Errorf("failed to read authorized key file: %w", err)
+		}
+
+		if keys, err = ParseSigningKeys(bytes.NewReader(authorizedKeys)); err != nil {
+			return false, fmt.Errorf("failed to parse authorized key file: %w", err)
+		}
+	}
+
+	registry := NewRegistryFromKeys(keys)
+
+	r.mutex.Lock()
+	r.keys = registry.keys
+	r.loadedFile = stat
+	r.loaded = true
+	r.mutex.Unlock()
+
+	return true, nil
+}
+
+// sameFile checks if the two file infos, each nil if the file did not exist, describe the same version of a file.
+func sameFile(previous fs.FileInfo, current fs.FileInfo) bool {
+	if previous == nil || current == nil {
+		return previous == nil && current == nil
+	}
+
+	return previous.ModTime().Equal(current.ModTime()) && previous.Size() == current.Size()
+}
+
+// Watch reloads the keys of the registry whenever its authorized keys file changes, polling it in the passed interval
+// until the context is cancelled.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.Reload()
+		if err != nil {
+			logrus.Errorf("failed to reload signing keys, keeping previous keys: %s", err)
+			continue
+		}
+
+		if reloaded {
+			logrus.Infof("reloaded %d signing keys from %s", len(r.Keys()), r.authorizedKeysPath)
+		}
+	}
+}
+
+// Verify verifies the passed signature against the message.
+// The key that created the signature is yielded back if it is known to the registry and valid at the passed time.
+// Signatures are expected in the SSHSIG format, created in the ArtefactNamespace. Legacy signatures over the sha256 hash
+// of the message remain accepted during the transition and are verified against all keys of the registry.
+func (r *Registry) Verify(message io.Reader, signatureBytes []byte, at time.Time) (SigningKey, error) {
+	if IsSSHSIG(signatureBytes) {
+		return r.verifySSHSIG(message, signatureBytes, at)
+	}
+
+	signature, err := ParseSignature(signatureBytes)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	messageHash, err := hashMessage("sha256", message)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, key := range r.keys {
+		if err := key.PublicKey.Verify(messageHash, signature); err != nil {
+			continue
+		}
+
+		if err := key.ValidAt(at); err != nil {
+			return SigningKey{}, err
+		}
+
+		return key, nil
+	}
+
+	return SigningKey{}, fmt.Errorf("did not find signature in %d known keys: %w", len(r.keys), ErrUnknownSigningKey)
+}
+
+// verifySSHSIG verifies the passed armored SSHSIG signature against the message.
+func (r *Registry) verifySSHSIG(message io.Reader, signatureBytes []byte, at time.Time) (SigningKey, error) {
+	signature, err := ParseSSHSIG(signatureBytes)
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	r.mutex.RLock()
+	key, err := r.lookupValidKey(KeyID(signature.PublicKey), at)
+	r.mutex.RUnlock()
+
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	if err := signature.Verify(ArtefactNamespace, message); err != nil {
+		return SigningKey{}, fmt.Errorf("failed to verify signature of key %s: %w", key.ID, err)
+	}
+
+	return key, nil
+}
+
+// lookupValidKey looks up the key with the passed id, validating that it may be used at the passed time.
+// The caller must hold the read lock of the registry.
+func (r *Registry) lookupValidKey(keyID string, at time.Time) (SigningKey, error) {
+	key, found := r.keys[keyID]
+	if !found {
+		return SigningKey{}, fmt.Errorf("key %s: %w", keyID, ErrUnknownSigningKey)
+	}
+
+	if err := key.ValidAt(at); err != nil {
+		return SigningKey{}, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,166 @@
+package keyauth_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+// generateSigner generates a new ed25519 ssh signer.
+func generateSigner() ssh.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).To(Not(HaveOccurred()))
+
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	Expect(err).To(Not(HaveOccurred()))
+
+	return signer
+}
+
+// authorizedKeyLine formats the public key of the signer as a line of an authorized keys file.
+func authorizedKeyLine(options string, signer ssh.Signer, comment string) string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))) + " " + comment
+	if options != "" {
+		line = options + " " + line
+	}
+
+	return line + "\n"
+}
+
+var _ = Describe("the signing key registry", Label("unittest"), func() {
+	var (
+		ciSigner    ssh.Signer
+		otherSigner ssh.Signer
+		data        []byte
+	)
+
+	BeforeEach(func() {
+		ciSigner = generateSigner()
+		otherSigner = generateSigner()
+		data = []byte("artefact hash")
+	})
+
+	It("should parse keys with their options", func() {
+		keys, err := keyauth.ParseSigningKeys(strings.NewReader(
+			"# comments are ignored\n" +
+				authorizedKeyLine(`valid-after="20240101",valid-before="202501021530Z"`, ciSigner, "ci@spellcore") +
+				authorizedKeyLine("revoked", otherSigner, ""),
+		))
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(keys).To(HaveLen(2))
+
+		Expect(keys[0].ID).To(Equal(keyauth.KeyID(ciSigner.PublicKey())))
+		Expect(keys[0].Name).To(Equal("ci@spellcore"))
+		Expect(keys[0].ValidAfter).To(HaveValue(Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))))
+		Expect(keys[0].ValidBefore).To(HaveValue(Equal(time.Date(2025, 1, 2, 15, 30, 0, 0, time.UTC))))
+		Expect(keys[1].Name).To(Equal(keys[1].ID))
+		Expect(keys[1].Revoked).To(BeTrue())
+	})
+
+	It("should reject unknown options and duplicate keys", func() {
+		_, err := keyauth.ParseSigningKeys(strings.NewReader(authorizedKeyLine(`expiry="2024"`, ciSigner, "ci")))
+		Expect(err).To(MatchError(keyauth.ErrInvalidKeyOption))
+
+		_, err = keyauth.ParseSigningKeys(strings.NewReader(authorizedKeyLine(`valid-after="2024"`, ciSigner, "ci")))
+		Expect(err).To(MatchError(keyauth.ErrInvalidKeyOption))
+
+		_, err = keyauth.ParseSigningKeys(strings.NewReader(
+			authorizedKeyLine("", ciSigner, "ci") + authorizedKeyLine("", ciSigner, "ci-again"),
+		))
+		Expect(err).To(MatchError(keyauth.ErrDuplicateSigningKey))
+	})
+
+	It("should verify SSHSIG signatures of known keys", func() {
+		keys, err := keyauth.ParseSigningKeys(strings.NewReader(authorizedKeyLine("", ciSigner, "ci@spellcore")))
+		Expect(err).To(Not(HaveOccurred()))
+
+		signature, err := keyauth.SignSSHSIG(ciSigner, keyauth.ArtefactNamespace, bytes.NewReader(data))
+		Expect(err).To(Not(HaveOccurred()))
+
+		key, err := keyauth.NewRegistryFromKeys(keys).Verify(bytes.NewReader(data), signature, time.Now())
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(key.Name).To(Equal("ci@spellcore"))
+
+		_, err = keyauth.NewRegistryFromKeys(keys).Verify(strings.NewReader("other data"), signature, time.Now())
+		Expect(err).To(MatchError(keyauth.ErrInvalidSignature))
+
+		otherSignature, err := keyauth.SignSSHSIG(otherSigner, keyauth.ArtefactNamespace, bytes.NewReader(data))
+		Expect(err).To(Not(HaveOccurred()))
+
+		_, err = keyauth.NewRegistryFromKeys(keys).Verify(bytes.NewReader(data), otherSignature, time.Now())
+		Expect(err).To(MatchError(keyauth.ErrUnknownSigningKey))
+
+		foreignNamespaceSignature, err := keyauth.SignSSHSIG(ciSigner, "git", bytes.NewReader(data))
+		Expect(err).To(Not(HaveOccurred()))
+
+		_, err = keyauth.NewRegistryFromKeys(keys).Verify(bytes.NewReader(data), foreignNamespaceSignature, time.Now())
+		Expect(err).To(MatchError(keyauth.ErrSignatureNamespaceMismatch))
+	})
+
+	It("should verify plain legacy ssh signatures against all keys", func() {
+		keys, err := keyauth.ParseSigningKeys(strings.NewReader(
+			authorizedKeyLine("", otherSigner, "other") + authorizedKeyLine("", ciSigner, "ci"),
+		))
+		Expect(err).To(Not(HaveOccurred()))
+
+		dataHash := sha256.Sum256(data)
+		signature, err := ciSigner.Sign(rand.Reader, dataHash[:])
+		Expect(err).To(Not(HaveOccurred()))
+
+		key, err := keyauth.NewRegistryFromKeys(keys).Verify(bytes.NewReader(data), ssh.Marshal(signature), time.Now())
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(key.Name).To(Equal("ci"))
+	})
+
+	It("should reject signatures of revoked keys and keys outside their validity window", func() {
+		signature, err := keyauth.SignSSHSIG(ciSigner, keyauth.ArtefactNamespace, bytes.NewReader(data))
+		Expect(err).To(Not(HaveOccurred()))
+
+		verify := func(options string, at time.Time) error {
+			keys, err := keyauth.ParseSigningKeys(strings.NewReader(authorizedKeyLine(options, ciSigner, "ci")))
+			Expect(err).To(Not(HaveOccurred()))
+
+			_, err = keyauth.NewRegistryFromKeys(keys).Verify(bytes.NewReader(data), signature, at)
+
+			return err
+		}
+
+		inWindow := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		Expect(verify(`valid-after="20240101",valid-before="20250101"`, inWindow)).To(Succeed())
+		Expect(verify("revoked", inWindow)).To(MatchError(keyauth.ErrSigningKeyRevoked))
+		Expect(verify(`valid-after="20240701"`, inWindow)).To(MatchError(keyauth.ErrSigningKeyNotValid))
+		Expect(verify(`valid-before="20240601"`, inWindow)).To(MatchError(keyauth.ErrSigningKeyNotValid))
+	})
+
+	It("should reload keys when the authorized keys file changes", func() {
+		authorizedKeysPath := filepath.Join(GinkgoT().TempDir(), "authorized_keys")
+
+		registry, err := keyauth.NewRegistry(authorizedKeysPath)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(registry.Keys()).To(BeEmpty())
+
+		Expect(os.WriteFile(authorizedKeysPath, []byte(authorizedKeyLine("", ciSigner, "ci")), 0o600)).To(Succeed())
+		Expect(registry.Reload()).To(BeTrue())
+		Expect(registry.Keys()).To(HaveLen(1))
+		Expect(registry.Reload()).To(BeFalse())
+
+		Expect(os.WriteFile(authorizedKeysPath, []byte("not a key\n"), 0o600)).To(Succeed())
+		_, err = registry.Reload()
+		Expect(err).To(HaveOccurred())
+		Expect(registry.Keys()).To(HaveLen(1))
+
+		Expect(os.Remove(authorizedKeysPath)).To(Succeed())
+		Expect(registry.Reload()).To(BeTrue())
+		Expect(registry.Keys()).To(BeEmpty())
+	})
+})
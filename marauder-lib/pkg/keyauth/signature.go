@@ -0,0 +1,27 @@
+package keyauth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrInvalidSignature is returned if the bytes of a signature cannot be parsed.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// KeyID computes the key id of the passed public key, which is its SHA256 fingerprint.
+func KeyID(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
+
+// ParseSignature parses a legacy signature, which is a plain ssh signature over the sha256 hash of the message.
+// Legacy signatures do not identify the key that created them.
+func ParseSignature(signatureBytes []byte) (*ssh.Signature, error) {
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(signatureBytes, &signature); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signature: %w: %w", ErrInvalidSignature, err)
+	}
+
+	return &signature, nil
+}
@@ -0,0 +1,220 @@
+package keyauth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ArtefactNamespace is the SSHSIG namespace artefacts are signed in.
+// Signatures of artefacts can hence be verified via `ssh-keygen -Y verify -n marauder-artefact`.
+const ArtefactNamespace = "marauder-artefact"
+
+const (
+	// sshsigMagic is the magic preamble of SSHSIG signature blobs and of the data signed by them.
+	sshsigMagic = "SSHSIG"
+
+	// sshsigVersion is the version of the SSHSIG format implemented.
+	sshsigVersion = 1
+
+	// sshsigPemType is the type of the PEM block armoring SSHSIG signatures.
+	sshsigPemType = "SSH SIGNATURE"
+
+	// sshsigHashAlgorithm is the hash algorithm marauder uses for the messages it signs, the default of ssh-keygen.
+	sshsigHashAlgorithm = "sha512"
+)
+
+var (
+	// ErrSignatureNamespaceMismatch is returned if an SSHSIG signature was created for a different namespace.
+	ErrSignatureNamespaceMismatch = errors.New("signature namespace mismatch")
+
+	// ErrUnsupportedHashAlgorithm is returned if an SSHSIG signature hashed its message with an unsupported algorithm.
+	ErrUnsupportedHashAlgorithm = errors.New("unsupported hash algorithm")
+)
+
+// sshsigBlob is the wire format of an SSHSIG signature without its magic preamble.
+type sshsigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshsigSignedData is the wire format of the data signed by an SSHSIG signature without its magic preamble.
+type sshsigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	MessageHash   []byte
+}
+
+// The SSHSignature struct represents a parsed signature in the SSHSIG format as specified by openssh's PROTOCOL.sshsig.
+type SSHSignature struct {
+	// PublicKey is the public key that created the signature.
+	PublicKey ssh.PublicKey
+
+	// Namespace is the namespace the signature was created in, preventing signatures to be reused across domains.
+	Namespace string
+
+	// HashAlgorithm is the algorithm the signed message was hashed with, either sha256 or sha512.
+	HashAlgorithm string
+
+	// Signature is the ssh signature over the signed data.
+	Signature *ssh.Signature
+}
+
+// IsSSHSIG checks if the passed signature bytes are an armored SSHSIG signature.
+func IsSSHSIG(signatureBytes []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(signatureBytes), []byte("-----BEGIN "+sshsigPemType+"-----"))
+}
+
+// SignSSHSIG signs the passed message with the signer in the given namespace, yielding back the armored SSHSIG signature
+// as produced by `ssh-keygen -Y sign`.
+func SignSSHSIG(signer ssh.Signer, namespace string, message io.Reader) ([]byte, error) {
+	messageHash, err := hashMessage(sshsigHashAlgorithm, message)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData := sshsigSignedDataBytes(namespace, sshsigHashAlgorithm, messageHash)
+
+	var signature *ssh.Signature
+	// ssh-keygen refuses rsa signatures using sha1, the default of rsa signers.
+	if algorithmSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = signer.Sign(rand.Reader, signedData)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	blob := append([]byte(sshsigMagic), ssh.Marshal(sshsigBlob{
+		Version:       sshsigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: sshsigHashAlgorithm,
+		Signature:     ssh.Marshal(signature),
+	})...)
+
+	return armorSSHSIG(blob), nil
+}
+
+// ParseSSHSIG parses an armored SSHSIG signature.
+func ParseSSHSIG(signatureBytes []byte) (*SSHSignature, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(signatureBytes))
+	if block == nil || block.Type != sshsigPemType {
+		return nil, fmt.Errorf("failed to find armored ssh signature: %w", ErrInvalidSignature)
+	}
+
+	blob, found := bytes.CutPrefix(block.Bytes, []byte(sshsigMagic))
+	if !found {
+		return nil, fmt.Errorf("missing magic preamble: %w", ErrInvalidSignature)
+	}
+
+	var parsedBlob sshsigBlob
+	if err := ssh.Unmarshal(blob, &parsedBlob); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ssh signature: %w: %w", ErrInvalidSignature, err)
+	}
+
+	if parsedBlob.Version != sshsigVersion {
+		return nil, fmt.Errorf("unsupported version %d: %w", parsedBlob.Version, ErrInvalidSignature)
+	}
+
+	publicKey, err := ssh.ParsePublicKey(parsedBlob.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of signature: %w: %w", ErrInvalidSignature, err)
+	}
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(parsedBlob.Signature, &signature); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signature: %w: %w", ErrInvalidSignature, err)
+	}
+
+	return &SSHSignature{
+		PublicKey:     publicKey,
+		Namespace:     parsedBlob.Namespace,
+		HashAlgorithm: parsedBlob.HashAlgorithm,
+		Signature:     &signature,
+	}, nil
+}
+
+// Verify verifies that the signature was created over the passed message in the given namespace by its public key.
+// The caller is responsible to check that the public key of the signature is trusted.
+func (s *SSHSignature) Verify(namespace string, message io.Reader) error {
+	if s.Namespace != namespace {
+		return fmt.Errorf("signature created for %q, expected %q: %w", s.Namespace, namespace, ErrSignatureNamespaceMismatch)
+	}
+
+	messageHash, err := hashMessage(s.HashAlgorithm, message)
+	if err != nil {
+		return err
+	}
+
+	if err := s.PublicKey.Verify(sshsigSignedDataBytes(s.Namespace, s.HashAlgorithm, messageHash), s.Signature); err != nil {
+		return fmt.Errorf("failed to verify signature: %w: %w", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+// sshsigSignedDataBytes computes the data an SSHSIG signature signs for the passed message hash.
+func sshsigSignedDataBytes(namespace string, hashAlgorithm string, messageHash []byte) []byte {
+	return append([]byte(sshsigMagic), ssh.Marshal(sshsigSignedData{
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		MessageHash:   messageHash,
+	})...)
+}
+
+// hashMessage hashes the passed message with the named SSHSIG hash algorithm.
+func hashMessage(hashAlgorithm string, message io.Reader) ([]byte, error) {
+	var hasher hash.Hash
+
+	switch hashAlgorithm {
+	case "sha256":
+		hasher = sha256.New()
+	case "sha512":
+		hasher = sha512.New()
+	default:
+		return nil, fmt.Errorf("hash algorithm %s: %w", hashAlgorithm, ErrUnsupportedHashAlgorithm)
+	}
+
+	if _, err := io.Copy(hasher, message); err != nil {
+		return nil, fmt.Errorf("failed to hash message: %w", err)
+	}
+
+	return hasher.Sum(nil), nil
+}
+
+// armorSSHSIG armors the passed SSHSIG blob like ssh-keygen does, wrapping its base64 encoding at 70 characters.
+func armorSSHSIG(blob []byte) []byte {
+	const lineLength = 70
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	var armored bytes.Buffer
+	armored.WriteString("-----BEGIN " + sshsigPemType + "-----\n")
+
+	for len(encoded) > lineLength {
+		armored.WriteString(encoded[:lineLength] + "\n")
+		encoded = encoded[lineLength:]
+	}
+
+	armored.WriteString(encoded + "\n")
+	armored.WriteString("-----END " + sshsigPemType + "-----\n")
+
+	return armored.Bytes()
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// OutcomeSuccess is the outcome label value of a successful operation.
+	OutcomeSuccess = "success"
+
+	// OutcomeFailure is the outcome label value of a failed operation.
+	OutcomeFailure = "failure"
+)
+
+// Outcome computes the outcome label value for the passed error.
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
+
+// The Registry holds all collectors exposed by a marauder component, including the go runtime and process collectors.
+type Registry struct {
+	registry *prometheus.Registry
+}
+
+// NewRegistry creates a new registry with the go runtime and process collectors registered on it.
+func NewRegistry() (*Registry, error) {
+	registry := &Registry{registry: prometheus.NewRegistry()}
+	if err := registry.Register(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Register registers the passed collectors on the registry.
+func (r *Registry) Register(collectors ...prometheus.Collector) error {
+	for _, collector := range collectors {
+		if err := r.registry.Register(collector); err != nil {
+			return fmt.Errorf("failed to register collector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Handler creates the http handler exposing the metrics of the registry.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
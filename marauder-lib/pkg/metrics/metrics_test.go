@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("metrics", Label("unittest"), func() {
+	It("computes the outcome of an operation", func() {
+		Expect(metrics.Outcome(nil)).To(Equal(metrics.OutcomeSuccess))
+		Expect(metrics.Outcome(errors.New("failed"))).To(Equal(metrics.OutcomeFailure))
+	})
+
+	It("exposes the registered collectors and the go runtime collector", func() {
+		registry, err := metrics.NewRegistry()
+		Expect(err).To(Not(HaveOccurred()))
+
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Namespace: "marauder_test", Name: "requests_total"})
+		Expect(registry.Register(counter)).To(Succeed())
+		counter.Inc()
+
+		recorder := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		body, err := io.ReadAll(recorder.Body)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(string(body)).To(ContainSubstring("marauder_test_requests_total 1"))
+		Expect(string(body)).To(ContainSubstring("go_goroutines"))
+	})
+
+	It("refuses to register a collector twice", func() {
+		registry, err := metrics.NewRegistry()
+		Expect(err).To(Not(HaveOccurred()))
+
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Namespace: "marauder_test", Name: "requests_total"})
+		Expect(registry.Register(counter)).To(Succeed())
+		Expect(registry.Register(counter)).To(Not(Succeed()))
+	})
+})
@@ -3,7 +3,11 @@ package filemodel
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"reflect"
 	"time"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/jsonschema"
 )
 
 var (
@@ -15,11 +19,80 @@ var (
 
 	// ErrExactFileMatchesFailed is returned if a matcher/builder for the file manifest matches a different amount than the exact match count.
 	ErrExactFileMatchesFailed = errors.New("different than exact amount matched")
+
+	// ErrUnknownFileDeploymentMode is returned if a file reference defines a deployment mode unknown to marauder.
+	ErrUnknownFileDeploymentMode = errors.New("unknown file deployment mode")
+
+	// ErrMergeUnsupportedByDeploymentMode is returned if a file reference defines a merge provider for a deployment mode
+	// that never overwrites existing files.
+	ErrMergeUnsupportedByDeploymentMode = errors.New("merge unsupported by deployment mode")
+
+	// ErrInconsistentFileRestriction is returned if the restrictions of a file reference contradict each other.
+	ErrInconsistentFileRestriction = errors.New("inconsistent file restriction")
 )
 
+// ManifestSchemaID is the identifier of the json schema of the manifest published with the marauder client, which is the
+// url the schema is served from on the default branch, so editors can resolve it.
+const ManifestSchemaID = "https://raw.githubusercontent.com/KnockturnMC/marauder/main/marauder-client/schema/manifest.schema.json"
+
+// The FileDeploymentMode defines how the files of a file reference are treated by updates of the artefact on a server.
+type FileDeploymentMode string
+
+const (
+	// FileDeploymentReplace deletes and re-extracts the files on every update of the artefact.
+	// This is the default deployment mode of files.
+	FileDeploymentReplace FileDeploymentMode = "replace"
+
+	// FileDeploymentInstallOnce only extracts the files if they do not exist on disk.
+	// Once installed, the files are owned by the server and are never validated, overwritten or deleted.
+	FileDeploymentInstallOnce FileDeploymentMode = "install-once"
+
+	// FileDeploymentPreserveIfModified replaces the files like FileDeploymentReplace, unless the server modified them.
+	// Modified files are neither reported as unexpected, overwritten nor deleted.
+	FileDeploymentPreserveIfModified FileDeploymentMode = "preserve-if-modified"
+
+	// FileDeploymentKeepOnUninstall replaces the files like FileDeploymentReplace, but keeps them on disk if the artefact is
+	// uninstalled or its new version no longer contains them.
+	FileDeploymentKeepOnUninstall FileDeploymentMode = "keep-on-uninstall"
+)
+
+// EnumValues yields back all deployment modes known to marauder, restricting the mode in the manifest schema.
+func (f FileDeploymentMode) EnumValues() []string {
+	return []string{
+		string(FileDeploymentReplace),
+		string(FileDeploymentInstallOnce),
+		string(FileDeploymentPreserveIfModified),
+		string(FileDeploymentKeepOnUninstall),
+	}
+}
+
+// ManifestSchema generates the json schema of the manifest.
+func ManifestSchema() *jsonschema.Schema {
+	schema := jsonschema.Generate(reflect.TypeFor[Manifest]())
+	schema.Schema = jsonschema.Draft
+	schema.ID = ManifestSchemaID
+	schema.Title = "marauder artefact manifest"
+
+	// Editors validating the manifest via the schema reference it from the manifest itself.
+	schema.Properties["$schema"] = &jsonschema.Schema{Type: "string"}
+
+	return schema
+}
+
 // A FileReferenceCollection holds all defined file references of a manifest.
 type FileReferenceCollection []FileReference
 
+// ValidateDeployments validates the deployment configuration of all file references of the collection.
+func (f FileReferenceCollection) ValidateDeployments() error {
+	for _, fileReference := range f {
+		if err := fileReference.Deployment.Validate(); err != nil {
+			return fmt.Errorf("invalid deployment of %s: %w", fileReference.Target, err)
+		}
+	}
+
+	return nil
+}
+
 // MatchedFilesToReferenceMap constructs a map of filenames in the tarball to the file reference they were matched under
 // This allows fast access to the respective file reference without having to iterate over all file references.
 func (f FileReferenceCollection) MatchedFilesToReferenceMap() map[string]*FileReference {
@@ -115,9 +188,33 @@ type FileReference struct {
 	// The deployment field holds configuration values used during the deployment of the files matched by this file reference.
 	Deployment *FileDeployment `json:"deployment,omitempty"`
 
+	// PreserveSymlinks includes symbolic links matched by this file reference as links instead of the files they point to.
+	// Only links to relative targets that stay inside the server folder can be deployed.
+	PreserveSymlinks bool `json:"preserveSymlinks,omitempty"`
+
 	// MatchedFiles contains a collection of paths in the tarball of the manifest that were included because they were matched by
 	// this file reference.
 	MatchedFiles map[string]string `json:"matchedFiles,omitempty"`
+
+	// MatchedFileMetadata holds the metadata of the matched files, keyed by their path in the tarball.
+	// Artefacts built before file metadata was recorded do not define it, their files are deployed with default permissions.
+	MatchedFileMetadata map[string]FileMetadata `json:"matchedFileMetadata,omitempty"`
+}
+
+// The FileMetadata struct holds the metadata of a single file in an artefact as recorded when building the artefact.
+type FileMetadata struct {
+	// Mode holds the POSIX permission bits of the file, e.g. 0o755 for an executable script.
+	Mode uint32 `json:"mode"`
+
+	// SymlinkTarget holds the target of the file if it was included as a symbolic link.
+	SymlinkTarget *string `json:"symlinkTarget,omitempty"`
+}
+
+// DeployedMode yields back the permissions the file is deployed with.
+// Only the permission bits are honoured and group and world write access is stripped, while the owner can always read
+// and write the file.
+func (f FileMetadata) DeployedMode() fs.FileMode {
+	return fs.FileMode(f.Mode)&0o755 | 0o600
 }
 
 // The FileDeployment struct holds configuration values for deploying matched files from a marauder artefact
@@ -127,7 +224,51 @@ type FileDeployment struct {
 	// when deploying the specific files of an artefact.
 	// Marauder will first validate if the current deployment of the artefact is intact to not potentially induce invalidate state.
 	// For this, the default equality provider "hash" is used which compares the file on disk to the expected file via their sha256sum hash.
+	// Structured files may be compared semantically by the "json", "yaml", "toml" and "properties" providers, which
+	// can ignore volatile keys via a parameter, e.g. `yaml?ignore=metrics.uuid,config-version`.
 	EqualityProvider *string `json:"equalityProvider,omitempty"`
+
+	// Template marks the files as go templates that are rendered when they are deployed onto a server.
+	// Templates are executed with the server model they are deployed onto, e.g. `{{.Name}}` or `{{.Port}}`, as well as
+	// the variables defined for the server on the controller under `{{.Variables.key}}`.
+	// Equality checks of templates are performed against their rendered output.
+	Template bool `json:"template,omitempty"`
+
+	// The Mode defines how the files are treated by updates of the artefact on a server.
+	// If not defined, the default mode "replace" deletes and re-extracts the files on every update.
+	Mode *FileDeploymentMode `json:"mode,omitempty"`
+
+	// The MergeProvider defines the way marauder three-way merges the files with their modified version on disk when
+	// the artefact is updated, e.g. "yaml" or "json".
+	// Local changes to keys the new version of the file did not touch are kept, keys changed both locally and by the new
+	// version are reported as conflicts and take the value of the new version.
+	// If not defined, files are overwritten. Merging is only supported by the "replace" and "keep-on-uninstall" modes.
+	MergeProvider *string `json:"mergeProvider,omitempty"`
+}
+
+// DeploymentMode yields back the deployment mode of the files, defaulting to FileDeploymentReplace.
+func (f *FileDeployment) DeploymentMode() FileDeploymentMode {
+	if f == nil || f.Mode == nil {
+		return FileDeploymentReplace
+	}
+
+	return *f.Mode
+}
+
+// Validate validates if the deployment mode of the files is known and supports the configured merge provider.
+func (f *FileDeployment) Validate() error {
+	switch mode := f.DeploymentMode(); mode {
+	case FileDeploymentReplace, FileDeploymentKeepOnUninstall:
+		return nil
+	case FileDeploymentInstallOnce, FileDeploymentPreserveIfModified:
+		if f.MergeProvider != nil {
+			return fmt.Errorf("mode %s: %w", mode, ErrMergeUnsupportedByDeploymentMode)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("mode %s: %w", mode, ErrUnknownFileDeploymentMode)
+	}
 }
 
 // The FileRestriction type allows to restrict matches by marauder during the artefact building process.
@@ -145,6 +286,29 @@ type FileRestriction struct {
 	Exact *int `json:"exact,omitempty"`
 }
 
+// Validate validates that the restrictions are consistent with each other.
+// An exact amount cannot be combined with a min or max amount and no amount may be negative or exclude all others.
+func (f *FileRestriction) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	for _, amount := range []*int{f.Exact, f.Min, f.Max} {
+		if amount != nil && *amount < 0 {
+			return fmt.Errorf("amount %d is negative: %w", *amount, ErrInconsistentFileRestriction)
+		}
+	}
+
+	switch {
+	case f.Exact != nil && (f.Min != nil || f.Max != nil):
+		return fmt.Errorf("exact amount combined with min or max: %w", ErrInconsistentFileRestriction)
+	case f.Min != nil && f.Max != nil && *f.Min > *f.Max:
+		return fmt.Errorf("min amount %d exceeds max amount %d: %w", *f.Min, *f.Max, ErrInconsistentFileRestriction)
+	}
+
+	return nil
+}
+
 // ValidateMatchAmount validates if the passed amount matches the file restrictions.
 func (f *FileRestriction) ValidateMatchAmount(amountMatched int) error {
 	if f == nil {
@@ -1,9 +1,11 @@
 package networkmodel
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 )
 
 // ArtefactModel represents an artefact entry in the database.
@@ -26,6 +28,54 @@ type ArtefactModel struct {
 	// Hence, if either of the artefacts during an upgrade (old or new) require a restart
 	// the upgrade in total also requires one.
 	RequiresRestart bool `db:"requires_restart" json:"requiresRestart"`
+
+	// SignerKeyID is the id of the key that signed the artefact, see keyauth.KeyID.
+	// Artefacts uploaded before signer keys were recorded do not hold one.
+	SignerKeyID *string `db:"signer_key_id" json:"signerKeyId,omitempty"`
+
+	// SignerName is the name of the key that signed the artefact at the time it was uploaded.
+	SignerName *string `db:"signer_name" json:"signerName,omitempty"`
+
+	// BuildRepository is the git repository the artefact was built from, as recorded in its manifest's build information.
+	// Artefacts built without build information do not hold any of the build fields.
+	BuildRepository *string `db:"build_repository" json:"buildRepository,omitempty"`
+
+	// BuildBranch is the branch the artefact was built from.
+	BuildBranch *string `db:"build_branch" json:"buildBranch,omitempty"`
+
+	// BuildCommitHash is the hash of the commit the artefact was built from.
+	BuildCommitHash *string `db:"build_commit_hash" json:"buildCommitHash,omitempty"`
+
+	// BuildCommitUser is the name of the author of the commit the artefact was built from.
+	BuildCommitUser *string `db:"build_commit_user" json:"buildCommitUser,omitempty"`
+
+	// BuildCommitEmail is the email of the author of the commit the artefact was built from.
+	BuildCommitEmail *string `db:"build_commit_email" json:"buildCommitEmail,omitempty"`
+}
+
+// WithBuildInformation yields back the artefact model with its build fields set from the passed build information.
+// Empty values of the build information are not recorded. The commit hash is recorded lowercased, as artefacts are searched
+// by their commit hash case-insensitively.
+func (a ArtefactModel) WithBuildInformation(buildInformation *filemodel.BuildInformation) ArtefactModel {
+	if buildInformation == nil {
+		return a
+	}
+
+	nilIfEmpty := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+
+		return &value
+	}
+
+	a.BuildRepository = nilIfEmpty(buildInformation.Repository)
+	a.BuildBranch = nilIfEmpty(buildInformation.Branch)
+	a.BuildCommitHash = nilIfEmpty(strings.ToLower(buildInformation.CommitHash))
+	a.BuildCommitUser = nilIfEmpty(buildInformation.CommitUser)
+	a.BuildCommitEmail = nilIfEmpty(buildInformation.CommitEmail)
+
+	return a
 }
 
 // The ArtefactModelWithBinary struct represents a full artefact, including its tarball.
@@ -37,4 +87,24 @@ type ArtefactModelWithBinary struct {
 
 	// The Hash of the tarball this artefact represents in the format of a sha256 hash.,
 	Hash []byte `db:"hash" json:"hash"`
+
+	// The Signature of the tarball as uploaded by its signer.
+	// Artefacts uploaded before signatures were recorded do not hold one.
+	Signature []byte `db:"signature" json:"signature,omitempty"`
+
+	// The Manifest of the artefact, stored alongside its tarball to be served and queried without reading the tarball.
+	Manifest *filemodel.Manifest `db:"-" json:"manifest,omitempty"`
+}
+
+// The ArtefactIntegrity struct holds the information needed to verify a downloaded artefact tarball.
+type ArtefactIntegrity struct {
+	// The Hash of the tarball in the format of a sha256 hash.
+	Hash []byte `db:"hash" json:"hash"`
+
+	// The Signature of the tarball as uploaded by its signer.
+	// Artefacts uploaded before signatures were recorded do not hold one.
+	Signature []byte `db:"signature" json:"signature,omitempty"`
+
+	// The UploadDate of the artefact, at which the key that signed it had to be valid.
+	UploadDate time.Time `db:"upload_date" json:"uploadDate"`
 }
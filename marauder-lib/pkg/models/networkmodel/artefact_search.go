@@ -0,0 +1,41 @@
+package networkmodel
+
+import (
+	"net/url"
+)
+
+// The ArtefactSearch defines the filters artefacts are searched by.
+// Only artefacts matching all defined filters are found.
+type ArtefactSearch struct {
+	// CommitHash finds artefacts built from the commit, which may be abbreviated.
+	CommitHash string `json:"commit"`
+
+	// Branch finds artefacts built from the branch.
+	Branch string `json:"branch"`
+
+	// File finds artefacts that own the file, given by its path in the server folder, e.g. plugins/spellcore.jar.
+	File string `json:"file"`
+}
+
+// IsEmpty computes if the search does not define any filter.
+func (a ArtefactSearch) IsEmpty() bool {
+	return a.CommitHash == "" && a.Branch == "" && a.File == ""
+}
+
+// QueryParameters encodes the search into url query parameters.
+func (a ArtefactSearch) QueryParameters() url.Values {
+	parameters := url.Values{}
+	if a.CommitHash != "" {
+		parameters.Set("commit", a.CommitHash)
+	}
+
+	if a.Branch != "" {
+		parameters.Set("branch", a.Branch)
+	}
+
+	if a.File != "" {
+		parameters.Set("file", a.File)
+	}
+
+	return parameters
+}
@@ -0,0 +1,20 @@
+package networkmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The ServerBackup represents a single backup archive of a servers data folder stored by its operator.
+type ServerBackup struct {
+	// Name is the unique name of the backup for its server, used to reference it on restore.
+	Name string `json:"name"`
+
+	ServerUUID  uuid.UUID `json:"serverUUID"`
+	Environment string    `json:"environment"`
+	ServerName  string    `json:"serverName"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	SizeBytes int64     `json:"sizeBytes"`
+}
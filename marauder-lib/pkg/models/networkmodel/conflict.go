@@ -0,0 +1,66 @@
+package networkmodel
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+)
+
+// ErrFileConflict is returned if multiple artefacts deployed onto the same server ship the same file.
+var ErrFileConflict = errors.New("file conflict")
+
+// The FileConflict struct represents a single file in the server folder that is shipped by multiple artefacts.
+// Installing either artefact overwrites the file of the other, while uninstalling either deletes it.
+type FileConflict struct {
+	// Path is the path of the file in the server folder.
+	Path string `json:"path"`
+
+	// ArtefactIdentifiers holds the identifiers of all artefacts shipping the file, sorted.
+	ArtefactIdentifiers []string `json:"artefactIdentifiers"`
+}
+
+// Involves returns if the artefact with the passed identifier ships the conflicting file.
+func (f FileConflict) Involves(artefactIdentifier string) bool {
+	return slices.Contains(f.ArtefactIdentifiers, artefactIdentifier)
+}
+
+// String formats the conflict as the path of the file followed by the artefacts shipping it.
+func (f FileConflict) String() string {
+	return f.Path + " (" + strings.Join(f.ArtefactIdentifiers, ", ") + ")"
+}
+
+// FindFileConflicts finds all files in the server folder shipped by more than one of the passed manifests, sorted by
+// their path. Manifests sharing an identifier are versions of the same artefact and never conflict with each other.
+func FindFileConflicts(manifests []filemodel.Manifest) []FileConflict {
+	owners := make(map[string]map[string]bool)
+	for _, manifest := range manifests {
+		for pathInTarball := range manifest.Files.MatchedFilesToReferenceMap() {
+			pathInServerFolder, _ := strings.CutPrefix(pathInTarball, pkg.FileParentDirectoryInArtefact)
+			if owners[pathInServerFolder] == nil {
+				owners[pathInServerFolder] = make(map[string]bool)
+			}
+
+			owners[pathInServerFolder][manifest.Identifier] = true
+		}
+	}
+
+	conflicts := make([]FileConflict, 0)
+	for pathInServerFolder, identifiers := range owners {
+		if len(identifiers) < 2 {
+			continue
+		}
+
+		conflicts = append(conflicts, FileConflict{
+			Path:                pathInServerFolder,
+			ArtefactIdentifiers: slices.Sorted(maps.Keys(identifiers)),
+		})
+	}
+
+	slices.SortFunc(conflicts, func(a, b FileConflict) int { return strings.Compare(a.Path, b.Path) })
+
+	return conflicts
+}
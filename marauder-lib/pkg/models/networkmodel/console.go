@@ -0,0 +1,17 @@
+package networkmodel
+
+// ConsoleControlTypeResize is the type of console control messages resizing the tty of the attached container.
+const ConsoleControlTypeResize = "resize"
+
+// The ConsoleControlMessage is sent as a text message over an attached console websocket to control the console.
+// Binary messages on the other hand carry the raw console input and output.
+type ConsoleControlMessage struct {
+	// Type defines the type of the control message, e.g. ConsoleControlTypeResize.
+	Type string `json:"type"`
+
+	// Width is the new width of the tty in columns for resize messages.
+	Width uint `json:"width,omitempty"`
+
+	// Height is the new height of the tty in rows for resize messages.
+	Height uint `json:"height,omitempty"`
+}
@@ -28,6 +28,10 @@ type ArtefactVersionMissmatch struct {
 
 	// The Missmatch defines the missmatch container that holds the potential missmatch.
 	Missmatch ArtefactMissmatch `json:"missmatch"`
+
+	// AllowFileConflicts defines if the artefact to install was explicitly allowed to ship files shipped by other
+	// artefacts on the server.
+	AllowFileConflicts bool `json:"allowFileConflicts,omitempty"`
 }
 
 type ArtefactMissmatch struct {
@@ -100,6 +104,10 @@ type UpdateServerStateRequest struct {
 	// ArtefactUUID provides the uuid reference to the artefact this state belongs to.
 	// If the artefact UUID is nil, the update server state request implies a deletion of the state.
 	ArtefactUUID *uuid.UUID `json:"artefactUuid,omitempty"`
+
+	// AllowFileConflicts allows the artefact of a TARGET state to ship files shipped by other artefacts on the server.
+	// Without it, such a TARGET state is rejected.
+	AllowFileConflicts bool `json:"allowFileConflicts,omitempty"`
 }
 
 // CheckFilled returns an err conveying if the request is filled with non-default values.
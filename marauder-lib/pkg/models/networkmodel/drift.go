@@ -0,0 +1,62 @@
+package networkmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The FileDriftKind defines how a file on disk drifted from the artefact it was deployed from.
+type FileDriftKind string
+
+const (
+	// FileDriftModified marks a file that exists on disk but no longer equals the file in its artefact.
+	FileDriftModified FileDriftKind = "modified"
+
+	// FileDriftMissing marks a file of an artefact that no longer exists on disk.
+	FileDriftMissing FileDriftKind = "missing"
+)
+
+// The FileDrift represents a single file of an artefact in the IS state of a server that drifted from said artefact.
+type FileDrift struct {
+	// The Server the drifted file belongs to.
+	Server uuid.UUID `db:"server" json:"server"`
+
+	// ArtefactIdentifier is the identifier of the artefact the file was deployed from.
+	ArtefactIdentifier string `db:"artefact_identifier" json:"artefactIdentifier"`
+
+	// ArtefactUUID is the uuid of the artefact the file was deployed from.
+	ArtefactUUID uuid.UUID `db:"artefact_uuid" json:"artefactUuid"`
+
+	// ArtefactVersion is the version of the artefact the file was deployed from.
+	ArtefactVersion string `db:"artefact_version" json:"artefactVersion"`
+
+	// The Path of the file relative to the server folder.
+	Path string `db:"path" json:"path"`
+
+	// The Kind of drift found for the file.
+	Kind FileDriftKind `db:"kind" json:"kind"`
+
+	// EqualityProvider is the identifier of the file equality used to compare the file.
+	EqualityProvider string `db:"equality_provider" json:"equalityProvider"`
+
+	// Detail optionally holds why the file is considered modified, e.g. if it could no longer be parsed by its equality provider.
+	Detail string `db:"detail" json:"detail,omitempty"`
+}
+
+// The ServerDrift represents the result of verifying all files of all artefacts in the IS state of a server against the disk.
+type ServerDrift struct {
+	// ServerUUID is the uuid of the server the drift was computed for.
+	ServerUUID uuid.UUID `db:"server" json:"serverUUID"`
+
+	// CheckedAt is the time the drift was computed at.
+	CheckedAt time.Time `db:"checked_at" json:"checkedAt"`
+
+	// Files holds all files that drifted from their artefact.
+	Files []FileDrift `db:"-" json:"files"`
+}
+
+// HasDrift returns if any file of the server drifted from its artefact.
+func (s ServerDrift) HasDrift() bool {
+	return len(s.Files) > 0
+}
@@ -0,0 +1,36 @@
+package networkmodel
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// The ServerLogOptions define which part of a servers container logs is requested.
+type ServerLogOptions struct {
+	// Since only yields logs since the passed timestamp or relative duration, e.g. 2013-01-02T13:23:37Z or 42m.
+	// An empty value yields all logs.
+	Since string `json:"since"`
+
+	// Tail is the amount of lines to yield from the end of the logs, or "all".
+	// An empty value yields all lines.
+	Tail string `json:"tail"`
+
+	// Follow defines if the logs should be streamed until the request is cancelled or the container stops.
+	Follow bool `json:"follow"`
+}
+
+// QueryParameters encodes the log options into url query parameters.
+func (o ServerLogOptions) QueryParameters() url.Values {
+	parameters := url.Values{}
+	if o.Since != "" {
+		parameters.Set("since", o.Since)
+	}
+
+	if o.Tail != "" {
+		parameters.Set("tail", o.Tail)
+	}
+
+	parameters.Set("follow", strconv.FormatBool(o.Follow))
+
+	return parameters
+}
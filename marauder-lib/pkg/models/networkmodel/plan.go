@@ -0,0 +1,97 @@
+package networkmodel
+
+import (
+	"github.com/google/uuid"
+)
+
+// The FileUpdateChange defines what an update does to a single file in the server folder.
+type FileUpdateChange string
+
+const (
+	// FileUpdateCreate marks a file that does not exist on disk and is created by the update.
+	FileUpdateCreate FileUpdateChange = "create"
+
+	// FileUpdateOverwrite marks a file that exists on disk and is overwritten by the update.
+	FileUpdateOverwrite FileUpdateChange = "overwrite"
+
+	// FileUpdateDelete marks a file that is deleted by the update.
+	FileUpdateDelete FileUpdateChange = "delete"
+
+	// FileUpdateMerge marks a file that exists on disk and is three-way merged with its new version by the update.
+	FileUpdateMerge FileUpdateChange = "merge"
+)
+
+// The FileUpdatePlan represents the planned change of a single file in the server folder.
+type FileUpdatePlan struct {
+	// The Path of the file relative to the server folder.
+	Path string `json:"path"`
+
+	// The Change the update performs on the file.
+	Change FileUpdateChange `json:"change"`
+
+	// LosesLocalModification is set if the file on disk was modified locally and said modification is lost by the update.
+	// Files on disk not tracked by the currently installed artefact that are overwritten are also considered local modifications.
+	LosesLocalModification bool `json:"losesLocalModification"`
+}
+
+// The ArtefactUpdatePlan represents the planned update of a single artefact on the server.
+type ArtefactUpdatePlan struct {
+	// ArtefactIdentifier is the identifier of the artefact updated.
+	ArtefactIdentifier string `json:"artefactIdentifier"`
+
+	// FromVersion is the currently installed version of the artefact, nil if the artefact is installed by the update.
+	FromVersion *string `json:"fromVersion,omitempty"`
+
+	// ToVersion is the version the artefact is updated to, nil if the artefact is uninstalled by the update.
+	ToVersion *string `json:"toVersion,omitempty"`
+
+	// RequiresRestart defines if the artefact requires a restart of the server to be updated.
+	RequiresRestart bool `json:"requiresRestart"`
+
+	// Deferred is set if the artefact requires a restart but the planned update does not restart the server.
+	// Deferred artefacts are not updated and hence hold no files.
+	Deferred bool `json:"deferred"`
+
+	// Files holds the planned changes of all files of the artefact, sorted by their path.
+	Files []FileUpdatePlan `json:"files"`
+}
+
+// The UpdatePlan represents the changes an update of the deployments of a server would perform, computed without
+// performing them.
+type UpdatePlan struct {
+	// ServerUUID is the uuid of the server the plan was computed for.
+	ServerUUID uuid.UUID `json:"serverUUID"`
+
+	// The Action the plan was computed for.
+	Action LifecycleAction `json:"action"`
+
+	// ServerRunning defines if the server was running when the plan was computed.
+	ServerRunning bool `json:"serverRunning"`
+
+	// RequiresRestart is set if any artefact of the plan requires a restart of the server to be updated.
+	RequiresRestart bool `json:"requiresRestart"`
+
+	// FailsOnLocalModifications is set if the planned update is not forced and would hence fail on the files of the
+	// currently installed artefacts that were modified or are missing on disk.
+	FailsOnLocalModifications bool `json:"failsOnLocalModifications"`
+
+	// Artefacts holds the plan of all artefacts that are not up to date.
+	Artefacts []ArtefactUpdatePlan `json:"artefacts"`
+
+	// FileConflicts holds the files shipped by multiple artefacts after the update that were not explicitly allowed.
+	// The update is refused while any exist.
+	FileConflicts []FileConflict `json:"fileConflicts,omitempty"`
+}
+
+// LosesLocalModifications returns if applying the plan loses any local modification in the server folder.
+func (p UpdatePlan) LosesLocalModifications() bool {
+	for _, artefact := range p.Artefacts {
+		for _, file := range artefact.Files {
+			if file.LosesLocalModification {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -60,4 +60,8 @@ type ServerArtefactStateModel struct {
 	// The Type enum represents the type of the state.
 	// For more information, see ServerStateType and its respective values.
 	Type ServerStateType `db:"type" json:"type"`
+
+	// AllowFileConflicts marks a TARGET state that was explicitly allowed to ship files shipped by other artefacts on
+	// the server.
+	AllowFileConflicts bool `db:"allow_file_conflicts" json:"allowFileConflicts,omitempty"`
 }
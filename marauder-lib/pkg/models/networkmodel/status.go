@@ -0,0 +1,56 @@
+package networkmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContainerStateMissing is reported as the container state of a server if the operator could not find a container for it.
+const ContainerStateMissing = "missing"
+
+// ContainerStateRunning is the docker container state of a running container.
+const ContainerStateRunning = "running"
+
+// ManagementStatusRunning is the status reported by a server over its management socket once it fully started.
+const ManagementStatusRunning = "RUNNING"
+
+// The ServerRuntimeStatus represents the live status of a server as observed by its operator.
+type ServerRuntimeStatus struct {
+	// ServerUUID is the uuid of the server the status was computed for.
+	ServerUUID uuid.UUID `json:"serverUUID"`
+
+	// Environment is the environment of the server the status was computed for.
+	Environment string `json:"environment"`
+
+	// Name is the name of the server the status was computed for.
+	Name string `json:"name"`
+
+	// ContainerState holds the state of the docker container as reported by docker, e.g. running or exited.
+	// If no container exists for the server, the state is ContainerStateMissing.
+	ContainerState string `json:"containerState"`
+
+	// ContainerStartedAt holds the time the container was last started at, if a container exists.
+	ContainerStartedAt *time.Time `json:"containerStartedAt,omitempty"`
+
+	// ManagementStatus holds the status the server reported over its management socket, e.g. STARTING or RUNNING.
+	// It is nil if the server has no management socket or the socket could not be reached.
+	ManagementStatus *string `json:"managementStatus,omitempty"`
+
+	// ManagementError holds the error encountered while requesting the status over the management socket, if any.
+	ManagementError *string `json:"managementError,omitempty"`
+}
+
+// IsRunning returns if the status indicates a fully started server.
+// A server with a management socket is only considered running once it reported itself as running.
+func (s ServerRuntimeStatus) IsRunning(server ServerModel) bool {
+	if s.ContainerState != ContainerStateRunning {
+		return false
+	}
+
+	if server.ManagementSocketPath == "" {
+		return true
+	}
+
+	return s.ManagementStatus != nil && *s.ManagementStatus == ManagementStatusRunning
+}
@@ -0,0 +1,24 @@
+package networkmodel
+
+import (
+	"github.com/google/uuid"
+)
+
+// The ServerVariable represents a single variable defined for a server on the controller.
+// Variables are available to the files of artefacts deployed as templates onto the server.
+type ServerVariable struct {
+	// The Server the variable is defined for.
+	Server uuid.UUID `db:"server" json:"server"`
+
+	// The Key of the variable, unique per server.
+	Key string `db:"key" json:"key"`
+
+	// The Value of the variable.
+	Value string `db:"value" json:"value"`
+}
+
+// The UpdateServerVariableRequest is sent to the controller to define the value of a variable of a server.
+type UpdateServerVariableRequest struct {
+	// The Value of the variable.
+	Value string `json:"value"`
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
 
@@ -36,6 +38,15 @@ type Client interface {
 		action networkmodel.LifecycleAction,
 	) error
 
+	// FetchServerStatus fetches the live runtime status of the specific server from the operator.
+	FetchServerStatus(ctx context.Context, serverUUID uuid.UUID) (networkmodel.ServerRuntimeStatus, error)
+
+	// FetchServerDrift verifies the files deployed on the specific server on the operator and fetches their drift.
+	FetchServerDrift(ctx context.Context, serverUUID uuid.UUID) (networkmodel.ServerDrift, error)
+
+	// AttachConsole attaches to the console of the specific server on the operator via a websocket.
+	AttachConsole(ctx context.Context, serverUUID uuid.UUID) (*websocket.Conn, error)
+
 	// ScheduleCacheClear schedules the clearing of the caches on the operator for any cachable item older than the passed age.
 	ScheduleCacheClear(ctx context.Context, age time.Duration) error
 }
@@ -63,7 +74,7 @@ func (c HTTPClient) DoHTTPRequest(
 		return nil, fmt.Errorf("failed to execute configurator: %w", err)
 	}
 
-	response, err := c.Do(request)
+	response, err := tracing.DoHTTPRequest(c.Client, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to perform request: %w", err)
 	}
@@ -96,6 +107,38 @@ func (c HTTPClient) ExecuteLifecycleAction(
 	return nil
 }
 
+func (c HTTPClient) FetchServerStatus(ctx context.Context, serverUUID uuid.UUID) (networkmodel.ServerRuntimeStatus, error) {
+	response, err := c.DoHTTPRequest(ctx, http.MethodGet, fmt.Sprintf("/server/%s/status", serverUUID.String()), &bytes.Buffer{}, None)
+	if err != nil {
+		return networkmodel.ServerRuntimeStatus{}, fmt.Errorf("failed to create http request for server status: %w", err)
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	status, err := utils.HTTPResponseBind(response, networkmodel.ServerRuntimeStatus{})
+	if err != nil {
+		return networkmodel.ServerRuntimeStatus{}, fmt.Errorf("failed to fetch server status: %w", err)
+	}
+
+	return status, nil
+}
+
+func (c HTTPClient) FetchServerDrift(ctx context.Context, serverUUID uuid.UUID) (networkmodel.ServerDrift, error) {
+	response, err := c.DoHTTPRequest(ctx, http.MethodGet, fmt.Sprintf("/server/%s/drift", serverUUID.String()), &bytes.Buffer{}, None)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to create http request for server drift: %w", err)
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	drift, err := utils.HTTPResponseBind(response, networkmodel.ServerDrift{})
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to fetch server drift: %w", err)
+	}
+
+	return drift, nil
+}
+
 func (c HTTPClient) ScheduleCacheClear(ctx context.Context, age time.Duration) error {
 	response, err := utils.PerformHTTPRequest(
 		ctx,
@@ -117,3 +160,12 @@ func (c HTTPClient) ScheduleCacheClear(ctx context.Context, age time.Duration) e
 
 	return nil
 }
+
+func (c HTTPClient) AttachConsole(ctx context.Context, serverUUID uuid.UUID) (*websocket.Conn, error) {
+	conn, err := utils.DialWebsocket(ctx, c.Client, fmt.Sprintf("%s/v1/server/%s/attach", c.OperatorURL, serverUUID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to console: %w", err)
+	}
+
+	return conn, nil
+}
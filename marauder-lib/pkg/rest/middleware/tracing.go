@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing creates the middleware continuing the trace propagated in the request headers in a server span.
+// The span is stored in the requests context, so the engine needs ContextWithFallback enabled for handlers passing the
+// gin context on.
+func Tracing() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(context.Request.Context(), propagation.HeaderCarrier(context.Request.Header))
+
+		route := context.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx, span := otel.Tracer(tracing.InstrumentationName).Start(
+			ctx,
+			context.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(context.Request.Method),
+				semconv.HTTPRoute(route),
+			),
+		)
+		defer span.End()
+
+		context.Request = context.Request.WithContext(ctx)
+		context.Next()
+
+		status := context.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used by all marauder components.
+const InstrumentationName = "github.com/knockturnmc/marauder"
+
+// The Configuration type holds the configuration of the OTLP trace exporter of a marauder component.
+type Configuration struct {
+	// Endpoint is the url of the OTLP http endpoint traces are exported to, e.g. http://localhost:4318.
+	// Spans are not exported if the endpoint is empty, trace contexts are however still propagated.
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers are additional headers sent to the endpoint with each export, e.g. for authentication.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// SampleRatio is the ratio of new traces that are sampled. Traces continued from a remote parent follow the parents decision.
+	// If not set, all traces are sampled.
+	SampleRatio *float64 `yaml:"sampleRatio,omitempty"`
+}
+
+// Setup configures the global tracer provider and W3C trace context propagation for the passed component.
+func Setup(ctx context.Context, serviceName string, serviceVersion string, configuration Configuration) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if strings.TrimSpace(configuration.Endpoint) == "" {
+		return nil
+	}
+
+	exporter, err := otlptracehttp.New(
+		ctx,
+		otlptracehttp.WithEndpointURL(configuration.Endpoint),
+		otlptracehttp.WithHeaders(configuration.Headers),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create otlp trace exporter: %w", err)
+	}
+
+	sampleRatio := 1.0
+	if configuration.SampleRatio != nil {
+		sampleRatio = *configuration.SampleRatio
+	}
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(serviceVersion),
+		)),
+	))
+
+	return nil
+}
+
+// Shutdown flushes all spans not yet exported and shuts down the global tracer provider, if it was configured by Setup.
+func Shutdown(ctx context.Context) error {
+	provider, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		return nil
+	}
+
+	if err := provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown tracer provider: %w", err)
+	}
+
+	return nil
+}
+
+// Start starts a new span as a child of the span found in the passed context.
+func Start(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// End ends the passed span, recording the passed error on it if non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// InjectMap injects the trace context of the passed context into a new string map, e.g. for transport in a proto message.
+func InjectMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	return carrier
+}
+
+// ExtractMap extracts a trace context previously injected via InjectMap into the passed context.
+func ExtractMap(ctx context.Context, traceContext map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+// DoHTTPRequest executes the passed request on the http client inside a client span, propagating the trace context via
+// the request headers.
+func DoHTTPRequest(httpClient *http.Client, request *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(InstrumentationName).Start(
+		request.Context(),
+		"HTTP "+request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(request.Method),
+			semconv.URLFull(request.URL.Redacted()),
+		),
+	)
+	defer span.End()
+
+	request = request.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, response.Status)
+	}
+
+	return response, nil
+}
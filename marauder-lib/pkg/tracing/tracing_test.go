@@ -0,0 +1,109 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collectorStub is an in-process OTLP http collector recording the trace ids of all spans exported to it by span name.
+type collectorStub struct {
+	mutex  sync.Mutex
+	traces map[string][]byte
+}
+
+func (c *collectorStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil || request.URL.Path != "/v1/traces" {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var exportRequest collectortrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &exportRequest); err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, resourceSpans := range exportRequest.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			for _, span := range scopeSpans.GetSpans() {
+				c.traces[span.GetName()] = span.GetTraceId()
+			}
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/x-protobuf")
+	writer.WriteHeader(http.StatusOK)
+}
+
+var _ = Describe("tracing", Label("unittest"), Ordered, func() {
+	var (
+		collector       *collectorStub
+		collectorServer *httptest.Server
+	)
+
+	BeforeAll(func() {
+		collector = &collectorStub{traces: make(map[string][]byte)}
+		collectorServer = httptest.NewServer(collector)
+
+		Expect(tracing.Setup(context.Background(), "marauder-test", "test", tracing.Configuration{
+			Endpoint: collectorServer.URL,
+		})).To(Succeed())
+	})
+
+	AfterAll(func() {
+		collectorServer.Close()
+	})
+
+	It("should carry the trace context through a string map", func() {
+		ctx, span := tracing.Start(context.Background(), "map carrier")
+		defer span.End()
+
+		extractedContext := tracing.ExtractMap(context.Background(), tracing.InjectMap(ctx))
+		_, childSpan := tracing.Start(extractedContext, "child")
+		defer childSpan.End()
+
+		Expect(span.SpanContext().IsValid()).To(BeTrue())
+		Expect(childSpan.SpanContext().TraceID()).To(Equal(span.SpanContext().TraceID()))
+	})
+
+	It("should propagate the trace context and export spans to the collector", func() {
+		var receivedTraceParent string
+		remoteServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			receivedTraceParent = request.Header.Get("traceparent")
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer remoteServer.Close()
+
+		ctx, span := tracing.Start(context.Background(), "test operation")
+		response, err := utils.PerformHTTPRequest(ctx, http.DefaultClient, http.MethodGet, remoteServer.URL, "application/json", &bytes.Buffer{})
+		Expect(err).To(Not(HaveOccurred()))
+		_ = response.Body.Close()
+		tracing.End(span, nil)
+
+		Expect(receivedTraceParent).To(ContainSubstring(span.SpanContext().TraceID().String()))
+
+		Expect(tracing.Shutdown(context.Background())).To(Succeed())
+
+		collector.mutex.Lock()
+		defer collector.mutex.Unlock()
+
+		expectedTraceID := span.SpanContext().TraceID()
+		Expect(collector.traces).To(HaveKeyWithValue("test operation", expectedTraceID[:]))
+		Expect(collector.traces).To(HaveKeyWithValue("HTTP GET", expectedTraceID[:]))
+	})
+})
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+)
+
+// ErrUnsafeArtefactEntry is returned if an entry of an artefact tarball would be deployed outside the server folder or
+// is of a type marauder does not deploy.
+var ErrUnsafeArtefactEntry = errors.New("unsafe artefact entry")
+
+// ValidateArtefactEntry validates that the passed header of an artefact tarball is either the manifest or a regular
+// file or symbolic link in the files folder, whose path, as well as the target of a symbolic link, stays inside the
+// server folder.
+func ValidateArtefactEntry(header *tar.Header) error {
+	if header.Name == pkg.ManifestFileName {
+		if header.Typeflag != tar.TypeReg {
+			return fmt.Errorf("manifest is not a regular file: %w", ErrUnsafeArtefactEntry)
+		}
+
+		return nil
+	}
+
+	pathInServerFolder, err := ArtefactPathInServerFolder(header.Name)
+	if err != nil {
+		return err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeReg:
+		return nil
+	case tar.TypeSymlink:
+		if !IsLocalSymlinkTarget(pathInServerFolder, header.Linkname) {
+			return fmt.Errorf("symlink %s points to %s: %w", header.Name, header.Linkname, errors.Join(ErrUnsafeArtefactEntry, ErrUnsafeSymlink))
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("entry %s is of unsupported type %q: %w", header.Name, header.Typeflag, ErrUnsafeArtefactEntry)
+	}
+}
+
+// ArtefactPathInServerFolder yields back the slash separated path relative to the server folder that the passed file in
+// the files folder of an artefact tarball is deployed to.
+// Paths outside the files folder, paths containing `..` segments and paths that resolve to the server folder itself are
+// rejected rather than cleaned.
+func ArtefactPathInServerFolder(pathInTarball string) (string, error) {
+	pathInServerFolder, found := strings.CutPrefix(pathInTarball, pkg.FileParentDirectoryInArtefact)
+	if !found {
+		return "", fmt.Errorf("entry %s is outside the files folder: %w", pathInTarball, ErrUnsafeArtefactEntry)
+	}
+
+	for segment := range strings.SplitSeq(pathInServerFolder, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("entry %s traverses parent directories: %w", pathInTarball, ErrUnsafeArtefactEntry)
+		}
+	}
+
+	// Targets of file references may start with a slash, yielding a path relative to the server folder nonetheless.
+	cleanedPath := strings.TrimPrefix(path.Clean("/"+pathInServerFolder), "/")
+	if !filepath.IsLocal(filepath.FromSlash(cleanedPath)) {
+		return "", fmt.Errorf("entry %s is not a file in the server folder: %w", pathInTarball, ErrUnsafeArtefactEntry)
+	}
+
+	return cleanedPath, nil
+}
@@ -0,0 +1,82 @@
+package utils_test
+
+import (
+	"archive/tar"
+	"path"
+	"path/filepath"
+	"testing"
+
+	. "github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// maliciousArtefactEntries holds tarball headers crafted to write outside the server folder an artefact is deployed to.
+var maliciousArtefactEntries = map[string]tar.Header{
+	"parent traversal":             {Name: "files/../escape.sh", Typeflag: tar.TypeReg},
+	"nested parent traversal":      {Name: "files/plugins/../../../escape.sh", Typeflag: tar.TypeReg},
+	"traversal resolving inside":   {Name: "files/plugins/../spellcore.jar", Typeflag: tar.TypeReg},
+	"absolute path":                {Name: "/etc/cron.d/escape", Typeflag: tar.TypeReg},
+	"outside the files folder":     {Name: "escape.sh", Typeflag: tar.TypeReg},
+	"the server folder itself":     {Name: "files/", Typeflag: tar.TypeReg},
+	"directory":                    {Name: "files/plugins/", Typeflag: tar.TypeDir},
+	"hardlink":                     {Name: "files/passwd", Typeflag: tar.TypeLink, Linkname: "/etc/passwd"},
+	"character device":             {Name: "files/tty", Typeflag: tar.TypeChar},
+	"fifo":                         {Name: "files/pipe", Typeflag: tar.TypeFifo},
+	"absolute symlink":             {Name: "files/plugins", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+	"symlink escaping":             {Name: "files/plugins/spellcore.jar", Typeflag: tar.TypeSymlink, Linkname: "../../spellcore.jar"},
+	"symlink to the parent folder": {Name: "files/server", Typeflag: tar.TypeSymlink, Linkname: ".."},
+	"manifest symlink":             {Name: "manifest.json", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+}
+
+var _ = Describe("Validating artefact entries", Label("unittest"), func() {
+	It("accepts regular files and symlinks inside the server folder", func() {
+		Expect(ValidateArtefactEntry(&tar.Header{Name: "manifest.json", Typeflag: tar.TypeReg})).To(Succeed())
+		Expect(ValidateArtefactEntry(&tar.Header{Name: "files/plugins/spellcore.jar", Typeflag: tar.TypeReg})).To(Succeed())
+		Expect(ValidateArtefactEntry(&tar.Header{Name: "files//plugins/spellcore.jar", Typeflag: tar.TypeReg})).To(Succeed())
+		Expect(ValidateArtefactEntry(&tar.Header{
+			Name:     "files/plugins/spellcore.jar",
+			Typeflag: tar.TypeSymlink,
+			Linkname: "../libs/spellcore-1.14.jar",
+		})).To(Succeed())
+	})
+
+	It("yields back the cleaned path in the server folder", func() {
+		Expect(ArtefactPathInServerFolder("files//plugins/./spellcore.jar")).To(Equal("plugins/spellcore.jar"))
+	})
+
+	for name, header := range maliciousArtefactEntries {
+		It("rejects a "+name, func() {
+			Expect(ValidateArtefactEntry(&header)).To(MatchError(ErrUnsafeArtefactEntry))
+		})
+	}
+})
+
+func FuzzValidateArtefactEntry(f *testing.F) {
+	for _, header := range maliciousArtefactEntries {
+		f.Add(header.Name, header.Typeflag, header.Linkname)
+	}
+
+	f.Add("files/plugins/spellcore.jar", byte(tar.TypeReg), "")
+	f.Add("files/plugins/spellcore.jar", byte(tar.TypeSymlink), "../libs/spellcore-1.14.jar")
+
+	f.Fuzz(func(t *testing.T, name string, typeflag byte, linkname string) {
+		header := &tar.Header{Name: name, Typeflag: typeflag, Linkname: linkname}
+		if err := ValidateArtefactEntry(header); err != nil || name == "manifest.json" {
+			return
+		}
+
+		pathInServerFolder, err := ArtefactPathInServerFolder(name)
+		if err != nil {
+			t.Fatalf("accepted entry %q has no path in the server folder: %s", name, err)
+		}
+
+		if !filepath.IsLocal(filepath.FromSlash(pathInServerFolder)) {
+			t.Fatalf("accepted entry %q resolves to %q outside the server folder", name, pathInServerFolder)
+		}
+
+		if typeflag == tar.TypeSymlink && !filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(pathInServerFolder), linkname))) {
+			t.Fatalf("accepted symlink %q points to %q outside the server folder", name, linkname)
+		}
+	})
+}
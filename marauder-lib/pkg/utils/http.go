@@ -10,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/http"
 
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -109,7 +110,7 @@ func PerformHTTPRequest(
 
 	postRequest.Header.Set("Content-Type", contentType)
 
-	response, err := httpClient.Do(postRequest)
+	response, err := tracing.DoHTTPRequest(httpClient, postRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute post request: %w", err)
 	}
@@ -130,3 +131,34 @@ func WriteFileToMultipart(multipartWriter *multipart.Writer, file io.Reader, nam
 
 	return nil
 }
+
+// CopyAndFlush copies the reader into the writer, flushing the writer after each written chunk if it is a http.Flusher.
+// This allows streamed responses, e.g. followed logs, to reach the remote immediately instead of being buffered.
+func CopyAndFlush(writer io.Writer, reader io.Reader) (int64, error) {
+	flusher, canFlush := writer.(http.Flusher)
+	buffer := make([]byte, 32*1024)
+
+	var written int64
+	for {
+		readBytes, readErr := reader.Read(buffer)
+		if readBytes > 0 {
+			writtenBytes, err := writer.Write(buffer[:readBytes])
+			written += int64(writtenBytes)
+			if err != nil {
+				return written, fmt.Errorf("failed to write chunk: %w", err)
+			}
+
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return written, nil
+			}
+
+			return written, fmt.Errorf("failed to read chunk: %w", readErr)
+		}
+	}
+}
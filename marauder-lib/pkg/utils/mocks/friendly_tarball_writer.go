@@ -259,6 +259,78 @@ func (_c *MockFriendlyTarballWriter_AddFolder_Call) RunAndReturn(run func(rootFs
 	return _c
 }
 
+// AddSymlink provides a mock function for the type MockFriendlyTarballWriter
+func (_mock *MockFriendlyTarballWriter) AddSymlink(rootFs fs.FS, filePathInFS string, filePathInTarball string) (utils.WriteResult, error) {
+	ret := _mock.Called(rootFs, filePathInFS, filePathInTarball)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSymlink")
+	}
+
+	var r0 utils.WriteResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(fs.FS, string, string) (utils.WriteResult, error)); ok {
+		return returnFunc(rootFs, filePathInFS, filePathInTarball)
+	}
+	if returnFunc, ok := ret.Get(0).(func(fs.FS, string, string) utils.WriteResult); ok {
+		r0 = returnFunc(rootFs, filePathInFS, filePathInTarball)
+	} else {
+		r0 = ret.Get(0).(utils.WriteResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(fs.FS, string, string) error); ok {
+		r1 = returnFunc(rootFs, filePathInFS, filePathInTarball)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFriendlyTarballWriter_AddSymlink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSymlink'
+type MockFriendlyTarballWriter_AddSymlink_Call struct {
+	*mock.Call
+}
+
+// AddSymlink is a helper method to define mock.On call
+//   - rootFs fs.FS
+//   - filePathInFS string
+//   - filePathInTarball string
+func (_e *MockFriendlyTarballWriter_Expecter) AddSymlink(rootFs interface{}, filePathInFS interface{}, filePathInTarball interface{}) *MockFriendlyTarballWriter_AddSymlink_Call {
+	return &MockFriendlyTarballWriter_AddSymlink_Call{Call: _e.mock.On("AddSymlink", rootFs, filePathInFS, filePathInTarball)}
+}
+
+func (_c *MockFriendlyTarballWriter_AddSymlink_Call) Run(run func(rootFs fs.FS, filePathInFS string, filePathInTarball string)) *MockFriendlyTarballWriter_AddSymlink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 fs.FS
+		if args[0] != nil {
+			arg0 = args[0].(fs.FS)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockFriendlyTarballWriter_AddSymlink_Call) Return(writeResult utils.WriteResult, err error) *MockFriendlyTarballWriter_AddSymlink_Call {
+	_c.Call.Return(writeResult, err)
+	return _c
+}
+
+func (_c *MockFriendlyTarballWriter_AddSymlink_Call) RunAndReturn(run func(rootFs fs.FS, filePathInFS string, filePathInTarball string) (utils.WriteResult, error)) *MockFriendlyTarballWriter_AddSymlink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function for the type MockFriendlyTarballWriter
 func (_mock *MockFriendlyTarballWriter) Close() error {
 	ret := _mock.Called()
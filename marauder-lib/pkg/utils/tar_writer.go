@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/fs"
 	"strings"
+	"time"
 
 	"github.com/samber/mo"
 )
@@ -39,6 +40,10 @@ type FriendlyTarballWriter interface {
 	// Write writes the specific file content and the passed header directly to the tarball.
 	Write(fileContent []byte, header tar.Header) error
 
+	// AddSymlink writes the symbolic link in the root fs located at the filePathInFS as a link to the tar ball at the
+	// filePathInTarball path. The root fs has to implement fs.ReadLinkFS.
+	AddSymlink(rootFs fs.FS, filePathInFS string, filePathInTarball string) (WriteResult, error)
+
 	// AddFolder writes a whole from the root fs located at the filePathInFS to the tar ball at the filePathInTarball path.
 	AddFolder(rootFs fs.FS, folderPathInFS string, folderPathInTarball string) ([]WriteResult, error)
 
@@ -52,6 +57,8 @@ type FriendlyTarballWriterImpl struct {
 	writerChain   []ClosableWriter
 	tarballWriter *tar.Writer
 	filter        func(string, string) bool
+	reproducible  bool
+	modTime       time.Time
 }
 
 // NewFriendlyTarballWriterGZ constructs a new FriendlyTarballWriter that writes to the passed writer.
@@ -69,9 +76,58 @@ func NewFriendlyTarballWriterGZ(writer io.Writer, level int) (*FriendlyTarballWr
 		filter: func(s string, s2 string) bool {
 			return true
 		},
+		modTime: time.Unix(0, 0),
 	}, nil
 }
 
+// WithReproducibleHeaders mutates this tarball writer to normalise the headers of all written files, writing the same
+// files yields byte-identical tarballs regardless of their owners, timestamps or the umask of the file system they are
+// read from. Tarballs that have to restore the exact permissions of their files, like backups, must not normalise them.
+func (f *FriendlyTarballWriterImpl) WithReproducibleHeaders() *FriendlyTarballWriterImpl {
+	f.reproducible = true
+	return f
+}
+
+// WithModTime mutates this tarball writer to write all files with the passed modification time instead of the unix epoch.
+// The modification time is only used by tarball writers with reproducible headers.
+func (f *FriendlyTarballWriterImpl) WithModTime(modTime time.Time) *FriendlyTarballWriterImpl {
+	f.modTime = modTime
+	return f
+}
+
+// normaliseHeader strips all information from the header that differs between otherwise identical files.
+// Owners and access times are removed, the modification time is fixed and the permissions are normalised via
+// NormalisedFileMode. Headers are left untouched unless the writer was configured via WithReproducibleHeaders.
+func (f *FriendlyTarballWriterImpl) normaliseHeader(header *tar.Header) {
+	if !f.reproducible {
+		return
+	}
+
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+	header.ModTime = f.modTime.UTC().Truncate(time.Second)
+	header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+	header.PAXRecords = nil
+	header.Format = tar.FormatUnknown
+
+	if header.Typeflag == tar.TypeSymlink {
+		header.Mode = int64(fs.ModePerm)
+		return
+	}
+
+	header.Mode = int64(NormalisedFileMode(fs.FileMode(header.Mode))) //nolint:gosec
+}
+
+// NormalisedFileMode normalises the permissions of a file like git does, files executable by their owner are
+// normalised to 0o755, all other files to 0o644.
+func NormalisedFileMode(mode fs.FileMode) fs.FileMode {
+	if mode&0o100 != 0 {
+		return 0o755
+	}
+
+	return 0o644
+}
+
 // Close closes the friendly tarball writer and all owned writers of it.
 func (f *FriendlyTarballWriterImpl) Close() error {
 	var lastErr error
@@ -92,6 +148,7 @@ func (f *FriendlyTarballWriterImpl) Close() error {
 func (f *FriendlyTarballWriterImpl) Write(fileContent []byte, header tar.Header) error {
 	// Explicitly update the size of the file in the header
 	header.Size = int64(len(fileContent))
+	f.normaliseHeader(&header)
 
 	if err := f.tarballWriter.WriteHeader(&header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
@@ -156,6 +213,7 @@ func (f *FriendlyTarballWriterImpl) AddFile(rootFs fs.FS, filePathInFS string, f
 	}
 
 	header.Name = filePathInTarball
+	f.normaliseHeader(header)
 	if err := f.tarballWriter.WriteHeader(header); err != nil {
 		return WriteResult{}, fmt.Errorf("failed to write tarball header for %s: %w", filePathInFS, err)
 	}
@@ -170,6 +228,40 @@ func (f *FriendlyTarballWriterImpl) AddFile(rootFs fs.FS, filePathInFS string, f
 	}, nil
 }
 
+// AddSymlink writes the symbolic link found at the filePathInFS in the passed file system as a link to the tarball.
+func (f *FriendlyTarballWriterImpl) AddSymlink(rootFs fs.FS, filePathInFS string, filePathInTarball string) (WriteResult, error) {
+	stat, err := fs.Lstat(rootFs, filePathInFS)
+	if err != nil {
+		return WriteResult{}, fmt.Errorf("failed to read stat of symlink %s: %w", filePathInFS, err)
+	}
+
+	target, err := fs.ReadLink(rootFs, filePathInFS)
+	if err != nil {
+		return WriteResult{}, fmt.Errorf("failed to read target of symlink %s: %w", filePathInFS, err)
+	}
+
+	// Check the filter, do not add file if filter yields false.
+	if !f.filter(filePathInFS, filePathInTarball) {
+		return WriteResult{PathInRootFS: filePathInFS}, nil
+	}
+
+	header, err := tar.FileInfoHeader(stat, target)
+	if err != nil {
+		return WriteResult{}, fmt.Errorf("failed to construct tar header for %s: %w", filePathInFS, err)
+	}
+
+	header.Name = filePathInTarball
+	f.normaliseHeader(header)
+	if err := f.tarballWriter.WriteHeader(header); err != nil {
+		return WriteResult{}, fmt.Errorf("failed to write tarball header for %s: %w", filePathInFS, err)
+	}
+
+	return WriteResult{
+		PathInRootFS:  filePathInFS,
+		PathInTarball: mo.Some(filePathInTarball),
+	}, nil
+}
+
 // AddFolder writes the entire folder found in the fs into the tarball.
 func (f *FriendlyTarballWriterImpl) AddFolder(rootFs fs.FS, folderPathInFS string, folderPathInTarball string) ([]WriteResult, error) {
 	results := make([]WriteResult, 0)
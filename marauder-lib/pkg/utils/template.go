@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os/user"
 	"text/template"
 )
@@ -37,3 +38,34 @@ func EvaluateFilePathTemplate(filePathTemplate string) (string, error) {
 
 	return filePathTemplate, nil
 }
+
+// ParseFileTemplate parses the content of a file deployed as a template.
+// Templates fail to execute if they reference keys missing in the data they are executed with.
+func ParseFileTemplate(name string, content string) (*template.Template, error) {
+	parsed, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file template %s: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+// ExecuteFileTemplate reads the template file from the passed reader and executes it given the passed data.
+func ExecuteFileTemplate[Data any](name string, reader io.Reader, data Data) ([]byte, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file template %s: %w", name, err)
+	}
+
+	parsed, err := ParseFileTemplate(name, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := parsed.Execute(&buffer, data); err != nil {
+		return nil, fmt.Errorf("failed to execute file template %s: %w", name, err)
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -1,14 +1,19 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 )
 
+// ErrUnsafeSymlink is returned if a symbolic link points to a target outside the folder it is deployed into.
+var ErrUnsafeSymlink = errors.New("unsafe symlink")
+
 // OrElse resolves a pointer to a type T to the value at the pointer or a default
 // value if the pointer is a nilpointer.
 func OrElse[T any](nillable *T, defaultVal T) T {
@@ -55,6 +60,16 @@ func CleanPathAndJoin(parent, path string) string {
 	return filepath.Join(parent, filepath.FromSlash("/"+path))
 }
 
+// IsLocalSymlinkTarget returns if the target of a symbolic link at the passed path is relative and resolves to a path
+// within the directory the path is relative to. Both the path and the target are slash separated.
+func IsLocalSymlinkTarget(linkPath string, target string) bool {
+	if target == "" || path.IsAbs(target) {
+		return false
+	}
+
+	return filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(linkPath), target)))
+}
+
 func CleanPathWorkingDir(path string) string {
 	getwd, err := os.Getwd()
 	if err != nil {
@@ -2,6 +2,8 @@ package utils_test
 
 import (
 	"bytes"
+	"net/http/httptest"
+	"strings"
 
 	. "github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	. "github.com/onsi/ginkgo/v2"
@@ -34,4 +36,37 @@ var _ = Describe("Utils", Label("unittest"), func() {
 			Expect(toInt).To(BeEquivalentTo(-103))
 		})
 	})
+
+	Describe("Copying and flushing a stream", func() {
+		It("copies the entire stream and flushes the writer", func() {
+			recorder := httptest.NewRecorder()
+			written, err := CopyAndFlush(recorder, strings.NewReader("line one\nline two\n"))
+
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(written).To(BeEquivalentTo(len("line one\nline two\n")))
+			Expect(recorder.Body.String()).To(Equal("line one\nline two\n"))
+			Expect(recorder.Flushed).To(BeTrue())
+		})
+
+		It("works fine for writers that cannot flush", func() {
+			var buffer bytes.Buffer
+			_, err := CopyAndFlush(&buffer, strings.NewReader("content"))
+
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(buffer.String()).To(Equal("content"))
+		})
+	})
+
+	Describe("Validating symlink targets", func() {
+		It("accepts relative targets inside the folder", func() {
+			Expect(IsLocalSymlinkTarget("plugins/spellcore.jar", "../libs/spellcore-1.14.jar")).To(BeTrue())
+			Expect(IsLocalSymlinkTarget("start.sh", "scripts/start.sh")).To(BeTrue())
+		})
+
+		It("rejects targets escaping the folder", func() {
+			Expect(IsLocalSymlinkTarget("plugins/spellcore.jar", "../../spellcore.jar")).To(BeFalse())
+			Expect(IsLocalSymlinkTarget("start.sh", "/usr/local/bin/start.sh")).To(BeFalse())
+			Expect(IsLocalSymlinkTarget("start.sh", "")).To(BeFalse())
+		})
+	})
 })
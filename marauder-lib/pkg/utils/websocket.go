@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// DialWebsocket dials the websocket at the passed http(s) url, re-using the tls configuration of the passed http client.
+// The trace context of the passed context is propagated in the handshake.
+func DialWebsocket(ctx context.Context, httpClient *http.Client, url string) (_ *websocket.Conn, err error) {
+	dialer := *websocket.DefaultDialer
+	if transport, ok := httpClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		dialer.TLSClientConfig = transport.TLSClientConfig.Clone()
+	}
+
+	ctx, span := tracing.Start(ctx, "websocket dial")
+	defer func() { tracing.End(span, err) }()
+
+	header := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+
+	websocketURL := "ws" + strings.TrimPrefix(url, "http")
+	conn, response, err := dialer.DialContext(ctx, websocketURL, header)
+	if err != nil {
+		if response != nil {
+			defer func() { _ = response.Body.Close() }()
+
+			if statusErr := IsOkayStatusCodeOrErrorWithBody(response); statusErr != nil {
+				err = statusErr
+			}
+		}
+
+		return nil, fmt.Errorf("failed to dial websocket %s: %w", websocketURL, err)
+	}
+
+	return conn, nil
+}
+
+// PipeWebsocket copies all messages read from the source websocket to the target websocket until either fails.
+// A normal closure of the source websocket is forwarded to the target and yields no error.
+func PipeWebsocket(source *websocket.Conn, target *websocket.Conn) error {
+	for {
+		messageType, message, err := source.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				_ = target.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeErr.Code, closeErr.Text))
+				return nil
+			}
+
+			return fmt.Errorf("failed to read websocket message: %w", err)
+		}
+
+		if err := target.WriteMessage(messageType, message); err != nil {
+			return fmt.Errorf("failed to write websocket message: %w", err)
+		}
+	}
+}
+
+// TunnelWebsockets pipes all messages between the two websockets in both directions until either side closes or fails.
+func TunnelWebsockets(first *websocket.Conn, second *websocket.Conn) error {
+	errChan := make(chan error, 2)
+	go func() { errChan <- PipeWebsocket(first, second) }()
+	go func() { errChan <- PipeWebsocket(second, first) }()
+
+	// The first finished direction ends the tunnel, closing both sides unblocks the other direction.
+	err := <-errChan
+	_ = first.Close()
+	_ = second.Close()
+	<-errChan
+
+	return err
+}
@@ -0,0 +1,94 @@
+package utils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/websocket"
+	. "github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tunneling websockets", Label("unittest"), func() {
+	var (
+		upgrader     websocket.Upgrader
+		echoServer   *httptest.Server
+		tunnelServer *httptest.Server
+	)
+
+	BeforeEach(func() {
+		echoServer = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			conn, err := upgrader.Upgrade(writer, request, nil)
+			if err != nil {
+				return
+			}
+
+			defer func() { _ = conn.Close() }()
+
+			for {
+				messageType, message, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+
+				if err := conn.WriteMessage(messageType, message); err != nil {
+					return
+				}
+			}
+		}))
+
+		tunnelServer = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			targetConn, err := DialWebsocket(request.Context(), http.DefaultClient, echoServer.URL)
+			if err != nil {
+				writer.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			clientConn, err := upgrader.Upgrade(writer, request, nil)
+			if err != nil {
+				_ = targetConn.Close()
+				return
+			}
+
+			_ = TunnelWebsockets(clientConn, targetConn)
+		}))
+	})
+
+	AfterEach(func() {
+		tunnelServer.Close()
+		echoServer.Close()
+	})
+
+	It("should forward messages in both directions", func() {
+		conn, err := DialWebsocket(context.Background(), http.DefaultClient, tunnelServer.URL)
+		Expect(err).To(Not(HaveOccurred()))
+
+		defer func() { _ = conn.Close() }()
+
+		Expect(conn.WriteMessage(websocket.BinaryMessage, []byte("console input"))).To(Succeed())
+		messageType, message, err := conn.ReadMessage()
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(messageType).To(Equal(websocket.BinaryMessage))
+		Expect(string(message)).To(Equal("console input"))
+
+		Expect(conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"resize"}`))).To(Succeed())
+		messageType, message, err = conn.ReadMessage()
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(messageType).To(Equal(websocket.TextMessage))
+		Expect(string(message)).To(Equal(`{"type":"resize"}`))
+	})
+
+	It("should yield the response of a failed handshake", func() {
+		failingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusForbidden)
+			_, _ = writer.Write([]byte("not allowed"))
+		}))
+		defer failingServer.Close()
+
+		_, err := DialWebsocket(context.Background(), http.DefaultClient, failingServer.URL)
+		Expect(err).To(MatchError(ErrBadStatusCode))
+		Expect(err.Error()).To(ContainSubstring("not allowed"))
+	})
+})
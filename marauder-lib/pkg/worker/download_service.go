@@ -2,13 +2,16 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Goldziher/go-utils/maputils"
@@ -19,13 +22,36 @@ import (
 // from multiple threads at the same time.
 type DownloadService interface {
 	// Download downloads the file from the given url into a service specific cache folder under the passed name.
+	// A file previously downloaded under the same name is reused from the cache folder instead of downloading it again,
+	// hence the name has to identify the content behind the url.
 	// The method returns the full path of the file, ready for future use or an error.
 	Download(ctx context.Context, url string, filename string) (string, error)
 
+	// Evict removes the file cached under the passed name, forcing the next download of it to fetch it again.
+	Evict(filename string) error
+
 	// CleanLocalCache cleans the local download cache.
 	// For this, any file older than the provided file age is removed if the download service does not currently have a running job to download said
 	// file.
 	CleanLocalCache(fileAge time.Duration) error
+
+	// Stats computes the current statistics of the download service and its local cache.
+	Stats() (DownloadServiceStats, error)
+}
+
+// DownloadServiceStats holds statistics about the usage of a download service and its local cache.
+type DownloadServiceStats struct {
+	// CacheHits counts the download requests served from a file found in the local cache.
+	CacheHits uint64
+
+	// CoalescedDownloads counts the download requests that were coalesced into an already running download of the same url.
+	CoalescedDownloads uint64
+
+	// CacheMisses counts the download requests that required a new download.
+	CacheMisses uint64
+
+	// CacheSizeBytes is the total size of all files currently found in the local cache.
+	CacheSizeBytes int64
 }
 
 // DownloadResult is a smaller helper utility representing the result retrieved from a download dispatcher.
@@ -44,6 +70,9 @@ type MutexDownloadService struct {
 	runningDownloads     map[string]*RunningDownload
 	resultListenersMutex *sync.Mutex
 	cacheDirectory       string
+	cacheHits            *atomic.Uint64
+	coalescedDownloads   *atomic.Uint64
+	cacheMisses          *atomic.Uint64
 }
 
 // NewMutexDownloadService creates a new download service using a mutex.
@@ -54,6 +83,9 @@ func NewMutexDownloadService(httpClient *http.Client, dispatcher *Dispatcher[Dow
 		runningDownloads:     make(map[string]*RunningDownload),
 		resultListenersMutex: &sync.Mutex{},
 		cacheDirectory:       cacheDirectory,
+		cacheHits:            &atomic.Uint64{},
+		coalescedDownloads:   &atomic.Uint64{},
+		cacheMisses:          &atomic.Uint64{},
 	}
 }
 
@@ -61,11 +93,22 @@ func (m *MutexDownloadService) Download(ctx context.Context, url string, filenam
 	m.resultListenersMutex.Lock() // Lock here, we are going to read from the map.
 
 	runningDownload, ok := m.runningDownloads[url]
+	if !ok {
+		if cachedFile, found := m.findCachedFile(filename); found {
+			m.cacheHits.Add(1)
+			m.resultListenersMutex.Unlock()
+
+			return cachedFile, nil
+		}
+	}
+
 	resultChan := make(chan Outcome[DownloadResult])
 	if ok {
+		m.coalescedDownloads.Add(1)
 		//nolint:gocritic // this is fine, we mutate on purpose
 		m.runningDownloads[url].Listeners = append(runningDownload.Listeners, resultChan)
 	} else {
+		m.cacheMisses.Add(1)
 		m.runningDownloads[url] = &RunningDownload{
 			Listeners:      []chan Outcome[DownloadResult]{resultChan},
 			TargetFileName: filename,
@@ -104,6 +147,34 @@ func (m *MutexDownloadService) Download(ctx context.Context, url string, filenam
 	return outcome.Value.fullFileName, nil
 }
 
+// findCachedFile finds the file cached under the passed name, refreshing its modification time so that the cache cleanup
+// keeps files that are still in use. Must be called while holding the result listeners mutex.
+func (m *MutexDownloadService) findCachedFile(filename string) (string, bool) {
+	cachedFile := m.cachePathOf(filename)
+	if _, err := os.Stat(cachedFile); err != nil {
+		return "", false
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(cachedFile, now, now); err != nil {
+		return "", false
+	}
+
+	return cachedFile, true
+}
+
+// Evict removes the file cached under the passed name.
+func (m *MutexDownloadService) Evict(filename string) error {
+	m.resultListenersMutex.Lock()
+	defer m.resultListenersMutex.Unlock()
+
+	if err := os.Remove(m.cachePathOf(filename)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to evict cached file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
 // CleanLocalCache cleans the local cache folder.
 func (m *MutexDownloadService) CleanLocalCache(fileAge time.Duration) error {
 	m.resultListenersMutex.Lock()
@@ -142,8 +213,33 @@ func (m *MutexDownloadService) CleanLocalCache(fileAge time.Duration) error {
 	return nil
 }
 
+// Stats computes the current statistics of the download service, including the size of the local cache folder.
+func (m *MutexDownloadService) Stats() (DownloadServiceStats, error) {
+	stats := DownloadServiceStats{
+		CacheHits:          m.cacheHits.Load(),
+		CoalescedDownloads: m.coalescedDownloads.Load(),
+		CacheMisses:        m.cacheMisses.Load(),
+	}
+
+	dirContent, err := os.ReadDir(m.cacheDirectory)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read cache dir content: %w", err)
+	}
+
+	for _, entry := range dirContent {
+		info, err := entry.Info()
+		if err != nil {
+			continue // The file was removed concurrently.
+		}
+
+		stats.CacheSizeBytes += info.Size()
+	}
+
+	return stats, nil
+}
+
 func (m *MutexDownloadService) downloadURLToFile(ctx context.Context, url string, filename string) (string, error) {
-	downloadTargetPath := path.Join(m.cacheDirectory, path.Base(filename))
+	downloadTargetPath := m.cachePathOf(filename)
 
 	if err := DownloadURLTo(ctx, m.httpClient, url, downloadTargetPath); err != nil {
 		return "", fmt.Errorf("failed to download: %w", err)
@@ -151,3 +247,8 @@ func (m *MutexDownloadService) downloadURLToFile(ctx context.Context, url string
 
 	return downloadTargetPath, nil
 }
+
+// cachePathOf computes the path of the file cached under the passed name.
+func (m *MutexDownloadService) cachePathOf(filename string) string {
+	return path.Join(m.cacheDirectory, path.Base(filename))
+}
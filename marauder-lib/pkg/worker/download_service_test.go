@@ -110,6 +110,12 @@ var _ = Describe("DownloadService", Label("functiontest"), func() {
 				}
 
 				Expect(counter.Load()).To(BeEquivalentTo(1)) // should all be cached
+
+				stats, err := downloadService.Stats()
+				Expect(err).To(Not(HaveOccurred()))
+				Expect(stats.CacheMisses).To(BeEquivalentTo(1))
+				Expect(stats.CoalescedDownloads).To(BeEquivalentTo(parallelCount - 1))
+				Expect(stats.CacheSizeBytes).To(BeEquivalentTo(len("good")))
 			})
 		})
 
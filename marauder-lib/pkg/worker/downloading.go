@@ -9,18 +9,21 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
 
 // DownloadURLTo downloads the contents at the specific url to the passed path.
 // The folder containing the path has to exist.
+// The download is written to a temporary file next to the path that is renamed to it once complete, files already
+// opened at the path are never truncated by a later download to it.
 func DownloadURLTo(ctx context.Context, httpClient *http.Client, url string, path string) error {
 	downloadReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, &bytes.Buffer{})
 	if err != nil {
 		return fmt.Errorf("failed to create download request: %w", err)
 	}
 
-	downloadResponse, err := httpClient.Do(downloadReq)
+	downloadResponse, err := tracing.DoHTTPRequest(httpClient, downloadReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute download request: %w", err)
 	}
@@ -31,16 +34,25 @@ func DownloadURLTo(ctx context.Context, httpClient *http.Client, url string, pat
 		return fmt.Errorf("failed to download %s, status code %d: %w", url, downloadResponse.StatusCode, utils.ErrBadStatusCode)
 	}
 
-	downloadTarget, err := os.Create(filepath.Clean(path))
+	downloadTarget, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.part")
 	if err != nil {
 		return fmt.Errorf("failed to open output file: %w", err)
 	}
 
-	defer func() { _ = downloadTarget.Close() }()
+	defer func() { _ = os.Remove(downloadTarget.Name()) }() // Remove the partial download if it was not renamed.
 
 	if _, err := io.Copy(downloadTarget, downloadResponse.Body); err != nil {
+		_ = downloadTarget.Close()
 		return fmt.Errorf("failed to copy download response to file system: %w", err)
 	}
 
+	if err := downloadTarget.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	if err := os.Rename(downloadTarget.Name(), filepath.Clean(path)); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+
 	return nil
 }
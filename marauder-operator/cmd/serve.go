@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -8,9 +9,11 @@ import (
 	"path/filepath"
 
 	"github.com/gonvenience/bunt"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-operator/internal/rest"
 	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -26,19 +29,31 @@ func defaultConfiguration() rest.ServerConfiguration {
 			Password:              "",
 			AutoRemoveContainers:  true,
 			ContainerMemoryBuffer: 512, // in MB
+			StartTimeout:          0,   // do not await servers by default
 		},
 		Controller: rest.Controller{
 			Endpoint:    "http://localhost:8080/v1",
 			WorkerCount: 5,
 		},
 		Disk: rest.Disk{
-			DownloadPath: "/var/local/marauder/operator/cache/downloads",
+			DownloadPath:        "/var/local/marauder/operator/cache/downloads",
+			UpdateJournalPath:   "/var/local/marauder/operator/journal",
+			DeploymentStatePath: "/var/local/marauder/operator/deployments",
 			Paths: manager.DiskPathMapping{
 				"*": manager.EnvironmentDiskConfig{
 					ServerDataPathTemplate: "/var/local/marauder/operator/servers/{{.Environment}}/{{.Name}}",
 				},
 			},
 		},
+		Artefacts: rest.Artefacts{
+			TrustedKeysFile: "/var/local/marauder/operator/authorized_keys",
+		},
+		Backup: manager.BackupConfiguration{
+			Directory: "/var/local/marauder/operator/backups",
+			Retention: manager.BackupRetention{
+				KeepLast: 10,
+			},
+		},
 	}
 }
 
@@ -67,11 +82,23 @@ func ServeCommand() *cobra.Command {
 			}
 		}
 
+		if err := tracing.Setup(cmd.Context(), "marauder-operator", version, configuration.Tracing); err != nil {
+			return fmt.Errorf("failed to setup tracing: %w", err)
+		}
+
+		defer func() { _ = tracing.Shutdown(context.Background()) }()
+
 		dependencies, err := rest.CreateServerDependencies(version, configuration)
 		if err != nil {
 			return fmt.Errorf("failed to create server dependencies: %w", err)
 		}
 
+		// Interrupted updates are replayed before serving. Journals that fail to replay are retried once the affected
+		// server is next started, updated or checked for drift.
+		if err := dependencies.ServerManager.RecoverUpdateJournals(cmd.Context()); err != nil {
+			logrus.Error("failed to recover interrupted updates, retrying once the affected servers are used: ", err)
+		}
+
 		if err := rest.StartMarauderOperatorServer(configuration, dependencies); err != nil {
 			return fmt.Errorf("failed to serve rest server: %w", err)
 		}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -10,22 +11,32 @@ import (
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/middleware"
 	"github.com/knockturnmc/marauder/marauder-operator/internal/rest/v1/endpoints"
 	"github.com/sirupsen/logrus"
+	"github.com/ztrue/shutdown"
 )
 
+// signingKeyReloadInterval is the interval in which the trusted keys file of artefact signers is checked for changes.
+const signingKeyReloadInterval = 10 * time.Second
+
 // StartMarauderOperatorServer starts the marauder operator server instance.
 func StartMarauderOperatorServer(configuration ServerConfiguration, dependencies ServerDependencies) error {
 	server := gin.New()
+	server.ContextWithFallback = true // handlers pass the gin context on, which should yield the traced request context.
 	if err := server.SetTrustedProxies(nil); err != nil {
 		return fmt.Errorf("failed to set server trusted proxies: %w", err)
 	}
 
+	startSigningKeyWatcher(dependencies)
+
 	logrus.Debug("registering middleware on gin server")
 	server.Use(gin.LoggerWithFormatter(middleware.RequestLoggerFormatter()))
 	server.Use(gin.Recovery())
 	server.Use(cors.Default())
+	server.Use(middleware.Tracing())
 	server.Use(middleware.ErrorHandler())
 
 	logrus.Debug("registering routs on gin server")
+	server.GET("/metrics", gin.WrapH(dependencies.Metrics.Handler()))
+
 	group := server.Group("/v1")
 	group.GET("/version", endpoints.VersionGet(dependencies.Version))
 	group.POST("/cron/cache/clear", endpoints.CronCleanCache(dependencies.DownloadingService))
@@ -34,6 +45,53 @@ func StartMarauderOperatorServer(configuration ServerConfiguration, dependencies
 		configuration.Identifier,
 		dependencies.ControllerClient,
 		dependencies.ServerManager,
+		dependencies.Metrics,
+		configuration.Backup.BeforeUpdateWithRestart,
+	))
+
+	group.GET("/server/:uuid/status", endpoints.ServerStatusGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+
+	group.GET("/server/:uuid/logs", endpoints.ServerLogsGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+
+	group.GET("/server/:uuid/attach", endpoints.ServerAttachGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+
+	group.GET("/server/:uuid/plan", endpoints.ServerUpdatePlanGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+	group.GET("/server/:uuid/drift", endpoints.ServerDriftGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+
+	group.GET("/server/:uuid/backups", endpoints.ServerBackupsGet(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+	group.POST("/server/:uuid/backups", endpoints.ServerBackupsPost(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
+	))
+	group.POST("/server/:uuid/backups/:backup/restore", endpoints.ServerBackupRestorePost(
+		configuration.Identifier,
+		dependencies.ControllerClient,
+		dependencies.ServerManager,
 	))
 
 	group.GET("/server/:uuid/management/players", endpoints.ServerManagementPlayers(
@@ -67,3 +125,11 @@ func StartMarauderOperatorServer(configuration ServerConfiguration, dependencies
 
 	return nil
 }
+
+// startSigningKeyWatcher starts hot-reloading the signing key registry passed in the server dependencies.
+func startSigningKeyWatcher(dependencies ServerDependencies) {
+	watcherContext, watcherCancel := context.WithCancel(context.Background())
+	go dependencies.SigningKeys.Watch(watcherContext, signingKeyReloadInterval)
+
+	shutdown.Add(watcherCancel) // stop watching on shutdown
+}
@@ -2,8 +2,10 @@ package rest
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/docker/docker/api/types/registry"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
 )
@@ -21,13 +23,39 @@ type ServerConfiguration struct {
 
 	Disk Disk `yaml:"disk"`
 
+	Backup manager.BackupConfiguration `yaml:"backup"`
+
+	Artefacts Artefacts `yaml:"artefacts"`
+
 	TLS utils.TLSConfiguration `yaml:"tls"`
+
+	Tracing tracing.Configuration `yaml:"tracing"`
 }
 
 // Disk contains configuration values for the disk setup of controller.
 type Disk struct {
 	DownloadPath string                  `yaml:"downloadPath"`
 	Paths        manager.DiskPathMapping `yaml:"paths"`
+
+	// UpdateJournalPath is the folder the previous state of all files touched by a server update is staged in, allowing
+	// the update to be rolled back as a whole.
+	UpdateJournalPath string `yaml:"updateJournalPath"`
+
+	// DeploymentStatePath is the folder the operator keeps the state of the artefacts deployed onto its servers in.
+	DeploymentStatePath string `yaml:"deploymentStatePath"`
+}
+
+// The Artefacts struct holds the configuration values for the verification of artefacts downloaded from the controller.
+type Artefacts struct {
+	// TrustedKeysFile is the ssh-like authorized keys file holding the keys artefacts must be signed by.
+	// It is reloaded whenever it changes.
+	TrustedKeysFile string `yaml:"trustedKeysFile"`
+
+	// AllowUnsigned allows artefacts uploaded before the controller recorded signatures to be deployed.
+	AllowUnsigned bool `yaml:"allowUnsigned"`
+
+	// ExpiredKeyGracePeriod is the duration after their expiry trusted keys are still accepted for.
+	ExpiredKeyGracePeriod time.Duration `yaml:"expiredKeyGracePeriod"`
 }
 
 // The Controller struct holds the configuration values for the controller client used by the operator.
@@ -42,6 +70,10 @@ type Docker struct {
 	Password              string `yaml:"password"`
 	AutoRemoveContainers  bool   `yaml:"autoRemoveContainers"`
 	ContainerMemoryBuffer int64  `yaml:"containerMemoryBuffer"`
+
+	// StartTimeout defines how long a server start waits for the server to report itself as running.
+	// A zero value disables waiting for the server after its container was started.
+	StartTimeout time.Duration `yaml:"startTimeout"`
 }
 
 // ToBasicAuth converts the docker config into the encoded auth string.
@@ -9,9 +9,12 @@ import (
 	dockerClient "github.com/docker/docker/client"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/worker"
 	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/metrics"
 	_ "github.com/lib/pq" // postgres driver
 	"github.com/sirupsen/logrus"
 )
@@ -27,11 +30,17 @@ type ServerDependencies struct {
 	// The used downloading service, cleanable by request.
 	DownloadingService worker.DownloadService
 
+	// SigningKeys holds the keys downloaded artefacts must be signed by.
+	SigningKeys *keyauth.Registry
+
 	// The ServerManager is responsible for managing the docker instances on the server.
 	ServerManager manager.Manager
 
 	// TLSConfig provides the tsl configuration for the gin engine.
 	TLSConfig *tls.Config
+
+	// Metrics holds the prometheus metrics exposed by the operator.
+	Metrics *metrics.Metrics
 }
 
 // CreateServerDependencies creates the server configuration for the server based on the configuration.
@@ -47,6 +56,16 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 		return ServerDependencies{}, fmt.Errorf("failed to create download path for marauder operator: %w", err)
 	}
 
+	logrus.Debug("creating update journal folder on disk")
+	if err := os.MkdirAll(configuration.Disk.UpdateJournalPath, 0o700); err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to create update journal path for marauder operator: %w", err)
+	}
+
+	logrus.Debug("creating deployment state folder on disk")
+	if err := os.MkdirAll(configuration.Disk.DeploymentStatePath, 0o700); err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to create deployment state path for marauder operator: %w", err)
+	}
+
 	logrus.Debug("creating docker client")
 	dockerClientInstance, err := dockerClient.NewClientWithOpts(dockerClient.FromEnv, dockerClient.WithAPIVersionNegotiation())
 	if err != nil {
@@ -77,6 +96,26 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 
 	downloadService := worker.NewMutexDownloadService(controllerHTTPClient, dispatcher, configuration.Disk.DownloadPath)
 
+	logrus.Debug("registering metrics")
+	operatorMetrics, err := metrics.NewMetrics()
+	if err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to create metrics: %w", err)
+	}
+
+	if err := operatorMetrics.RegisterDownloadService(downloadService); err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to register download service metrics: %w", err)
+	}
+
+	if err := operatorMetrics.Register(manager.NewContainerStatsCollector(dockerClientInstance)); err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to register container stats metrics: %w", err)
+	}
+
+	logrus.Debug("loading trusted keys of artefact signers")
+	signingKeys, err := keyauth.NewRegistry(configuration.Artefacts.TrustedKeysFile)
+	if err != nil {
+		return ServerDependencies{}, fmt.Errorf("failed to load trusted artefact signing keys: %w", err)
+	}
+
 	controllerClient := &controller.DownloadingHTTPClient{
 		HTTPClient: controller.HTTPClient{
 			Client:        controllerHTTPClient,
@@ -90,14 +129,25 @@ func CreateServerDependencies(version string, configuration ServerConfiguration)
 		ControllerClient:   controllerClient,
 		TLSConfig:          tlsConfiguration,
 		DownloadingService: downloadService,
+		SigningKeys:        signingKeys,
+		Metrics:            operatorMetrics,
 		ServerManager: &manager.DockerBasedManager{
-			ControllerClient:      controllerClient,
-			DockerClient:          dockerClientInstance,
-			DockerEncodedAuth:     dockerEncodedBasicAuth,
-			AutoRemoveContainers:  configuration.Docker.AutoRemoveContainers,
-			ContainerMemoryBuffer: configuration.Docker.ContainerMemoryBuffer,
-			DiskPathMapping:       configuration.Disk.Paths,
-			FileEqualityRegistry:  fileeq.DefaultFileEqualityRegistry(),
+			ControllerClient:             controllerClient,
+			DockerClient:                 dockerClientInstance,
+			DockerEncodedAuth:            dockerEncodedBasicAuth,
+			AutoRemoveContainers:         configuration.Docker.AutoRemoveContainers,
+			ContainerMemoryBuffer:        configuration.Docker.ContainerMemoryBuffer,
+			StartTimeout:                 configuration.Docker.StartTimeout,
+			DiskPathMapping:              configuration.Disk.Paths,
+			UpdateJournalPath:            configuration.Disk.UpdateJournalPath,
+			DeploymentStatePath:          configuration.Disk.DeploymentStatePath,
+			FileEqualityRegistry:         fileeq.DefaultFileEqualityRegistry(),
+			FileMergeRegistry:            filemerge.DefaultFileMergeRegistry(),
+			SigningKeys:                  signingKeys,
+			AllowUnsignedArtefacts:       configuration.Artefacts.AllowUnsigned,
+			ExpiredSigningKeyGracePeriod: configuration.Artefacts.ExpiredKeyGracePeriod,
+			Backups:                      configuration.Backup,
+			Metrics:                      operatorMetrics,
 		},
 	}, nil
 }
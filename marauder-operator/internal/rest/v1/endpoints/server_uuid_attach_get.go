@@ -0,0 +1,159 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	"github.com/sirupsen/logrus"
+)
+
+// consoleUpgrader upgrades console attach requests to websockets.
+var consoleUpgrader = websocket.Upgrader{ReadBufferSize: 4096, WriteBufferSize: 4096}
+
+// ServerAttachGet creates the endpoint that attaches a websocket to the console of a server managed by this operator.
+func ServerAttachGet(
+	operatorIdentifier string,
+	controllerClient controller.Client,
+	serverManager manager.Manager,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUIDAsString := context.Param("uuid")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		server, err := controllerClient.FetchServer(context, serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to fetch server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		if server.OperatorRef.Identifier != operatorIdentifier {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("server %s is not managed by operator %s", serverUUID.String(), operatorIdentifier),
+			))
+
+			return
+		}
+
+		console, err := serverManager.Attach(context, server)
+		if err != nil {
+			if errors.Is(err, manager.ErrServerContainerNotFound) {
+				_ = context.Error(response.RestErrorFrom(http.StatusNotFound, "server "+serverUUIDAsString+" has no container", err))
+				return
+			}
+
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to attach to server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		defer func() { _ = console.Close() }()
+
+		// The upgrader responds to the request itself on failure.
+		conn, err := consoleUpgrader.Upgrade(context.Writer, context.Request, nil)
+		if err != nil {
+			logrus.Warn("failed to upgrade console attach request of server ", serverUUIDAsString, " ", err)
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		logrus.Info("attached console of server ", server.Environment, "/", server.Name)
+
+		if err := pumpConsole(conn, console, func(width uint, height uint) error {
+			return serverManager.ResizeConsole(context, server, width, height)
+		}); err != nil {
+			logrus.Warn("console of server ", serverUUIDAsString, " failed ", err)
+		}
+
+		logrus.Info("detached console of server ", server.Environment, "/", server.Name)
+	}
+}
+
+// pumpConsole pumps the console output into the websocket and binary websocket messages into the console until either closes.
+// Text messages on the websocket are parsed as networkmodel.ConsoleControlMessage.
+func pumpConsole(conn *websocket.Conn, console io.ReadWriteCloser, resize func(width uint, height uint) error) error {
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+
+		buffer := make([]byte, 4096)
+		for {
+			readBytes, err := console.Read(buffer)
+			if readBytes > 0 {
+				if err := conn.WriteMessage(websocket.BinaryMessage, buffer[:readBytes]); err != nil {
+					return
+				}
+			}
+
+			if err != nil {
+				// The container stopped, close the websocket which in turn ends the input loop.
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "console closed"))
+				_ = conn.Close()
+
+				return
+			}
+		}
+	}()
+
+	// Closing the console unblocks the output pump once the input loop ended.
+	defer func() {
+		_ = console.Close()
+		<-outputDone
+	}()
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+
+			select {
+			case <-outputDone:
+				return nil // the console closed the websocket.
+			default:
+				return fmt.Errorf("failed to read console input: %w", err)
+			}
+		}
+
+		switch messageType {
+		case websocket.BinaryMessage:
+			if _, err := console.Write(message); err != nil {
+				return fmt.Errorf("failed to write console input: %w", err)
+			}
+		case websocket.TextMessage:
+			var controlMessage networkmodel.ConsoleControlMessage
+			if err := json.Unmarshal(message, &controlMessage); err != nil {
+				return fmt.Errorf("failed to parse console control message: %w", err)
+			}
+
+			if controlMessage.Type == networkmodel.ConsoleControlTypeResize {
+				if err := resize(controlMessage.Width, controlMessage.Height); err != nil {
+					logrus.Warn("failed to resize console ", err)
+				}
+			}
+		}
+	}
+}
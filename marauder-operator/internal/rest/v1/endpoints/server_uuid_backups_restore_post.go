@@ -0,0 +1,66 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+)
+
+// ServerBackupRestorePost restores a backup of the server into its data folder.
+func ServerBackupRestorePost(
+	operatorIdentifier string,
+	controllerClient controller.Client,
+	serverManager manager.Manager,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUIDAsString := context.Param("uuid")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		server, err := controllerClient.FetchServer(context, serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to fetch server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		if server.OperatorRef.Identifier != operatorIdentifier {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("server %s is not managed by operator %s", serverUUID.String(), operatorIdentifier),
+			))
+
+			return
+		}
+
+		backupName := context.Param("backup")
+		if err := serverManager.RestoreBackup(context, server, backupName); err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				manager.ErrBackupsNotConfigured: {
+					ResponseCode: http.StatusBadRequest, Description: "backups are not configured on operator " + operatorIdentifier,
+				},
+				manager.ErrBackupNotFound: {
+					ResponseCode: http.StatusNotFound, Description: fmt.Sprintf("could not find backup %s of server %s", backupName, server.Name),
+				},
+				manager.ErrServerRunning: {
+					ResponseCode: http.StatusBadRequest, Description: fmt.Sprintf("the server %s is running", server.Name),
+				},
+			}, fmt.Errorf("failed to restore backup %s of server %s: %w", backupName, serverUUIDAsString, err)))
+
+			return
+		}
+
+		context.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,60 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+)
+
+// ServerDriftGet verifies all files deployed on the server against the artefacts in its IS state and yields back the drift.
+func ServerDriftGet(
+	operatorIdentifier string,
+	controllerClient controller.Client,
+	serverManager manager.Manager,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUIDAsString := context.Param("uuid")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		server, err := controllerClient.FetchServer(context, serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to fetch server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		if server.OperatorRef.Identifier != operatorIdentifier {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("server %s is not managed by operator %s", serverUUID.String(), operatorIdentifier),
+			))
+
+			return
+		}
+
+		drift, err := serverManager.Drift(context, server)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				manager.ErrUpdateJournalPending: {
+					ResponseCode: http.StatusConflict, Description: "server " + serverUUIDAsString + " has a pending update journal",
+				},
+			}, fmt.Errorf("failed to compute drift of server %s: %w", serverUUIDAsString, err)))
+
+			return
+		}
+
+		context.JSONP(http.StatusOK, drift)
+	}
+}
@@ -4,19 +4,24 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	libMetrics "github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
 	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/metrics"
 )
 
 func ServerLifecycleActionPost(
 	operatorIdentifier string,
 	controllerClient controller.Client,
 	serverManager manager.Manager,
+	operatorMetrics *metrics.Metrics,
+	backupBeforeUpdateWithRestart bool,
 ) gin.HandlerFunc {
 	return func(context *gin.Context) {
 		serverUUIDAsString := context.Param("uuid")
@@ -51,7 +56,17 @@ func ServerLifecycleActionPost(
 			return
 		}
 
-		if handleLifecycleAction(context, action, serverManager, server) {
+		actionStart := time.Now()
+		succeeded := handleLifecycleAction(context, action, serverManager, server, backupBeforeUpdateWithRestart)
+
+		outcome := libMetrics.OutcomeFailure
+		if succeeded {
+			outcome = libMetrics.OutcomeSuccess
+		}
+
+		operatorMetrics.ObserveLifecycleAction(server, action, outcome, time.Since(actionStart))
+
+		if succeeded {
 			context.Status(http.StatusOK)
 		}
 	}
@@ -63,6 +78,7 @@ func handleLifecycleAction(
 	action networkmodel.LifecycleAction,
 	serverManager manager.Manager,
 	server networkmodel.ServerModel,
+	backupBeforeUpdateWithRestart bool,
 ) bool {
 	switch action {
 	case networkmodel.Start:
@@ -76,6 +92,7 @@ func handleLifecycleAction(
 		return updateServerDeployments(context, serverManager, server, false, action == networkmodel.UpdateWithoutRestart)
 	case networkmodel.UpdateWithRestart, networkmodel.ForceUpdateWithRestart:
 		return handleLifecycleActionStop(context, serverManager, server) &&
+			(!backupBeforeUpdateWithRestart || backupServerBeforeUpdate(context, serverManager, server)) &&
 			updateServerDeployments(context, serverManager, server, true, action == networkmodel.UpdateWithRestart) &&
 			handleLifecycleActionStart(context, serverManager, server)
 	default:
@@ -98,6 +115,9 @@ func updateServerDeployments(
 			manager.ErrServerRunning: {
 				ResponseCode: http.StatusBadRequest, Description: fmt.Sprintf("the server %s is running", server.Name),
 			},
+			manager.ErrUpdateJournalPending: {
+				ResponseCode: http.StatusConflict, Description: fmt.Sprintf("the server %s has a pending update journal", server.Name),
+			},
 		}, fmt.Errorf("failed to update deployments: %w", err)))
 
 		return false
@@ -105,10 +125,25 @@ func updateServerDeployments(
 	return true
 }
 
+// backupServerBeforeUpdate backs up the stopped server before its deployments are updated.
+func backupServerBeforeUpdate(context *gin.Context, serverManager manager.Manager, server networkmodel.ServerModel) bool {
+	if _, err := serverManager.Backup(context, server); err != nil {
+		_ = context.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to backup server before update: %w", err)))
+		return false
+	}
+
+	return true
+}
+
 // handleLifecycleActionStart handles the start lifecycle action.
 func handleLifecycleActionStart(ctx *gin.Context, serverManager manager.Manager, server networkmodel.ServerModel) bool {
 	if err := serverManager.Start(ctx, server); err != nil {
-		_ = ctx.Error(response.RestErrorFromErr(http.StatusInternalServerError, fmt.Errorf("failed to start server: %w", err)))
+		_ = ctx.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+			manager.ErrUpdateJournalPending: {
+				ResponseCode: http.StatusConflict, Description: fmt.Sprintf("the server %s has a pending update journal", server.Name),
+			},
+		}, fmt.Errorf("failed to start server: %w", err)))
+
 		return false
 	}
 
@@ -0,0 +1,87 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	"github.com/sirupsen/logrus"
+)
+
+// ServerLogsGet creates the endpoint that streams the container logs of a server managed by this operator.
+func ServerLogsGet(
+	operatorIdentifier string,
+	controllerClient controller.Client,
+	serverManager manager.Manager,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUIDAsString := context.Param("uuid")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		follow, err := strconv.ParseBool(context.DefaultQuery("follow", "false"))
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse follow query parameter"))
+			return
+		}
+
+		server, err := controllerClient.FetchServer(context, serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to fetch server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		if server.OperatorRef.Identifier != operatorIdentifier {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("server %s is not managed by operator %s", serverUUID.String(), operatorIdentifier),
+			))
+
+			return
+		}
+
+		logs, err := serverManager.Logs(context, server, networkmodel.ServerLogOptions{
+			Since:  context.Query("since"),
+			Tail:   context.Query("tail"),
+			Follow: follow,
+		})
+		if err != nil {
+			if errors.Is(err, manager.ErrServerContainerNotFound) {
+				_ = context.Error(response.RestErrorFrom(http.StatusNotFound, "server "+serverUUIDAsString+" has no container", err))
+				return
+			}
+
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to open logs of server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		defer func() { _ = logs.Close() }()
+
+		context.Header("Content-Type", "text/plain; charset=utf-8")
+		context.Status(http.StatusOK)
+
+		// The response is already committed at this point, failures can only be logged.
+		if _, err := utils.CopyAndFlush(context.Writer, logs); err != nil && context.Request.Context().Err() == nil {
+			logrus.Warn("failed to stream logs of server ", serverUUIDAsString, " ", err)
+		}
+	}
+}
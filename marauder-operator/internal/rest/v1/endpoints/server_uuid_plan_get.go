@@ -0,0 +1,76 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/rest/response"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+)
+
+// ServerUpdatePlanGet computes the changes the update lifecycle action passed in the action query parameter would
+// perform on the server, without performing them. The action defaults to update+restart.
+func ServerUpdatePlanGet(
+	operatorIdentifier string,
+	controllerClient controller.Client,
+	serverManager manager.Manager,
+) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		serverUUIDAsString := context.Param("uuid")
+		serverUUID, err := uuid.Parse(serverUUIDAsString)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, "could not parse uuid in url params"))
+			return
+		}
+
+		action := networkmodel.LifecycleAction(strings.ToLower(context.DefaultQuery("action", string(networkmodel.UpdateWithRestart))))
+		var restarting, failOnUnexpectedOldFilesOnDisk bool
+		switch action {
+		case networkmodel.UpdateWithoutRestart, networkmodel.ForceUpdateWithoutRestart:
+			restarting, failOnUnexpectedOldFilesOnDisk = false, action == networkmodel.UpdateWithoutRestart
+		case networkmodel.UpdateWithRestart, networkmodel.ForceUpdateWithRestart:
+			restarting, failOnUnexpectedOldFilesOnDisk = true, action == networkmodel.UpdateWithRestart
+		default:
+			_ = context.Error(response.RestErrorFromDescription(http.StatusBadRequest, fmt.Sprintf("cannot plan action %s", action)))
+			return
+		}
+
+		server, err := controllerClient.FetchServer(context, serverUUID)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromErr(
+				http.StatusInternalServerError,
+				fmt.Errorf("failed to fetch server %s: %w", serverUUIDAsString, err),
+			))
+
+			return
+		}
+
+		if server.OperatorRef.Identifier != operatorIdentifier {
+			_ = context.Error(response.RestErrorFromDescription(
+				http.StatusBadRequest,
+				fmt.Sprintf("server %s is not managed by operator %s", serverUUID.String(), operatorIdentifier),
+			))
+
+			return
+		}
+
+		plan, err := serverManager.PlanUpdateDeployments(context, server, restarting, failOnUnexpectedOldFilesOnDisk)
+		if err != nil {
+			_ = context.Error(response.RestErrorFromKnownErr(map[error]response.KnownErr{
+				manager.ErrUpdateJournalPending: {
+					ResponseCode: http.StatusConflict, Description: fmt.Sprintf("the server %s has a pending update journal", server.Name),
+				},
+			}, fmt.Errorf("failed to plan update of server %s: %w", serverUUIDAsString, err)))
+
+			return
+		}
+
+		plan.Action = action
+		context.JSONP(http.StatusOK, plan)
+	}
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// RestoreBackupArchive exposes restoreBackupArchive to the tests, as RestoreBackup requires a docker daemon.
+func RestoreBackupArchive(archivePath string, serverFolder string, configuration BackupConfiguration) error {
+	filter, err := newBackupFilter(configuration)
+	if err != nil {
+		return err
+	}
+
+	return restoreBackupArchive(archivePath, serverFolder, filter, nil)
+}
+
+// UpdateDeploymentsOfStoppedServer exposes updateDeployments to the tests, skipping the container lookup of
+// UpdateDeployments that requires a docker daemon.
+func (d DockerBasedManager) UpdateDeploymentsOfStoppedServer(
+	ctx context.Context,
+	server networkmodel.ServerModel,
+	failOnUnexpectedOldFilesOnDisk bool,
+) error {
+	return d.updateDeployments(ctx, server, true, failOnUnexpectedOldFilesOnDisk, false)
+}
+
+// PlanUpdateDeploymentsOfStoppedServer exposes planUpdateDeployments to the tests, skipping the container lookup of
+// PlanUpdateDeployments that requires a docker daemon.
+func (d DockerBasedManager) PlanUpdateDeploymentsOfStoppedServer(
+	ctx context.Context,
+	server networkmodel.ServerModel,
+	requiresRestart bool,
+	failOnUnexpectedOldFilesOnDisk bool,
+) (networkmodel.UpdatePlan, error) {
+	return d.planUpdateDeployments(ctx, server, requiresRestart, failOnUnexpectedOldFilesOnDisk, false)
+}
+
+// LockUpdateJournal exposes lockUpdateJournal to the tests, simulating an update in progress holding the journal lock.
+func (d DockerBasedManager) LockUpdateJournal(serverUUID uuid.UUID) (func(), error) {
+	lock, locked, err := d.lockUpdateJournal(serverUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, ErrUpdateJournalPending
+	}
+
+	return func() { _ = lock.Close() }, nil
+}
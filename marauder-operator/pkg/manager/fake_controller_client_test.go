@@ -0,0 +1,415 @@
+package manager_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// errFakeController is returned by the fake controller client for injected failures.
+	errFakeController = errors.New("fake controller failure")
+
+	// errSimulatedCrash is panicked with by the fake controller client to simulate a crash of the operator.
+	errSimulatedCrash = errors.New("simulated crash")
+)
+
+// The fakeArtefact is an artefact served by the fake controller client.
+type fakeArtefact struct {
+	path     string
+	manifest filemodel.Manifest
+}
+
+// The fakeControllerClient implements the parts of the downloading controller client used to update deployments.
+// All other methods are not implemented and panic if called.
+type fakeControllerClient struct {
+	controller.DownloadingClient
+
+	folder      string
+	missmatches []networkmodel.ArtefactVersionMissmatch
+	artefacts   map[uuid.UUID]fakeArtefact
+
+	// isStates holds the IS state of each artefact identifier as updated through the client.
+	isStates map[string]uuid.UUID
+
+	// variables holds the variables of the server files deployed as templates are rendered with.
+	variables map[string]string
+
+	// failDownloadOf and failUpdateStateOf inject failures for the respective artefact.
+	failDownloadOf    map[uuid.UUID]bool
+	failUpdateStateOf map[string]bool
+
+	// crashOnDownloadOf and crashOnUpdateStateOf simulate a crash of the operator for the respective artefact.
+	crashOnDownloadOf    map[uuid.UUID]bool
+	crashOnUpdateStateOf map[string]bool
+
+	// unreachable fails all state updates, simulating a controller that cannot be reached.
+	unreachable bool
+
+	// signer signs the artefacts whose integrity is fetched through the client, leaving them unsigned if nil.
+	signer ssh.Signer
+
+	// corruptDownloadsOf holds the amount of downloads of the respective artefact that yield a corrupted tarball.
+	corruptDownloadsOf map[uuid.UUID]int
+
+	// downloads counts the downloads of each artefact.
+	downloads map[uuid.UUID]int
+
+	// evictions counts the evictions of each artefact from the download cache.
+	evictions map[uuid.UUID]int
+
+	// uploadDate overwrites the upload date the client reports for all artefacts, simulating a controller backdating them.
+	uploadDate *time.Time
+
+	// buildInformation is included in the manifest of all artefacts added to the client.
+	buildInformation *filemodel.BuildInformation
+}
+
+func newFakeControllerClient(folder string) *fakeControllerClient {
+	return &fakeControllerClient{
+		folder:            folder,
+		artefacts:         make(map[uuid.UUID]fakeArtefact),
+		isStates:          make(map[string]uuid.UUID),
+		variables:         make(map[string]string),
+		failDownloadOf:    make(map[uuid.UUID]bool),
+		failUpdateStateOf: make(map[string]bool),
+
+		crashOnDownloadOf:    make(map[uuid.UUID]bool),
+		crashOnUpdateStateOf: make(map[string]bool),
+
+		corruptDownloadsOf: make(map[uuid.UUID]int),
+		downloads:          make(map[uuid.UUID]int),
+		evictions:          make(map[uuid.UUID]int),
+	}
+}
+
+// newTestServer creates the dev/lobby server and its server folder in the servers folder of the passed root folder.
+func newTestServer(root string) (networkmodel.ServerModel, string) {
+	GinkgoHelper()
+
+	server := networkmodel.ServerModel{UUID: uuid.New(), Environment: "dev", Name: "lobby"}
+	serverFolder := filepath.Join(root, "servers", server.Environment, server.Name)
+	Expect(os.MkdirAll(serverFolder, 0o700)).To(Succeed())
+
+	return server, serverFolder
+}
+
+// newTestManager creates a manager deploying servers into the servers folder of the passed root folder, staging its
+// update journals in the journal folder. Artefacts are served by the returned fake controller client from the
+// artefacts folder.
+func newTestManager(root string) (*DockerBasedManager, *fakeControllerClient) {
+	GinkgoHelper()
+
+	artefactFolder := filepath.Join(root, "artefacts")
+	Expect(os.MkdirAll(artefactFolder, 0o700)).To(Succeed())
+
+	controllerClient := newFakeControllerClient(artefactFolder)
+
+	return &DockerBasedManager{
+		ControllerClient: controllerClient,
+		DiskPathMapping: DiskPathMapping{
+			"*": EnvironmentDiskConfig{ServerDataPathTemplate: filepath.Join(root, "servers", "{{.Environment}}", "{{.Name}}")},
+		},
+		FileEqualityRegistry: fileeq.DefaultFileEqualityRegistry(),
+		FileMergeRegistry:    filemerge.DefaultFileMergeRegistry(),
+		UpdateJournalPath:    filepath.Join(root, "journal"),
+		DeploymentStatePath:  filepath.Join(root, "deployments"),
+	}, controllerClient
+}
+
+// installMissmatch creates the missmatch installing the passed artefact in version 1 under the passed identifier.
+func installMissmatch(identifier string, artefact uuid.UUID) networkmodel.ArtefactVersionMissmatch {
+	return networkmodel.ArtefactVersionMissmatch{ArtefactIdentifier: identifier, Missmatch: networkmodel.ArtefactMissmatch{
+		Install: &networkmodel.ArtefactVersionMissmatchInstall{
+			Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: artefact, Version: "1"},
+		},
+	}}
+}
+
+// simulateCrash runs the passed function, recovering from a crash simulated by the fake controller client.
+func simulateCrash(function func()) {
+	GinkgoHelper()
+
+	defer func() {
+		Expect(recover()).To(Equal(errSimulatedCrash))
+	}()
+
+	function()
+}
+
+// addArtefact writes an artefact tarball containing the passed files, mapped from their path in the server folder to
+// their content, and serves it under a new uuid.
+func (f *fakeControllerClient) addArtefact(identifier string, version string, files map[string]string) uuid.UUID {
+	GinkgoHelper()
+
+	return f.addArtefactWithMetadata(identifier, version, files, nil)
+}
+
+// addArtefactWithMetadata adds an artefact like addArtefact, recording the passed metadata of its files in the manifest.
+// Files with a symlink target in their metadata are written to the tarball as symbolic links.
+func (f *fakeControllerClient) addArtefactWithMetadata(
+	identifier string,
+	version string,
+	files map[string]string,
+	metadata map[string]filemodel.FileMetadata,
+) uuid.UUID {
+	GinkgoHelper()
+
+	artefactUUID := uuid.New()
+	artefactPath := filepath.Join(f.folder, artefactUUID.String()+".tar.gz")
+
+	artefactFile, err := os.Create(artefactPath)
+	Expect(err).To(Not(HaveOccurred()))
+
+	tarballWriter, err := utils.NewFriendlyTarballWriterGZ(artefactFile, gzip.DefaultCompression)
+	Expect(err).To(Not(HaveOccurred()))
+
+	matchedFiles := make(map[string]string)
+	matchedFileMetadata := make(map[string]filemodel.FileMetadata)
+	for pathInServer, content := range files {
+		pathInTarball := "files/" + pathInServer
+		matchedFiles[pathInTarball] = pathInServer
+
+		fileMetadata, found := metadata[pathInServer]
+		if !found {
+			Expect(tarballWriter.Write([]byte(content), tar.Header{Name: pathInTarball, Typeflag: tar.TypeReg, Mode: 0o640})).To(Succeed())
+			continue
+		}
+
+		matchedFileMetadata[pathInTarball] = fileMetadata
+		if fileMetadata.SymlinkTarget != nil {
+			Expect(tarballWriter.Write(nil, tar.Header{
+				Name:     pathInTarball,
+				Typeflag: tar.TypeSymlink,
+				Linkname: *fileMetadata.SymlinkTarget,
+				Mode:     0o777,
+			})).To(Succeed())
+
+			continue
+		}
+
+		Expect(tarballWriter.Write([]byte(content), tar.Header{Name: pathInTarball, Typeflag: tar.TypeReg, Mode: int64(fileMetadata.Mode)})).To(Succeed())
+	}
+
+	if len(matchedFileMetadata) == 0 {
+		matchedFileMetadata = nil
+	}
+
+	manifest := filemodel.Manifest{
+		Identifier: identifier,
+		Version:    version,
+		Files: filemodel.FileReferenceCollection{{
+			Target:              "/",
+			MatchedFiles:        matchedFiles,
+			MatchedFileMetadata: matchedFileMetadata,
+		}},
+		BuildInformation: f.buildInformation,
+	}
+
+	serialisedManifest, err := json.Marshal(manifest)
+	Expect(err).To(Not(HaveOccurred()))
+	Expect(tarballWriter.Write(serialisedManifest, tar.Header{Name: pkg.ManifestFileName, Typeflag: tar.TypeReg, Mode: 0o644})).To(Succeed())
+
+	Expect(tarballWriter.Close()).To(Succeed())
+	Expect(artefactFile.Close()).To(Succeed())
+
+	f.artefacts[artefactUUID] = fakeArtefact{path: artefactPath, manifest: manifest}
+
+	return artefactUUID
+}
+
+// addArtefactWithEntries adds an artefact holding the passed raw tarball entries, regular files with a placeholder content.
+// All entries are tracked by the manifest of the artefact, allowing to craft malicious artefacts.
+func (f *fakeControllerClient) addArtefactWithEntries(identifier string, version string, entries []tar.Header) uuid.UUID {
+	GinkgoHelper()
+
+	artefactUUID := uuid.New()
+	artefactPath := filepath.Join(f.folder, artefactUUID.String()+".tar.gz")
+
+	artefactFile, err := os.Create(artefactPath)
+	Expect(err).To(Not(HaveOccurred()))
+
+	tarballWriter, err := utils.NewFriendlyTarballWriterGZ(artefactFile, gzip.DefaultCompression)
+	Expect(err).To(Not(HaveOccurred()))
+
+	matchedFiles := make(map[string]string)
+	for _, entry := range entries {
+		matchedFiles[entry.Name] = entry.Name
+
+		var content []byte
+		if entry.Typeflag == tar.TypeReg {
+			content = []byte("malicious")
+		}
+
+		Expect(tarballWriter.Write(content, entry)).To(Succeed())
+	}
+
+	Expect(tarballWriter.Close()).To(Succeed())
+	Expect(artefactFile.Close()).To(Succeed())
+
+	f.artefacts[artefactUUID] = fakeArtefact{
+		path: artefactPath,
+		manifest: filemodel.Manifest{
+			Identifier: identifier,
+			Version:    version,
+			Files:      filemodel.FileReferenceCollection{{Target: "/", MatchedFiles: matchedFiles}},
+		},
+	}
+
+	return artefactUUID
+}
+
+func (f *fakeControllerClient) FetchMissmatchesFor(context.Context, uuid.UUID, bool) ([]networkmodel.ArtefactVersionMissmatch, error) {
+	return f.missmatches, nil
+}
+
+func (f *fakeControllerClient) FetchServerStateArtefacts(
+	_ context.Context,
+	_ uuid.UUID,
+	_ networkmodel.ServerStateType,
+) ([]networkmodel.ArtefactModel, error) {
+	result := make([]networkmodel.ArtefactModel, 0, len(f.isStates))
+	for _, artefactUUID := range f.isStates {
+		manifest := f.artefacts[artefactUUID].manifest
+		result = append(result, networkmodel.ArtefactModel{UUID: artefactUUID, Identifier: manifest.Identifier, Version: manifest.Version})
+	}
+
+	slices.SortFunc(result, func(a, b networkmodel.ArtefactModel) int { return strings.Compare(a.Identifier, b.Identifier) })
+
+	return result, nil
+}
+
+func (f *fakeControllerClient) FetchServerVariables(_ context.Context, server uuid.UUID) ([]networkmodel.ServerVariable, error) {
+	result := make([]networkmodel.ServerVariable, 0, len(f.variables))
+	for key, value := range f.variables {
+		result = append(result, networkmodel.ServerVariable{Server: server, Key: key, Value: value})
+	}
+
+	slices.SortFunc(result, func(a, b networkmodel.ServerVariable) int { return strings.Compare(a.Key, b.Key) })
+
+	return result, nil
+}
+
+func (f *fakeControllerClient) DownloadArtefact(_ context.Context, artefactUUID uuid.UUID) (string, error) {
+	if f.crashOnDownloadOf[artefactUUID] {
+		panic(errSimulatedCrash)
+	}
+
+	if f.failDownloadOf[artefactUUID] {
+		return "", errFakeController
+	}
+
+	f.downloads[artefactUUID]++
+
+	if f.corruptDownloadsOf[artefactUUID] > 0 {
+		f.corruptDownloadsOf[artefactUUID]--
+
+		content, err := os.ReadFile(f.artefacts[artefactUUID].path)
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		corruptedPath := filepath.Join(f.folder, artefactUUID.String()+".corrupted.tar.gz")
+		if err := os.WriteFile(corruptedPath, content[:len(content)/2], 0o600); err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		return corruptedPath, nil
+	}
+
+	return f.artefacts[artefactUUID].path, nil
+}
+
+func (f *fakeControllerClient) EvictArtefact(artefactUUID uuid.UUID) error {
+	f.evictions[artefactUUID]++
+
+	return nil
+}
+
+func (f *fakeControllerClient) FetchArtefactIntegrity(_ context.Context, artefactUUID uuid.UUID) (networkmodel.ArtefactIntegrity, error) {
+	artefactFile, err := os.Open(f.artefacts[artefactUUID].path)
+	if err != nil {
+		return networkmodel.ArtefactIntegrity{}, err //nolint:wrapcheck
+	}
+
+	defer func() { _ = artefactFile.Close() }()
+
+	integrity := networkmodel.ArtefactIntegrity{UploadDate: time.Now()}
+	if f.uploadDate != nil {
+		integrity.UploadDate = *f.uploadDate
+	}
+
+	if integrity.Hash, err = utils.ComputeSha256(artefactFile); err != nil {
+		return networkmodel.ArtefactIntegrity{}, err //nolint:wrapcheck
+	}
+
+	if f.signer == nil {
+		return integrity, nil
+	}
+
+	if _, err := artefactFile.Seek(0, io.SeekStart); err != nil {
+		return networkmodel.ArtefactIntegrity{}, err //nolint:wrapcheck
+	}
+
+	if integrity.Signature, err = keyauth.SignSSHSIG(f.signer, keyauth.ArtefactNamespace, artefactFile); err != nil {
+		return networkmodel.ArtefactIntegrity{}, err //nolint:wrapcheck
+	}
+
+	return integrity, nil
+}
+
+func (f *fakeControllerClient) FetchManifest(_ context.Context, artefactUUID uuid.UUID) (filemodel.Manifest, error) {
+	return f.artefacts[artefactUUID].manifest, nil
+}
+
+func (f *fakeControllerClient) UpdateState(
+	_ context.Context,
+	_ uuid.UUID,
+	_ networkmodel.ServerStateType,
+	request networkmodel.UpdateServerStateRequest,
+) error {
+	if f.crashOnUpdateStateOf[request.ArtefactIdentifier] {
+		delete(f.crashOnUpdateStateOf, request.ArtefactIdentifier) // crash once, the restarted operator reaches the controller.
+		panic(errSimulatedCrash)
+	}
+
+	if f.unreachable {
+		return errFakeController
+	}
+
+	if f.failUpdateStateOf[request.ArtefactIdentifier] {
+		delete(f.failUpdateStateOf, request.ArtefactIdentifier) // fail once, allowing the rollback to succeed.
+		return errFakeController
+	}
+
+	if request.ArtefactUUID == nil {
+		delete(f.isStates, request.ArtefactIdentifier)
+		return nil
+	}
+
+	f.isStates[request.ArtefactIdentifier] = *request.ArtefactUUID
+
+	return nil
+}
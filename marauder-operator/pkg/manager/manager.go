@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"google.golang.org/protobuf/proto"
@@ -12,8 +14,11 @@ import (
 	dockerClient "github.com/docker/docker/client"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/metrics"
 )
 
 // ErrNoMatchingDiskConfig is returned for a deployment that is not matched by any disk matcher.
@@ -26,6 +31,33 @@ type Manager interface {
 	// Start starts the server model. If the given server is running, this method is a NOOP.
 	Start(ctx context.Context, server networkmodel.ServerModel) error
 
+	// Status computes the live runtime status of the server, combining its container state and management socket status.
+	Status(ctx context.Context, server networkmodel.ServerModel) (networkmodel.ServerRuntimeStatus, error)
+
+	// Logs opens a stream of the container logs of the server as defined by the passed options.
+	// The caller is responsible for closing the returned stream.
+	Logs(ctx context.Context, server networkmodel.ServerModel, options networkmodel.ServerLogOptions) (io.ReadCloser, error)
+
+	// Attach attaches to the stdin and stdout of the servers container.
+	// The caller is responsible for closing the returned console.
+	Attach(ctx context.Context, server networkmodel.ServerModel) (io.ReadWriteCloser, error)
+
+	// ResizeConsole resizes the tty of the servers container.
+	ResizeConsole(ctx context.Context, server networkmodel.ServerModel, width uint, height uint) error
+
+	// Backup creates a backup of the servers data folder, toggling saving on the server off during the backup if it is running.
+	Backup(ctx context.Context, server networkmodel.ServerModel) (networkmodel.ServerBackup, error)
+
+	// ListBackups lists all backups of the server, sorted from newest to oldest.
+	ListBackups(ctx context.Context, server networkmodel.ServerModel) ([]networkmodel.ServerBackup, error)
+
+	// RestoreBackup restores the backup with the passed name into the servers data folder. The server must not be running.
+	RestoreBackup(ctx context.Context, server networkmodel.ServerModel, name string) error
+
+	// Drift verifies every file of every artefact in the IS state of the server against the server folder and yields back
+	// all files that were modified or are missing.
+	Drift(ctx context.Context, server networkmodel.ServerModel) (networkmodel.ServerDrift, error)
+
 	// UpdateDeployments updates all deployments currently defined on the server.
 	UpdateDeployments(
 		ctx context.Context,
@@ -34,6 +66,20 @@ type Manager interface {
 		failOnUnexpectedOldFilesOnDisk bool,
 	) error
 
+	// PlanUpdateDeployments computes the changes UpdateDeployments would perform with the same arguments without
+	// performing them.
+	PlanUpdateDeployments(
+		ctx context.Context,
+		server networkmodel.ServerModel,
+		requiresRestart bool,
+		failOnUnexpectedOldFilesOnDisk bool,
+	) (networkmodel.UpdatePlan, error)
+
+	// RecoverUpdateJournals replays the journals of all updates interrupted by a crash of the operator, rolling back
+	// incomplete updates. Servers with a journal that could not be replayed are refused to be started or updated.
+	// This must only be called before any update is started.
+	RecoverUpdateJournals(ctx context.Context) error
+
 	// ExchangeManagementMessage exchanges an outgoing message to a server with an incoming return message.
 	ExchangeManagementMessage(ctx context.Context, server networkmodel.ServerModel, outgoing proto.Message, response proto.Message) error
 }
@@ -61,9 +107,41 @@ type DockerBasedManager struct {
 	AutoRemoveContainers  bool
 	ContainerMemoryBuffer int64
 
+	// StartTimeout defines how long Start waits for a started server to report itself as running.
+	// A zero value disables waiting.
+	StartTimeout time.Duration
+
 	DiskPathMapping DiskPathMapping
 
 	FileEqualityRegistry fileeq.FileEqualityRegistry
+
+	// FileMergeRegistry holds the merges files deployed with a merge provider are merged with on updates.
+	FileMergeRegistry filemerge.FileMergeRegistry
+
+	// SigningKeys holds the keys the signatures of downloaded artefacts are verified against before they are used.
+	// If nil, only the hash of downloaded artefacts is verified.
+	SigningKeys *keyauth.Registry
+
+	// AllowUnsignedArtefacts allows artefacts the controller holds no signature for, namely those uploaded before
+	// signatures were recorded, to be used.
+	AllowUnsignedArtefacts bool
+
+	// ExpiredSigningKeyGracePeriod is the duration after their expiry the signing keys are still accepted for, allowing
+	// artefacts signed by them to be deployed until they are re-signed. Zero accepts no expired keys.
+	ExpiredSigningKeyGracePeriod time.Duration
+
+	// UpdateJournalPath is the folder update journals are staged in while the deployments of a server are updated.
+	UpdateJournalPath string
+
+	// DeploymentStatePath is the folder the state of the artefacts deployed onto servers is kept in, namely the template
+	// data their files were rendered with and the merge results of their merged files.
+	DeploymentStatePath string
+
+	// Backups defines where and how the data folders of servers are backed up.
+	Backups BackupConfiguration
+
+	// Metrics records the behaviour of the manager. It may be nil if no metrics are collected.
+	Metrics *metrics.Metrics
 }
 
 // FindDiskConfig locates the disk config for a specific environment.
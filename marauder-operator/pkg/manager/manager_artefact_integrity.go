@@ -0,0 +1,161 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// artefactDownloadAttempts is the amount of times an artefact is downloaded before a corrupted download is given up on.
+const artefactDownloadAttempts = 2
+
+var (
+	// ErrArtefactHashMismatch is returned if a downloaded artefact does not match the hash the controller recorded for it.
+	ErrArtefactHashMismatch = errors.New("artefact hash mismatch")
+
+	// ErrArtefactUnsigned is returned if the controller holds no signature for an artefact and unsigned artefacts are
+	// not allowed.
+	ErrArtefactUnsigned = errors.New("artefact unsigned")
+)
+
+// downloadVerifiedArtefact downloads the passed artefact and verifies it against its hash and signature before it is
+// used. Downloads that do not match the hash are downloaded again.
+// The download cache is shared by all updates and a concurrent download of the same artefact may replace the cached
+// file at any time, hence the artefact is verified and yielded back as a private copy of the download. The caller
+// has to remove the copy once done with it.
+func (d DockerBasedManager) downloadVerifiedArtefact(ctx context.Context, artefact uuid.UUID) (string, error) {
+	integrity, err := d.ControllerClient.FetchArtefactIntegrity(ctx, artefact)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch integrity of artefact: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		cachedArtefact, err := d.ControllerClient.DownloadArtefact(ctx, artefact)
+		if err != nil {
+			return "", fmt.Errorf("failed to download artefact: %w", err)
+		}
+
+		artefactOnDisk, err := copyCachedArtefact(cachedArtefact)
+		if err != nil {
+			return "", err
+		}
+
+		err = d.verifyArtefactIntegrity(artefactOnDisk, integrity)
+		if err == nil {
+			return artefactOnDisk, nil
+		}
+
+		_ = os.Remove(artefactOnDisk) // Never leave an unverified copy of the artefact behind.
+
+		if !errors.Is(err, ErrArtefactHashMismatch) || attempt >= artefactDownloadAttempts {
+			return "", fmt.Errorf("failed to verify artefact: %w", err)
+		}
+
+		logrus.Warnf("downloaded artefact %s is corrupted, downloading it again: %s", artefact, err)
+
+		// The corrupted artefact would otherwise be served from the download cache again.
+		if err := d.ControllerClient.EvictArtefact(artefact); err != nil {
+			return "", fmt.Errorf("failed to evict corrupted artefact from download cache: %w", err)
+		}
+	}
+}
+
+// copyCachedArtefact copies the artefact found in the download cache to a new, uniquely named file next to it.
+func copyCachedArtefact(cachedArtefact string) (string, error) {
+	cachedFile, err := os.Open(filepath.Clean(cachedArtefact))
+	if err != nil {
+		return "", fmt.Errorf("failed to open downloaded artefact: %w", err)
+	}
+
+	defer func() { _ = cachedFile.Close() }()
+
+	artefactCopy, err := os.CreateTemp(filepath.Dir(cachedArtefact), "."+filepath.Base(cachedArtefact)+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create copy of downloaded artefact: %w", err)
+	}
+
+	if _, err := io.Copy(artefactCopy, cachedFile); err != nil {
+		_ = artefactCopy.Close()
+		_ = os.Remove(artefactCopy.Name())
+
+		return "", fmt.Errorf("failed to copy downloaded artefact: %w", err)
+	}
+
+	if err := artefactCopy.Close(); err != nil {
+		_ = os.Remove(artefactCopy.Name())
+		return "", fmt.Errorf("failed to close copy of downloaded artefact: %w", err)
+	}
+
+	return artefactCopy.Name(), nil
+}
+
+// verifyArtefactIntegrity verifies that the artefact tarball at the passed path matches its hash and, if the manager
+// holds signing keys, that its signature was created by one of them.
+// The validity windows of the keys are evaluated now. Neither the upload date recorded by the controller nor the build
+// time chosen by the signer are trusted for this, as they would allow backdating artefacts into the validity window of an
+// expired key. Keys that expired within the configured grace period are accepted instead. Revoked keys are never accepted.
+func (d DockerBasedManager) verifyArtefactIntegrity(artefactPath string, integrity networkmodel.ArtefactIntegrity) error {
+	artefactFile, err := os.Open(filepath.Clean(artefactPath))
+	if err != nil {
+		return fmt.Errorf("failed to open artefact: %w", err)
+	}
+
+	defer func() { _ = artefactFile.Close() }()
+
+	hash, err := utils.ComputeSha256(artefactFile)
+	if err != nil {
+		return fmt.Errorf("failed to compute hash of artefact: %w", err)
+	}
+
+	if !bytes.Equal(hash, integrity.Hash) {
+		return fmt.Errorf("expected %x, found %x: %w", integrity.Hash, hash, ErrArtefactHashMismatch)
+	}
+
+	if d.SigningKeys == nil {
+		return nil
+	}
+
+	if len(integrity.Signature) == 0 {
+		if d.AllowUnsignedArtefacts {
+			return nil
+		}
+
+		return ErrArtefactUnsigned
+	}
+
+	now := time.Now()
+
+	err = d.verifyArtefactSignatureAt(artefactFile, integrity.Signature, now)
+	if !errors.Is(err, keyauth.ErrSigningKeyNotValid) || d.ExpiredSigningKeyGracePeriod <= 0 {
+		return err
+	}
+
+	// Keys that are not yet valid now were not valid before the grace period either, so only keys that expired within the
+	// grace period are accepted here.
+	return d.verifyArtefactSignatureAt(artefactFile, integrity.Signature, now.Add(-d.ExpiredSigningKeyGracePeriod))
+}
+
+// verifyArtefactSignatureAt verifies the signature of the passed artefact file with the validity windows of the signing
+// keys evaluated at the passed time.
+func (d DockerBasedManager) verifyArtefactSignatureAt(artefactFile *os.File, signature []byte, at time.Time) error {
+	if _, err := artefactFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to reset artefact file ref to start: %w", err)
+	}
+
+	if _, err := d.SigningKeys.Verify(artefactFile, signature, at); err != nil {
+		return fmt.Errorf("failed to verify artefact signature: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package manager_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/keyauth"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("Verifying the integrity of downloaded artefacts", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+		trustedSigner    ssh.Signer
+		artefactFolder   string
+		spellcore        uuid.UUID
+	)
+
+	generateSigner := func() ssh.Signer {
+		GinkgoHelper()
+
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Not(HaveOccurred()))
+
+		signer, err := ssh.NewSignerFromKey(privateKey)
+		Expect(err).To(Not(HaveOccurred()))
+
+		return signer
+	}
+
+	install := func(artefact uuid.UUID) error {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("spellcore", artefact)}
+
+		return serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)
+	}
+
+	expectNotInstalled := func() {
+		GinkgoHelper()
+
+		Expect(filepath.Join(serverFolder, "plugins", "spellcore.jar")).To(Not(BeAnExistingFile()))
+		Expect(controllerClient.isStates).To(BeEmpty())
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+		artefactFolder = controllerClient.folder
+
+		trustedSigner = generateSigner()
+		controllerClient.signer = trustedSigner
+		spellcore = controllerClient.addArtefact("spellcore", "1", map[string]string{"plugins/spellcore.jar": "spellcore 1"})
+
+		serverManager.SigningKeys = keyauth.NewRegistryFromKeys([]keyauth.SigningKey{{
+			ID:        keyauth.KeyID(trustedSigner.PublicKey()),
+			Name:      "ci",
+			PublicKey: trustedSigner.PublicKey(),
+		}})
+	})
+
+	It("installs an artefact signed by a trusted key", func() {
+		Expect(install(spellcore)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(serverFolder, "plugins", "spellcore.jar"))).To(BeEquivalentTo("spellcore 1"))
+		Expect(filepath.Glob(filepath.Join(artefactFolder, ".*"))).To(BeEmpty())
+	})
+
+	It("downloads a corrupted artefact again", func() {
+		controllerClient.corruptDownloadsOf[spellcore] = 1
+
+		Expect(install(spellcore)).To(Succeed())
+		Expect(controllerClient.downloads[spellcore]).To(Equal(2))
+		Expect(controllerClient.evictions[spellcore]).To(Equal(1))
+		Expect(os.ReadFile(filepath.Join(serverFolder, "plugins", "spellcore.jar"))).To(BeEquivalentTo("spellcore 1"))
+	})
+
+	It("gives up on an artefact that stays corrupted", func() {
+		controllerClient.corruptDownloadsOf[spellcore] = 2
+
+		Expect(install(spellcore)).To(MatchError(ErrArtefactHashMismatch))
+		Expect(controllerClient.downloads[spellcore]).To(Equal(2))
+		expectNotInstalled()
+	})
+
+	It("refuses an artefact signed by an unknown key", func() {
+		controllerClient.signer = generateSigner()
+
+		Expect(install(spellcore)).To(MatchError(keyauth.ErrUnknownSigningKey))
+		expectNotInstalled()
+
+		// Only the private copy verified by the update is removed, the cached download may be in use by other updates.
+		Expect(filepath.Join(artefactFolder, spellcore.String()+".tar.gz")).To(BeAnExistingFile())
+		Expect(filepath.Glob(filepath.Join(artefactFolder, ".*"))).To(BeEmpty())
+	})
+
+	It("refuses an artefact signed by a revoked key", func() {
+		serverManager.SigningKeys = keyauth.NewRegistryFromKeys([]keyauth.SigningKey{{
+			ID:        keyauth.KeyID(trustedSigner.PublicKey()),
+			Name:      "ci",
+			PublicKey: trustedSigner.PublicKey(),
+			Revoked:   true,
+		}})
+
+		Expect(install(spellcore)).To(MatchError(keyauth.ErrSigningKeyRevoked))
+		expectNotInstalled()
+	})
+
+	Describe("signing keys that expired", func() {
+		keyValidBefore := time.Now().Add(-time.Hour)
+
+		BeforeEach(func() {
+			serverManager.SigningKeys = keyauth.NewRegistryFromKeys([]keyauth.SigningKey{{
+				ID:          keyauth.KeyID(trustedSigner.PublicKey()),
+				Name:        "ci",
+				PublicKey:   trustedSigner.PublicKey(),
+				ValidBefore: &keyValidBefore,
+			}})
+		})
+
+		It("refuses artefacts whose upload date the controller backdated", func() {
+			controllerClient.uploadDate = new(keyValidBefore.Add(-time.Hour))
+
+			Expect(install(spellcore)).To(MatchError(keyauth.ErrSigningKeyNotValid))
+			expectNotInstalled()
+		})
+
+		It("refuses artefacts the signer attested to have built while the key was valid", func() {
+			controllerClient.buildInformation = &filemodel.BuildInformation{Timestamp: keyValidBefore.Add(-time.Hour)}
+			backdated := controllerClient.addArtefact("spellcore", "1", map[string]string{"plugins/spellcore.jar": "spellcore 1"})
+
+			Expect(install(backdated)).To(MatchError(keyauth.ErrSigningKeyNotValid))
+			expectNotInstalled()
+		})
+
+		It("installs artefacts signed by keys that expired within the grace period", func() {
+			serverManager.ExpiredSigningKeyGracePeriod = 2 * time.Hour
+
+			Expect(install(spellcore)).To(Succeed())
+		})
+
+		It("refuses artefacts signed by keys that expired before the grace period", func() {
+			serverManager.ExpiredSigningKeyGracePeriod = 30 * time.Minute
+
+			Expect(install(spellcore)).To(MatchError(keyauth.ErrSigningKeyNotValid))
+			expectNotInstalled()
+		})
+	})
+
+	Describe("unsigned artefacts", func() {
+		BeforeEach(func() {
+			controllerClient.signer = nil
+		})
+
+		It("refuses them by default", func() {
+			Expect(install(spellcore)).To(MatchError(ErrArtefactUnsigned))
+			expectNotInstalled()
+		})
+
+		It("installs them if allowed", func() {
+			serverManager.AllowUnsignedArtefacts = true
+
+			Expect(install(spellcore)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,624 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/gobwas/glob"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/knockturnmc/marauder/marauder-proto/src/main/golang/marauderpb"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrBackupsNotConfigured is returned if a backup is requested but no backup directory is configured.
+	ErrBackupsNotConfigured = errors.New("backups not configured")
+
+	// ErrBackupNotFound is returned if a backup to restore does not exist for the server.
+	ErrBackupNotFound = errors.New("backup not found")
+
+	// ErrUnsafeBackupEntry is returned if a backup archive contains an entry that would be restored outside the server folder.
+	ErrUnsafeBackupEntry = errors.New("unsafe backup entry")
+)
+
+const (
+	// backupNameLayout is the time layout used to name backups, allowing them to be sorted by name.
+	backupNameLayout = "20060102T150405.000Z"
+
+	// backupSequenceSeparator separates the sequence number suffixed to the names of backups created within the same
+	// millisecond from their creation time.
+	backupSequenceSeparator = "-"
+
+	// backupFileSuffix is the file suffix of backup archives in the backup directory.
+	backupFileSuffix = ".tar.gz"
+
+	// backupToggleSaveTimeout defines the timeout for toggling the save of a server around a backup.
+	backupToggleSaveTimeout = 10 * time.Second
+)
+
+// BackupConfiguration defines how the operator backs up the data folders of its servers.
+type BackupConfiguration struct {
+	// Directory is the directory backups are stored in, grouped by environment and server name.
+	// Backups are disabled if no directory is configured.
+	Directory string `yaml:"directory"`
+
+	// Include holds globs of paths relative to the server folder that are backed up. If empty, all files are backed up.
+	// The globs use / as separator, ** matches across directories.
+	Include []string `yaml:"include,omitempty"`
+
+	// Exclude holds globs of paths relative to the server folder that are not backed up.
+	// Directories matched by an exclude glob are skipped entirely.
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// Retention defines which backups are kept after a new backup was created.
+	Retention BackupRetention `yaml:"retention"`
+
+	// BeforeUpdateWithRestart defines if a backup is created after the server stopped during an update+restart.
+	BeforeUpdateWithRestart bool `yaml:"beforeUpdateWithRestart"`
+}
+
+// BackupRetention defines how many backups of a single server are kept. The latest backup is always kept.
+type BackupRetention struct {
+	// KeepLast is the amount of backups kept per server. A zero value keeps all backups.
+	KeepLast int `yaml:"keepLast"`
+
+	// MaxAge is the maximum age of a backup before it is deleted. A zero value keeps backups regardless of their age.
+	MaxAge time.Duration `yaml:"maxAge"`
+}
+
+// The backupFilter decides which paths of a server folder are part of a backup.
+type backupFilter struct {
+	include []glob.Glob
+	exclude []glob.Glob
+}
+
+// newBackupFilter compiles the include and exclude globs of the backup configuration.
+func newBackupFilter(configuration BackupConfiguration) (backupFilter, error) {
+	compile := func(patterns []string) ([]glob.Glob, error) {
+		compiled := make([]glob.Glob, 0, len(patterns))
+		for _, pattern := range patterns {
+			compiledGlob, err := glob.Compile(pattern, '/')
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile pattern %s: %w", pattern, err)
+			}
+
+			compiled = append(compiled, compiledGlob)
+		}
+
+		return compiled, nil
+	}
+
+	include, err := compile(configuration.Include)
+	if err != nil {
+		return backupFilter{}, fmt.Errorf("failed to compile include globs: %w", err)
+	}
+
+	exclude, err := compile(configuration.Exclude)
+	if err != nil {
+		return backupFilter{}, fmt.Errorf("failed to compile exclude globs: %w", err)
+	}
+
+	return backupFilter{include: include, exclude: exclude}, nil
+}
+
+// excludes computes if the passed path relative to the server folder is excluded from backups.
+func (f backupFilter) excludes(pathInFolder string) bool {
+	return slices.ContainsFunc(f.exclude, func(g glob.Glob) bool { return g.Match(pathInFolder) })
+}
+
+// includes computes if the passed file path relative to the server folder is part of backups.
+func (f backupFilter) includes(pathInFolder string) bool {
+	if f.excludes(pathInFolder) {
+		return false
+	}
+
+	return len(f.include) == 0 || slices.ContainsFunc(f.include, func(g glob.Glob) bool { return g.Match(pathInFolder) })
+}
+
+// walkIncludedFiles walks all regular files in the folder included by the filter.
+// Symlinks, sockets and other special files are never part of a backup.
+func (f backupFilter) walkIncludedFiles(folder string, consumer func(pathInFolder string) error) error {
+	if err := fs.WalkDir(os.DirFS(folder), ".", func(pathInFolder string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk %s: %w", pathInFolder, err)
+		}
+
+		if pathInFolder == "." {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if f.excludes(pathInFolder) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || !f.includes(pathInFolder) {
+			return nil
+		}
+
+		return consumer(pathInFolder)
+	}); err != nil {
+		return fmt.Errorf("failed to walk folder %s: %w", folder, err)
+	}
+
+	return nil
+}
+
+func (d DockerBasedManager) Backup(ctx context.Context, server networkmodel.ServerModel) (backup networkmodel.ServerBackup, err error) {
+	ctx, span := tracing.Start(ctx, "backup server", serverAttributes(server)...)
+	defer func() { tracing.End(span, err) }()
+
+	backupFolder, err := d.computeBackupFolderLocation(server)
+	if err != nil {
+		return networkmodel.ServerBackup{}, err
+	}
+
+	filter, err := newBackupFilter(d.Backups)
+	if err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to create backup filter: %w", err)
+	}
+
+	serverFolder, err := d.computeServerFolderLocation(server)
+	if err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to compute server folder location: %w", err)
+	}
+
+	if err := os.MkdirAll(backupFolder, 0o700); err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to create backup folder %s: %w", backupFolder, err)
+	}
+
+	enableSave, err := d.disableSaveDuringBackup(ctx, server)
+	if err != nil {
+		return networkmodel.ServerBackup{}, err
+	}
+
+	createdAt := time.Now().UTC()
+	name, size, err := createBackupArchive(serverFolder, backupFolder, createdAt, filter)
+	enableSave()
+
+	if err != nil {
+		return networkmodel.ServerBackup{}, fmt.Errorf("failed to create backup archive: %w", err)
+	}
+
+	backup = networkmodel.ServerBackup{
+		Name:        name,
+		ServerUUID:  server.UUID,
+		Environment: server.Environment,
+		ServerName:  server.Name,
+		CreatedAt:   createdAt,
+		SizeBytes:   size,
+	}
+
+	if err := d.applyBackupRetention(server, createdAt); err != nil {
+		logrus.Warn("failed to apply backup retention on server ", server.Environment, "/", server.Name, ": ", err)
+	}
+
+	logrus.Info("created backup ", name, " of server ", server.Environment, "/", server.Name)
+
+	return backup, nil
+}
+
+func (d DockerBasedManager) ListBackups(_ context.Context, server networkmodel.ServerModel) ([]networkmodel.ServerBackup, error) {
+	backupFolder, err := d.computeBackupFolderLocation(server)
+	if err != nil {
+		return nil, err
+	}
+
+	return listBackupsIn(backupFolder, server)
+}
+
+func (d DockerBasedManager) RestoreBackup(ctx context.Context, server networkmodel.ServerModel, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "restore server backup", serverAttributes(server, attribute.String("marauder.backup.name", name))...)
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := d.retrieveContainerInfo(ctx, server); err == nil {
+		return fmt.Errorf("server %s is running: %w", server.UUID.String(), ErrServerRunning)
+	} else if !utils.CheckDockerError(err, errdefs.IsNotFound) {
+		return fmt.Errorf("failed to fetch container info for %s: %w", server.UUID.String(), err)
+	}
+
+	backupFolder, err := d.computeBackupFolderLocation(server)
+	if err != nil {
+		return err
+	}
+
+	backups, err := listBackupsIn(backupFolder, server)
+	if err != nil {
+		return err
+	}
+
+	// Only restore backups known by name, the name is never used to construct a path on its own.
+	if !slices.ContainsFunc(backups, func(backup networkmodel.ServerBackup) bool { return backup.Name == name }) {
+		return fmt.Errorf("failed to find backup %s of server %s: %w", name, server.UUID.String(), ErrBackupNotFound)
+	}
+
+	filter, err := newBackupFilter(d.Backups)
+	if err != nil {
+		return fmt.Errorf("failed to create backup filter: %w", err)
+	}
+
+	serverFolder, err := d.computeServerFolderLocation(server)
+	if err != nil {
+		return fmt.Errorf("failed to compute server folder location: %w", err)
+	}
+
+	diskConfig, err := d.FindDiskConfig(server)
+	if err != nil {
+		return fmt.Errorf("failed to find disk config: %w", err)
+	}
+
+	if err := restoreBackupArchive(filepath.Join(backupFolder, name+backupFileSuffix), serverFolder, filter, diskConfig.FolderOwner); err != nil {
+		return fmt.Errorf("failed to restore backup %s: %w", name, err)
+	}
+
+	logrus.Info("restored backup ", name, " of server ", server.Environment, "/", server.Name)
+
+	return nil
+}
+
+// computeBackupFolderLocation computes the folder the backups of the passed server are stored in.
+func (d DockerBasedManager) computeBackupFolderLocation(server networkmodel.ServerModel) (string, error) {
+	if strings.TrimSpace(d.Backups.Directory) == "" {
+		return "", ErrBackupsNotConfigured
+	}
+
+	return utils.CleanPathAndJoin(d.Backups.Directory, path.Join(server.Environment, server.Name)), nil
+}
+
+// disableSaveDuringBackup disables saving on the server if it is running and reachable via its management socket.
+// The returned function enables saving again and has to be called once the backup is completed.
+func (d DockerBasedManager) disableSaveDuringBackup(ctx context.Context, server networkmodel.ServerModel) (func(), error) {
+	noop := func() {}
+	if server.ManagementSocketPath == "" {
+		return noop, nil
+	}
+
+	if _, err := d.retrieveContainerInfo(ctx, server); err != nil {
+		if utils.CheckDockerError(err, errdefs.IsNotFound) {
+			return noop, nil
+		}
+
+		return nil, fmt.Errorf("failed to fetch container info for %s: %w", server.UUID.String(), err)
+	}
+
+	toggleSave := func(ctx context.Context, save bool) error {
+		withTimeout, cancelFunction := context.WithTimeout(ctx, backupToggleSaveTimeout)
+		defer cancelFunction()
+
+		var toggleResponse marauderpb.ServerToggleSaveRequest_Response
+
+		return d.ExchangeManagementMessage(
+			withTimeout,
+			server,
+			marauderpb.ServerToggleSaveRequest_builder{Save: new(save)}.Build(),
+			&toggleResponse,
+		)
+	}
+
+	if err := toggleSave(ctx, false); err != nil {
+		return nil, fmt.Errorf("failed to disable save on server %s: %w", server.UUID.String(), err)
+	}
+
+	return func() {
+		// Saving has to be enabled again even if the backup request was cancelled.
+		if err := toggleSave(context.WithoutCancel(ctx), true); err != nil {
+			logrus.Error("failed to enable save on server ", server.Environment, "/", server.Name, " after backup: ", err)
+		}
+	}, nil
+}
+
+// applyBackupRetention deletes all backups of the server no longer retained by the configured retention.
+func (d DockerBasedManager) applyBackupRetention(server networkmodel.ServerModel, now time.Time) error {
+	backupFolder, err := d.computeBackupFolderLocation(server)
+	if err != nil {
+		return err
+	}
+
+	backups, err := listBackupsIn(backupFolder, server)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, backup := range expiredBackups(backups, d.Backups.Retention, now) {
+		if err := os.Remove(filepath.Join(backupFolder, backup.Name+backupFileSuffix)); err != nil {
+			lastErr = fmt.Errorf("failed to delete backup %s: %w", backup.Name, err)
+			continue
+		}
+
+		logrus.Debug("deleted expired backup ", backup.Name, " of server ", server.Environment, "/", server.Name)
+	}
+
+	return lastErr
+}
+
+// expiredBackups computes the backups not retained by the passed retention.
+// The passed backups are expected to be sorted from newest to oldest, the newest backup is always retained.
+func expiredBackups(backups []networkmodel.ServerBackup, retention BackupRetention, now time.Time) []networkmodel.ServerBackup {
+	expired := make([]networkmodel.ServerBackup, 0)
+	for index, backup := range backups {
+		if index == 0 {
+			continue
+		}
+
+		if (retention.KeepLast > 0 && index >= retention.KeepLast) || (retention.MaxAge > 0 && now.Sub(backup.CreatedAt) > retention.MaxAge) {
+			expired = append(expired, backup)
+		}
+	}
+
+	return expired
+}
+
+// listBackupsIn lists all backups found in the backup folder of the server, sorted from newest to oldest.
+func listBackupsIn(backupFolder string, server networkmodel.ServerModel) ([]networkmodel.ServerBackup, error) {
+	entries, err := os.ReadDir(backupFolder)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return make([]networkmodel.ServerBackup, 0), nil
+		}
+
+		return nil, fmt.Errorf("failed to read backup folder %s: %w", backupFolder, err)
+	}
+
+	backups := make([]networkmodel.ServerBackup, 0, len(entries))
+	sequences := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		name, isBackup := strings.CutSuffix(entry.Name(), backupFileSuffix)
+		if !isBackup || !entry.Type().IsRegular() {
+			continue
+		}
+
+		createdAt, sequence, err := parseBackupName(name)
+		if err != nil {
+			continue // Not created by the operator.
+		}
+
+		sequences[name] = sequence
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat backup %s: %w", entry.Name(), err)
+		}
+
+		backups = append(backups, networkmodel.ServerBackup{
+			Name:        name,
+			ServerUUID:  server.UUID,
+			Environment: server.Environment,
+			ServerName:  server.Name,
+			CreatedAt:   createdAt,
+			SizeBytes:   info.Size(),
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b networkmodel.ServerBackup) int {
+		if order := b.CreatedAt.Compare(a.CreatedAt); order != 0 {
+			return order
+		}
+
+		return sequences[b.Name] - sequences[a.Name]
+	})
+
+	return backups, nil
+}
+
+// backupName names the backup created at the passed time. Backups created within the same millisecond are told apart by
+// the passed sequence number, which is omitted for the first backup.
+func backupName(createdAt time.Time, sequence int) string {
+	name := createdAt.Format(backupNameLayout)
+	if sequence == 0 {
+		return name
+	}
+
+	return name + backupSequenceSeparator + strconv.Itoa(sequence)
+}
+
+// parseBackupName parses the creation time and sequence number from the name of a backup.
+func parseBackupName(name string) (time.Time, int, error) {
+	timestamp, sequenceSuffix, hasSequence := strings.Cut(name, backupSequenceSeparator)
+
+	createdAt, err := time.Parse(backupNameLayout, timestamp)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to parse creation time of backup %s: %w", name, err)
+	}
+
+	if !hasSequence {
+		return createdAt, 0, nil
+	}
+
+	sequence, err := strconv.Atoi(sequenceSuffix)
+	if err != nil || sequence <= 0 || backupName(createdAt, sequence) != name {
+		return time.Time{}, 0, fmt.Errorf("failed to parse sequence of backup %s: %w", name, strconv.ErrSyntax)
+	}
+
+	return createdAt, sequence, nil
+}
+
+// createBackupArchive writes all files of the server folder included by the filter into a gzipped tarball in the backup
+// folder, yielding back the name of the created backup and its size.
+// The archive is written to a temporary file first, so incomplete archives are never listed as backups.
+func createBackupArchive(serverFolder string, backupFolder string, createdAt time.Time, filter backupFilter) (string, int64, error) {
+	archiveFile, err := os.CreateTemp(backupFolder, ".backup-*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create archive in %s: %w", backupFolder, err)
+	}
+
+	temporaryPath := archiveFile.Name()
+
+	defer func() { _ = os.Remove(temporaryPath) }()
+	defer utils.SwallowClose(archiveFile)
+
+	tarballWriter, err := utils.NewFriendlyTarballWriterGZ(archiveFile, gzip.DefaultCompression)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create tarball writer: %w", err)
+	}
+
+	serverFS := os.DirFS(serverFolder)
+	if err := filter.walkIncludedFiles(serverFolder, func(pathInFolder string) error {
+		if _, err := tarballWriter.AddFile(serverFS, pathInFolder, pathInFolder); err != nil {
+			return fmt.Errorf("failed to add %s to backup: %w", pathInFolder, err)
+		}
+
+		return nil
+	}); err != nil {
+		_ = tarballWriter.Close()
+		return "", 0, err
+	}
+
+	if err := tarballWriter.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to close tarball writer: %w", err)
+	}
+
+	if err := archiveFile.Sync(); err != nil {
+		return "", 0, fmt.Errorf("failed to sync archive: %w", err)
+	}
+
+	stat, err := archiveFile.Stat()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	name, err := publishBackupArchive(temporaryPath, backupFolder, createdAt)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return name, stat.Size(), nil
+}
+
+// publishBackupArchive moves the archive at the temporary path into the backup folder under the first name for the
+// passed creation time not taken by another backup, yielding back the name.
+// The archive is linked rather than renamed into place, as linking fails instead of replacing an existing backup.
+func publishBackupArchive(temporaryPath string, backupFolder string, createdAt time.Time) (string, error) {
+	for sequence := 0; ; sequence++ {
+		name := backupName(createdAt, sequence)
+		archivePath := filepath.Join(backupFolder, name+backupFileSuffix)
+
+		if err := os.Link(temporaryPath, archivePath); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				continue
+			}
+
+			return "", fmt.Errorf("failed to move archive to %s: %w", archivePath, err)
+		}
+
+		return name, nil
+	}
+}
+
+// restoreBackupArchive restores the backup archive into the server folder.
+// Files included by the filter that are not part of the backup are deleted, excluded files are left untouched.
+// Files are deleted and restored through the server folder as a root, so symbolic links on disk cannot redirect the
+// restore outside of it.
+func restoreBackupArchive(archivePath string, serverFolderLocation string, filter backupFilter, owner *FolderOwner) error {
+	serverFolder, err := os.OpenRoot(serverFolderLocation)
+	if err != nil {
+		return fmt.Errorf("failed to open server folder %s: %w", serverFolderLocation, err)
+	}
+
+	defer utils.SwallowClose(serverFolder)
+
+	filesInBackup := make(map[string]struct{})
+	if err := readBackupArchive(archivePath, func(header *tar.Header, _ io.Reader) error {
+		filesInBackup[header.Name] = struct{}{}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to index backup: %w", err)
+	}
+
+	if err := filter.walkIncludedFiles(serverFolderLocation, func(pathInFolder string) error {
+		if _, inBackup := filesInBackup[pathInFolder]; inBackup {
+			return nil
+		}
+
+		if err := serverFolder.Remove(filepath.FromSlash(pathInFolder)); err != nil {
+			return fmt.Errorf("failed to delete %s not found in backup: %w", pathInFolder, err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return readBackupArchive(archivePath, func(header *tar.Header, content io.Reader) error {
+		return restoreBackupFile(serverFolder, header, content, owner)
+	})
+}
+
+// readBackupArchive passes each regular file in the backup archive to the consumer.
+// Entries that are not local paths are rejected before they are passed to the consumer.
+func readBackupArchive(archivePath string, consumer func(header *tar.Header, content io.Reader) error) error {
+	tarballReader, err := utils.NewFriendlyTarballReaderFromPath(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup archive %s: %w", archivePath, err)
+	}
+
+	defer func() { _ = tarballReader.Close(true) }()
+
+	for {
+		header, err := tarballReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read next entry: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if !filepath.IsLocal(filepath.FromSlash(header.Name)) {
+			return fmt.Errorf("entry %s: %w", header.Name, ErrUnsafeBackupEntry)
+		}
+
+		if err := consumer(header, tarballReader); err != nil {
+			return err
+		}
+	}
+}
+
+// restoreBackupFile writes a single file of a backup archive into the server folder, chowning it and all its parent
+// directories to the owner if passed. Symbolic links at the path of the file are replaced rather than written through.
+func restoreBackupFile(serverFolder *os.Root, header *tar.Header, content io.Reader, owner *FolderOwner) error {
+	pathInFolder := filepath.FromSlash(header.Name)
+
+	if err := serverFolder.MkdirAll(filepath.Dir(pathInFolder), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory for %s: %w", header.Name, err)
+	}
+
+	if err := writeDeployedFile(serverFolder, pathInFolder, content, header.FileInfo().Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", header.Name, err)
+	}
+
+	if owner == nil {
+		return nil
+	}
+
+	for chownedPath := pathInFolder; chownedPath != "."; chownedPath = filepath.Dir(chownedPath) {
+		if err := serverFolder.Lchown(chownedPath, owner.UID, owner.GID); err != nil {
+			return fmt.Errorf("failed to chown %s: %w", chownedPath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package manager_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backups", Label("unittest"), func() {
+	var (
+		serverFolder  string
+		backupFolder  string
+		server        networkmodel.ServerModel
+		serverManager *DockerBasedManager
+	)
+
+	writeServerFile := func(pathInFolder string, content string) {
+		GinkgoHelper()
+
+		filePath := filepath.Join(serverFolder, filepath.FromSlash(pathInFolder))
+		Expect(os.MkdirAll(filepath.Dir(filePath), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filePath, []byte(content), 0o600)).To(Succeed())
+	}
+
+	readServerFile := func(pathInFolder string) string {
+		GinkgoHelper()
+
+		content, err := os.ReadFile(filepath.Join(serverFolder, filepath.FromSlash(pathInFolder)))
+		Expect(err).To(Not(HaveOccurred()))
+
+		return string(content)
+	}
+
+	createBackup := func() networkmodel.ServerBackup {
+		GinkgoHelper()
+
+		backup, err := serverManager.Backup(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+
+		return backup
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		backupFolder = filepath.Join(root, "backups")
+		server = networkmodel.ServerModel{UUID: uuid.New(), Environment: "dev", Name: "lobby"}
+		serverFolder = filepath.Join(root, "servers", server.Environment, server.Name)
+
+		serverManager = &DockerBasedManager{
+			DiskPathMapping: DiskPathMapping{
+				"*": EnvironmentDiskConfig{ServerDataPathTemplate: filepath.Join(root, "servers", "{{.Environment}}", "{{.Name}}")},
+			},
+			Backups: BackupConfiguration{Directory: backupFolder},
+		}
+
+		writeServerFile("server.properties", "motd=hello")
+		writeServerFile("world/level.dat", "level")
+		writeServerFile("logs/latest.log", "log line")
+	})
+
+	It("fails if no backup directory is configured", func() {
+		serverManager.Backups.Directory = ""
+
+		_, err := serverManager.Backup(context.Background(), server)
+		Expect(err).To(MatchError(ErrBackupsNotConfigured))
+	})
+
+	It("lists created backups newest first", func() {
+		first := createBackup()
+		second := createBackup()
+
+		backups, err := serverManager.ListBackups(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Name).To(Equal(second.Name))
+		Expect(backups[1].Name).To(Equal(first.Name))
+		Expect(backups[0].SizeBytes).To(BeNumerically(">", 0))
+		Expect(backups[0].ServerUUID).To(Equal(server.UUID))
+	})
+
+	It("names backups created within the same millisecond uniquely", func() {
+		names := make([]string, 0, 5)
+		for range 5 {
+			names = append(names, createBackup().Name)
+		}
+
+		Expect(names).To(ConsistOf(names[0], names[1], names[2], names[3], names[4]))
+		Expect(serverManager.ListBackups(context.Background(), server)).To(HaveLen(5))
+	})
+
+	It("lists no backups for a server never backed up", func() {
+		backups, err := serverManager.ListBackups(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(backups).To(BeEmpty())
+	})
+
+	It("keeps only the configured amount of backups", func() {
+		serverManager.Backups.Retention = BackupRetention{KeepLast: 2}
+
+		createBackup()
+		second := createBackup()
+		third := createBackup()
+
+		backups, err := serverManager.ListBackups(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Name).To(Equal(third.Name))
+		Expect(backups[1].Name).To(Equal(second.Name))
+	})
+
+	It("deletes backups older than the max age but keeps the latest", func() {
+		serverManager.Backups.Retention = BackupRetention{MaxAge: time.Nanosecond}
+
+		createBackup()
+		latest := createBackup()
+
+		backups, err := serverManager.ListBackups(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Name).To(Equal(latest.Name))
+	})
+
+	It("restores the backed up files and deletes files created after the backup", func() {
+		backup := createBackup()
+
+		writeServerFile("server.properties", "motd=changed")
+		writeServerFile("world/region/r.0.0.mca", "new region")
+		Expect(os.Remove(filepath.Join(serverFolder, "world", "level.dat"))).To(Succeed())
+
+		archivePath := filepath.Join(backupFolder, server.Environment, server.Name, backup.Name+".tar.gz")
+		Expect(RestoreBackupArchive(archivePath, serverFolder, serverManager.Backups)).To(Succeed())
+
+		Expect(readServerFile("server.properties")).To(Equal("motd=hello"))
+		Expect(readServerFile("world/level.dat")).To(Equal("level"))
+		Expect(filepath.Join(serverFolder, "world", "region", "r.0.0.mca")).To(Not(BeAnExistingFile()))
+	})
+
+	It("restores the permissions of the backed up files", func() {
+		Expect(os.Chmod(filepath.Join(serverFolder, "world", "level.dat"), 0o640)).To(Succeed())
+
+		backup := createBackup()
+
+		Expect(os.Remove(filepath.Join(serverFolder, "server.properties"))).To(Succeed())
+		Expect(os.Remove(filepath.Join(serverFolder, "world", "level.dat"))).To(Succeed())
+
+		archivePath := filepath.Join(backupFolder, server.Environment, server.Name, backup.Name+".tar.gz")
+		Expect(RestoreBackupArchive(archivePath, serverFolder, serverManager.Backups)).To(Succeed())
+
+		secretStat, err := os.Stat(filepath.Join(serverFolder, "server.properties"))
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(secretStat.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		levelStat, err := os.Stat(filepath.Join(serverFolder, "world", "level.dat"))
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(levelStat.Mode().Perm()).To(Equal(os.FileMode(0o640)))
+	})
+
+	It("does not restore through symlinked directories", func() {
+		backup := createBackup()
+
+		outsideFolder := filepath.Join(filepath.Dir(backupFolder), "outside")
+		Expect(os.MkdirAll(outsideFolder, 0o700)).To(Succeed())
+		Expect(os.RemoveAll(filepath.Join(serverFolder, "world"))).To(Succeed())
+		Expect(os.Symlink(outsideFolder, filepath.Join(serverFolder, "world"))).To(Succeed())
+
+		archivePath := filepath.Join(backupFolder, server.Environment, server.Name, backup.Name+".tar.gz")
+		Expect(RestoreBackupArchive(archivePath, serverFolder, serverManager.Backups)).To(HaveOccurred())
+		Expect(os.ReadDir(outsideFolder)).To(BeEmpty())
+	})
+
+	It("respects include and exclude globs on backup and restore", func() {
+		serverManager.Backups.Include = []string{"world/**", "*.properties", "logs/**"}
+		serverManager.Backups.Exclude = []string{"logs"}
+		writeServerFile("plugins/plugin.jar", "jar")
+
+		backup := createBackup()
+
+		writeServerFile("logs/latest.log", "newer log line")
+		writeServerFile("plugins/plugin.jar", "updated jar")
+		writeServerFile("world/level.dat", "changed level")
+
+		archivePath := filepath.Join(backupFolder, server.Environment, server.Name, backup.Name+".tar.gz")
+		Expect(RestoreBackupArchive(archivePath, serverFolder, serverManager.Backups)).To(Succeed())
+
+		Expect(readServerFile("world/level.dat")).To(Equal("level"))
+		Expect(readServerFile("logs/latest.log")).To(Equal("newer log line"))
+		Expect(readServerFile("plugins/plugin.jar")).To(Equal("updated jar"))
+	})
+
+	It("rejects backups with entries outside of the server folder", func() {
+		archivePath := filepath.Join(GinkgoT().TempDir(), "malicious.tar.gz")
+		archiveFile, err := os.Create(archivePath)
+		Expect(err).To(Not(HaveOccurred()))
+
+		tarballWriter, err := utils.NewFriendlyTarballWriterGZ(archiveFile, gzip.DefaultCompression)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(tarballWriter.Write([]byte("evil"), tar.Header{Name: "../escaped.txt", Typeflag: tar.TypeReg, Mode: 0o600})).To(Succeed())
+		Expect(tarballWriter.Close()).To(Succeed())
+		Expect(archiveFile.Close()).To(Succeed())
+
+		Expect(RestoreBackupArchive(archivePath, serverFolder, serverManager.Backups)).To(MatchError(ErrUnsafeBackupEntry))
+		Expect(filepath.Join(filepath.Dir(serverFolder), "escaped.txt")).To(Not(BeAnExistingFile()))
+		Expect(readServerFile("server.properties")).To(Equal("motd=hello"))
+	})
+})
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// attachedConsole implements an io.ReadWriteCloser on top of a hijacked docker attach connection.
+type attachedConsole struct {
+	hijacked types.HijackedResponse
+}
+
+func (a attachedConsole) Read(p []byte) (int, error) {
+	return a.hijacked.Reader.Read(p) //nolint:wrapcheck
+}
+
+func (a attachedConsole) Write(p []byte) (int, error) {
+	return a.hijacked.Conn.Write(p) //nolint:wrapcheck
+}
+
+func (a attachedConsole) Close() error {
+	a.hijacked.Close()
+	return nil
+}
+
+func (d DockerBasedManager) Attach(ctx context.Context, server networkmodel.ServerModel) (io.ReadWriteCloser, error) {
+	// Containers are created with a tty, so the output is not multiplexed and can be forwarded as is.
+	hijacked, err := d.DockerClient.ContainerAttach(ctx, d.computeUniqueDockerContainerNameFor(server), container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		if utils.CheckDockerError(err, errdefs.IsNotFound) {
+			return nil, fmt.Errorf("container of server %s: %w", server.UUID, ErrServerContainerNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to attach to container: %w", err)
+	}
+
+	return attachedConsole{hijacked: hijacked}, nil
+}
+
+func (d DockerBasedManager) ResizeConsole(ctx context.Context, server networkmodel.ServerModel, width uint, height uint) error {
+	if err := d.DockerClient.ContainerResize(ctx, d.computeUniqueDockerContainerNameFor(server), container.ResizeOptions{
+		Width:  width,
+		Height: height,
+	}); err != nil {
+		return fmt.Errorf("failed to resize container tty: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// installedOnlyIfAbsent returns if files deployed in the passed mode are only extracted if they do not exist on disk.
+func installedOnlyIfAbsent(mode filemodel.FileDeploymentMode) bool {
+	return mode == filemodel.FileDeploymentInstallOnce || mode == filemodel.FileDeploymentPreserveIfModified
+}
+
+// deploymentModeOf yields back the deployment mode of the passed file in the tarball given the file references of its artefact.
+func deploymentModeOf(fileReferences map[string]*filemodel.FileReference, pathInTarball string) filemodel.FileDeploymentMode {
+	fileReference, found := fileReferences[pathInTarball]
+	if !found {
+		return filemodel.FileDeploymentReplace
+	}
+
+	return fileReference.Deployment.DeploymentMode()
+}
+
+// unexpectedDrift filters the drift of the passed artefact down to the files whose deployment does not expect
+// modifications by the server, as the files are neither preserved nor merged if modified.
+func unexpectedDrift(artefact filemodel.Manifest, drift []networkmodel.FileDrift) []networkmodel.FileDrift {
+	fileReferences := artefact.Files.MatchedFilesToReferenceMap()
+
+	result := make([]networkmodel.FileDrift, 0, len(drift))
+	for _, file := range drift {
+		fileReference, found := fileReferences[pkg.FileParentDirectoryInArtefact+file.Path]
+		if found && (fileReference.Deployment.DeploymentMode() == filemodel.FileDeploymentPreserveIfModified ||
+			fileReference.Deployment != nil && fileReference.Deployment.MergeProvider != nil) {
+			continue
+		}
+
+		result = append(result, file)
+	}
+
+	return result
+}
+
+// oldFilesKeptOnDisk yields back the files of the artefact to uninstall, relative to the server folder, that are kept on
+// disk instead of being deleted given their deployment mode, their drift and the artefact to install.
+func oldFilesKeptOnDisk(
+	artefactToUninstall filemodel.Manifest,
+	artefactToInstall filemodel.Manifest,
+	drift []networkmodel.FileDrift,
+) map[string]bool {
+	driftKinds := make(map[string]networkmodel.FileDriftKind, len(drift))
+	for _, file := range drift {
+		driftKinds[file.Path] = file.Kind
+	}
+
+	newFiles := make(map[string]bool)
+	for _, file := range manifestFilesInServerFolder(artefactToInstall) {
+		newFiles[file] = true
+	}
+
+	keptFiles := make(map[string]bool)
+	for filePathWithPrefix, fileReference := range artefactToUninstall.Files.MatchedFilesToReferenceMap() {
+		file, _ := strings.CutPrefix(filePathWithPrefix, pkg.FileParentDirectoryInArtefact)
+
+		switch fileReference.Deployment.DeploymentMode() {
+		case filemodel.FileDeploymentInstallOnce:
+			keptFiles[file] = true
+		case filemodel.FileDeploymentPreserveIfModified:
+			keptFiles[file] = driftKinds[file] == networkmodel.FileDriftModified
+		case filemodel.FileDeploymentKeepOnUninstall:
+			keptFiles[file] = !newFiles[file]
+		case filemodel.FileDeploymentReplace:
+		}
+	}
+
+	return keptFiles
+}
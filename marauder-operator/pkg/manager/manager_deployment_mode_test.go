@@ -0,0 +1,291 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deploying files with deployment modes", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+
+		configV1, configV2 uuid.UUID
+	)
+
+	configFile := func() string {
+		return filepath.Join(serverFolder, "plugins", "spellcore", "config.yml")
+	}
+
+	writeConfigFile := func(content string) {
+		GinkgoHelper()
+
+		Expect(os.MkdirAll(filepath.Dir(configFile()), 0o700)).To(Succeed())
+		Expect(os.WriteFile(configFile(), []byte(content), 0o640)).To(Succeed())
+	}
+
+	addConfigArtefact := func(version string, mode filemodel.FileDeploymentMode) uuid.UUID {
+		GinkgoHelper()
+
+		artefactUUID := controllerClient.addArtefact("config", version, map[string]string{
+			"plugins/spellcore/config.yml": "config " + version,
+		})
+
+		artefact := controllerClient.artefacts[artefactUUID]
+		artefact.manifest.Files[0].Deployment = &filemodel.FileDeployment{Mode: &mode}
+		controllerClient.artefacts[artefactUUID] = artefact
+
+		return artefactUUID
+	}
+
+	install := func() {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("config", configV1)}
+	}
+
+	update := func() {
+		controllerClient.isStates["config"] = configV1
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "config", Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV1, Version: "1"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV2, Version: "2"},
+				},
+			}},
+		}
+	}
+
+	uninstall := func() {
+		controllerClient.isStates["config"] = configV1
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "config", Missmatch: networkmodel.ArtefactMissmatch{
+				Uninstall: &networkmodel.ArtefactVersionMissmatchUninstall{
+					Is: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV1, Version: "1"},
+				},
+			}},
+		}
+	}
+
+	updateDeployments := func() error {
+		return serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+	})
+
+	It("refuses artefacts with an unknown deployment mode", func() {
+		configV1 = addConfigArtefact("1", "overwrite-sometimes")
+		install()
+
+		Expect(updateDeployments()).To(MatchError(filemodel.ErrUnknownFileDeploymentMode))
+		Expect(configFile()).To(Not(BeAnExistingFile()))
+	})
+
+	Describe("replace", func() {
+		BeforeEach(func() {
+			configV1 = addConfigArtefact("1", filemodel.FileDeploymentReplace)
+			configV2 = addConfigArtefact("2", filemodel.FileDeploymentReplace)
+		})
+
+		It("overwrites existing files on install", func() {
+			writeConfigFile("local")
+			install()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 1"))
+		})
+
+		It("fails the update on modified files", func() {
+			writeConfigFile("modified")
+			update()
+
+			Expect(updateDeployments()).To(MatchError(ErrFileUnequal))
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("modified"))
+		})
+
+		It("deletes files on uninstall", func() {
+			writeConfigFile("config 1")
+			uninstall()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(configFile()).To(Not(BeAnExistingFile()))
+		})
+	})
+
+	Describe("install-once", func() {
+		BeforeEach(func() {
+			configV1 = addConfigArtefact("1", filemodel.FileDeploymentInstallOnce)
+			configV2 = addConfigArtefact("2", filemodel.FileDeploymentInstallOnce)
+		})
+
+		It("installs absent files", func() {
+			install()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 1"))
+		})
+
+		It("keeps existing files on install", func() {
+			writeConfigFile("local")
+			install()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("local"))
+		})
+
+		It("neither validates nor overwrites files on update", func() {
+			writeConfigFile("modified")
+			update()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("modified"))
+			Expect(controllerClient.isStates).To(HaveKeyWithValue("config", configV2))
+		})
+
+		It("installs files deleted by the server on update", func() {
+			update()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 2"))
+		})
+
+		It("keeps files on uninstall", func() {
+			writeConfigFile("config 1")
+			uninstall()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 1"))
+		})
+
+		It("does not report drift of files", func() {
+			writeConfigFile("modified")
+			controllerClient.isStates["config"] = configV1
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(drift.HasDrift()).To(BeFalse())
+		})
+	})
+
+	Describe("preserve-if-modified", func() {
+		BeforeEach(func() {
+			configV1 = addConfigArtefact("1", filemodel.FileDeploymentPreserveIfModified)
+			configV2 = addConfigArtefact("2", filemodel.FileDeploymentPreserveIfModified)
+		})
+
+		It("keeps existing files on install", func() {
+			writeConfigFile("local")
+			install()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("local"))
+		})
+
+		It("replaces unmodified files on update", func() {
+			writeConfigFile("config 1")
+			update()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 2"))
+		})
+
+		It("preserves modified files on update without failing", func() {
+			writeConfigFile("modified")
+			update()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("modified"))
+		})
+
+		It("fails the update if the files cannot be compared instead of replacing modified files", func() {
+			artefact := controllerClient.artefacts[configV1]
+			artefact.manifest.Files[0].Deployment.EqualityProvider = new("unknown")
+			controllerClient.artefacts[configV1] = artefact
+
+			writeConfigFile("modified")
+			update()
+
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)).To(HaveOccurred())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("modified"))
+			Expect(controllerClient.isStates).To(HaveKeyWithValue("config", configV1))
+		})
+
+		It("plans no change for modified files", func() {
+			writeConfigFile("modified")
+			update()
+
+			plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, true)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(plan.FailsOnLocalModifications).To(BeFalse())
+			Expect(plan.Artefacts).To(ConsistOf(HaveField("Files", BeEmpty())))
+		})
+
+		It("deletes unmodified files on uninstall", func() {
+			writeConfigFile("config 1")
+			uninstall()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(configFile()).To(Not(BeAnExistingFile()))
+		})
+
+		It("keeps modified files on uninstall", func() {
+			writeConfigFile("modified")
+			uninstall()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("modified"))
+		})
+	})
+
+	Describe("keep-on-uninstall", func() {
+		BeforeEach(func() {
+			configV1 = addConfigArtefact("1", filemodel.FileDeploymentKeepOnUninstall)
+			configV2 = addConfigArtefact("2", filemodel.FileDeploymentKeepOnUninstall)
+		})
+
+		It("overwrites existing files on install", func() {
+			writeConfigFile("local")
+			install()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 1"))
+		})
+
+		It("replaces files on update", func() {
+			writeConfigFile("config 1")
+			update()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 2"))
+		})
+
+		It("fails the update on modified files", func() {
+			writeConfigFile("modified")
+			update()
+
+			Expect(updateDeployments()).To(MatchError(ErrFileUnequal))
+		})
+
+		It("keeps files on uninstall", func() {
+			writeConfigFile("config 1")
+			uninstall()
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("config 1"))
+			Expect(controllerClient.isStates).To(Not(HaveKey("config")))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+const (
+	// deploymentStateTemplateDataFile is the file in the state folder of a deployment persisting the template data the
+	// files of the deployed artefact were rendered with.
+	deploymentStateTemplateDataFile = "template-data.json"
+
+	// deploymentStateMergedFilesFolder is the folder in the state folder of a deployment holding the merge results of
+	// files deployed with a merge provider. Only merge results that differ from the file in the artefact are kept, drift
+	// detection compares the files against them.
+	deploymentStateMergedFilesFolder = "merged"
+
+	// deploymentStatePendingSuffix is appended to the state folder of a deployment to name the folder its state is written
+	// to while it is deployed. The pending state replaces the state folder once the update is completed.
+	deploymentStatePendingSuffix = ".pending"
+)
+
+// The deploymentState is the state of an artefact deployed onto a server that is kept outside the server folder.
+type deploymentState struct {
+	// folder is the folder the state is kept in.
+	folder string
+
+	// data is the template data the files of the artefact were rendered with.
+	data templateData
+}
+
+// computeServerDeploymentStateLocation computes the folder holding the states of all artefacts deployed onto the server.
+func (d DockerBasedManager) computeServerDeploymentStateLocation(server networkmodel.ServerModel) string {
+	return filepath.Join(d.DeploymentStatePath, server.UUID.String())
+}
+
+// computeDeploymentStateLocation computes the folder holding the state of the passed artefact deployed onto the server.
+// The state is keyed by the artefact rather than its identifier, so the state of an artefact replaced by an update that
+// is rolled back remains untouched.
+func (d DockerBasedManager) computeDeploymentStateLocation(server networkmodel.ServerModel, artefact uuid.UUID) string {
+	return filepath.Join(d.computeServerDeploymentStateLocation(server), artefact.String())
+}
+
+// writeDeploymentState writes the template data the passed artefact is deployed onto the server with into its pending
+// state, which the journal moves into place once the update is completed and discards if it is rolled back.
+func (d DockerBasedManager) writeDeploymentState(
+	server networkmodel.ServerModel,
+	artefact uuid.UUID,
+	data templateData,
+	journal *updateJournal,
+) (deploymentState, error) {
+	if err := journal.recordInstalledDeploymentState(artefact); err != nil {
+		return deploymentState{}, err
+	}
+
+	state := deploymentState{folder: d.computeDeploymentStateLocation(server, artefact) + deploymentStatePendingSuffix, data: data}
+	if err := os.RemoveAll(state.folder); err != nil {
+		return deploymentState{}, fmt.Errorf("failed to remove previous deployment state %s: %w", state.folder, err)
+	}
+
+	if err := os.MkdirAll(state.folder, 0o700); err != nil {
+		return deploymentState{}, fmt.Errorf("failed to create deployment state folder %s: %w", state.folder, err)
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return deploymentState{}, fmt.Errorf("failed to marshal template data: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(state.folder, deploymentStateTemplateDataFile), content, 0o600); err != nil {
+		return deploymentState{}, fmt.Errorf("failed to write template data of deployment %s: %w", state.folder, err)
+	}
+
+	return state, nil
+}
+
+// readDeploymentState reads the state of the passed artefact deployed onto the server.
+// Artefacts deployed before their state was persisted fall back to the passed current template data.
+func (d DockerBasedManager) readDeploymentState(
+	server networkmodel.ServerModel,
+	artefact uuid.UUID,
+	current templateData,
+) (deploymentState, error) {
+	state := deploymentState{folder: d.computeDeploymentStateLocation(server, artefact), data: current}
+
+	content, err := os.ReadFile(filepath.Clean(filepath.Join(state.folder, deploymentStateTemplateDataFile)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, nil
+		}
+
+		return deploymentState{}, fmt.Errorf("failed to read template data of deployment %s: %w", artefact, err)
+	}
+
+	// The template data is parsed into a new value, as parsing into the current one would merge into its variables.
+	var data templateData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return deploymentState{}, fmt.Errorf("failed to parse template data of deployment %s: %w", artefact, err)
+	}
+
+	state.data = data
+
+	return state, nil
+}
+
+// mergedFilePath yields back the path on disk of the merge result of the passed file relative to the server folder.
+func (s deploymentState) mergedFilePath(pathInServerFolder string) string {
+	return filepath.Join(s.folder, deploymentStateMergedFilesFolder, filepath.FromSlash(pathInServerFolder))
+}
+
+// writeMergedFile writes the merge result of the passed file relative to the server folder into the deployment state.
+func (s deploymentState) writeMergedFile(pathInServerFolder string, mergeResult []byte) error {
+	mergedPath := s.mergedFilePath(pathInServerFolder)
+	if err := os.MkdirAll(filepath.Dir(mergedPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory of merge result of %s: %w", pathInServerFolder, err)
+	}
+
+	if err := os.WriteFile(filepath.Clean(mergedPath), mergeResult, 0o600); err != nil {
+		return fmt.Errorf("failed to write merge result of %s: %w", pathInServerFolder, err)
+	}
+
+	return nil
+}
+
+// openMergedFile opens the merge result of the passed file relative to the server folder, reporting if the file has a
+// merge result.
+func (s deploymentState) openMergedFile(pathInServerFolder string) (*os.File, bool, error) {
+	mergedFile, err := os.Open(filepath.Clean(s.mergedFilePath(pathInServerFolder)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to open merge result of %s: %w", pathInServerFolder, err)
+	}
+
+	return mergedFile, true, nil
+}
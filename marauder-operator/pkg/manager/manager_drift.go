@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Drift compares every file of the artefacts in the IS state of the server with the server folder.
+// It fails while an update of the server is pending, as its partially updated files would be reported as drift.
+func (d DockerBasedManager) Drift(ctx context.Context, server networkmodel.ServerModel) (drift networkmodel.ServerDrift, err error) {
+	ctx, span := tracing.Start(ctx, "compute drift", serverAttributes(server)...)
+	defer func() { tracing.End(span, err) }()
+
+	// An update in progress or pending rollback would be reported as drift.
+	if err := d.checkNoPendingUpdateJournal(ctx, server); err != nil {
+		return networkmodel.ServerDrift{}, err
+	}
+
+	serverFolderLocation, err := d.computeServerFolderLocation(server)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to compute server folder location: %w", err)
+	}
+
+	artefacts, err := d.ControllerClient.FetchServerStateArtefacts(ctx, server.UUID, networkmodel.IS)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to fetch is state of server %s: %w", server.UUID.String(), err)
+	}
+
+	data, err := d.fetchTemplateData(ctx, server)
+	if err != nil {
+		return networkmodel.ServerDrift{}, fmt.Errorf("failed to fetch template data: %w", err)
+	}
+
+	drift = networkmodel.ServerDrift{
+		ServerUUID: server.UUID,
+		CheckedAt:  time.Now().UTC(),
+		Files:      make([]networkmodel.FileDrift, 0),
+	}
+
+	for _, artefact := range artefacts {
+		artefactCtx, artefactSpan := tracing.Start(ctx, "compute artefact drift", serverAttributes(
+			server,
+			attribute.String("marauder.artefact.identifier", artefact.Identifier),
+		)...)
+
+		artefactDrift, err := d.computeArtefactDrift(artefactCtx, server, artefact, serverFolderLocation, data)
+		tracing.End(artefactSpan, err)
+
+		if err != nil {
+			return networkmodel.ServerDrift{}, fmt.Errorf("failed to compute drift of %s: %w", artefact.Identifier, err)
+		}
+
+		drift.Files = append(drift.Files, artefactDrift...)
+	}
+
+	return drift, nil
+}
+
+// computeArtefactDrift computes the drift of all files of the passed artefact deployed in the server folder.
+func (d DockerBasedManager) computeArtefactDrift(
+	ctx context.Context,
+	server networkmodel.ServerModel,
+	artefact networkmodel.ArtefactModel,
+	serverFolderLocation string,
+	data templateData,
+) ([]networkmodel.FileDrift, error) {
+	artefactOnDisk, err := d.downloadArtefact(ctx, artefact.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artefact to disk: %w", err)
+	}
+
+	defer func() { _ = os.Remove(artefactOnDisk) }()
+
+	manifest, err := d.ControllerClient.FetchManifest(ctx, artefact.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artefact manifest: %w", err)
+	}
+
+	state, err := d.readDeploymentState(server, artefact.UUID, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state of artefact: %w", err)
+	}
+
+	drift, err := compareDeploymentFilesOnDisk(manifest, artefactOnDisk, serverFolderLocation, d.FileEqualityRegistry, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare artefact files with server folder: %w", err)
+	}
+
+	for index := range drift {
+		drift[index].Server = server.UUID
+		drift[index].ArtefactIdentifier = artefact.Identifier
+		drift[index].ArtefactUUID = artefact.UUID
+		drift[index].ArtefactVersion = artefact.Version
+	}
+
+	return drift, nil
+}
@@ -0,0 +1,130 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Computing drift", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+
+		spellcoreV1, settingsV1 uuid.UUID
+	)
+
+	writeServerFile := func(pathInFolder string, content string) {
+		GinkgoHelper()
+
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(serverFolder, pathInFolder)), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(serverFolder, pathInFolder), []byte(content), 0o640)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+		spellcoreV1 = controllerClient.addArtefact("spellcore", "1", map[string]string{
+			"plugins/spellcore.jar":        "spellcore 1",
+			"plugins/spellcore/config.yml": "config 1",
+		})
+		settingsV1 = controllerClient.addArtefact("settings", "1", map[string]string{
+			"plugins/settings/config.yml": "motd: hello\nslots: 20\n",
+		})
+
+		settings := controllerClient.artefacts[settingsV1]
+		settings.manifest.Files[0].Deployment = &filemodel.FileDeployment{EqualityProvider: new("yaml")}
+		controllerClient.artefacts[settingsV1] = settings
+
+		controllerClient.isStates["spellcore"] = spellcoreV1
+		controllerClient.isStates["settings"] = settingsV1
+
+		writeServerFile("plugins/spellcore.jar", "spellcore 1")
+		writeServerFile("plugins/spellcore/config.yml", "config 1")
+		writeServerFile("plugins/settings/config.yml", "slots: 20\nmotd: hello\n")
+	})
+
+	It("reports no drift for files equal to their artefact", func() {
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+
+		Expect(drift.ServerUUID).To(Equal(server.UUID))
+		Expect(drift.HasDrift()).To(BeFalse())
+	})
+
+	It("reports modified and missing files per artefact", func() {
+		writeServerFile("plugins/spellcore/config.yml", "hand edited")
+		writeServerFile("plugins/settings/config.yml", "motd: hello\nslots: 50\n")
+		Expect(os.Remove(filepath.Join(serverFolder, "plugins/spellcore.jar"))).To(Succeed())
+
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+
+		Expect(drift.Files).To(ConsistOf(
+			networkmodel.FileDrift{
+				Server: server.UUID, ArtefactIdentifier: "spellcore", ArtefactUUID: spellcoreV1, ArtefactVersion: "1",
+				Path: "plugins/spellcore.jar", Kind: networkmodel.FileDriftMissing, EqualityProvider: "hash",
+			},
+			networkmodel.FileDrift{
+				Server: server.UUID, ArtefactIdentifier: "spellcore", ArtefactUUID: spellcoreV1, ArtefactVersion: "1",
+				Path: "plugins/spellcore/config.yml", Kind: networkmodel.FileDriftModified, EqualityProvider: "hash",
+			},
+			networkmodel.FileDrift{
+				Server: server.UUID, ArtefactIdentifier: "settings", ArtefactUUID: settingsV1, ArtefactVersion: "1",
+				Path: "plugins/settings/config.yml", Kind: networkmodel.FileDriftModified, EqualityProvider: "yaml",
+			},
+		))
+	})
+
+	It("reports files that can no longer be compared as modified", func() {
+		writeServerFile("plugins/settings/config.yml", "motd: [unclosed\n")
+
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+
+		Expect(drift.Files).To(HaveLen(1))
+		Expect(drift.Files[0].Path).To(Equal("plugins/settings/config.yml"))
+		Expect(drift.Files[0].Kind).To(Equal(networkmodel.FileDriftModified))
+		Expect(drift.Files[0].Detail).To(Not(BeEmpty()))
+	})
+
+	It("ignores the keys excluded by a parametrised equality provider", func() {
+		settings := controllerClient.artefacts[settingsV1]
+		settings.manifest.Files[0].Deployment = &filemodel.FileDeployment{EqualityProvider: new("yaml?ignore=slots")}
+		controllerClient.artefacts[settingsV1] = settings
+
+		writeServerFile("plugins/settings/config.yml", "motd: hello\nslots: 50\n")
+
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(drift.HasDrift()).To(BeFalse())
+	})
+
+	It("refuses to compute drift while an update is in progress", func() {
+		Expect(os.MkdirAll(filepath.Join(serverManager.UpdateJournalPath, server.UUID.String()), 0o700)).To(Succeed())
+		unlock, err := serverManager.LockUpdateJournal(server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		defer unlock()
+
+		_, err = serverManager.Drift(context.Background(), server)
+		Expect(err).To(MatchError(ErrUpdateJournalPending))
+	})
+
+	It("replays a journal left behind before computing drift", func() {
+		Expect(os.MkdirAll(filepath.Join(serverManager.UpdateJournalPath, server.UUID.String()), 0o700)).To(Succeed())
+
+		_, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(filepath.Join(serverManager.UpdateJournalPath, server.UUID.String())).To(Not(BeADirectory()))
+	})
+})
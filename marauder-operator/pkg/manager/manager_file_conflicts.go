@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+)
+
+// findUpdateFileConflicts finds the files shipped by multiple artefacts of the server once the passed missmatches are
+// applied to its IS state.
+// Only conflicts involving an artefact installed by the missmatches that was not explicitly allowed to conflict are
+// yielded back, so conflicts already deployed onto the server do not block updates of other artefacts.
+func (d DockerBasedManager) findUpdateFileConflicts(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	missmatches []networkmodel.ArtefactVersionMissmatch,
+) ([]networkmodel.FileConflict, error) {
+	checkedIdentifiers := make([]string, 0)
+	for _, update := range missmatches {
+		if update.Missmatch.ArtefactToInstall() != nil && !update.AllowFileConflicts {
+			checkedIdentifiers = append(checkedIdentifiers, update.ArtefactIdentifier)
+		}
+	}
+
+	if len(checkedIdentifiers) == 0 {
+		return nil, nil
+	}
+
+	isArtefacts, err := d.ControllerClient.FetchServerStateArtefacts(ctx, serverModel.UUID, networkmodel.IS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch is state of %s: %w", serverModel.UUID, err)
+	}
+
+	artefactsAfterUpdate := make(map[string]uuid.UUID, len(isArtefacts))
+	for _, artefact := range isArtefacts {
+		artefactsAfterUpdate[artefact.Identifier] = artefact.UUID
+	}
+
+	for _, update := range missmatches {
+		if artefactToInstall := update.Missmatch.ArtefactToInstall(); artefactToInstall != nil {
+			artefactsAfterUpdate[update.ArtefactIdentifier] = artefactToInstall.Artefact
+		} else {
+			delete(artefactsAfterUpdate, update.ArtefactIdentifier)
+		}
+	}
+
+	manifests := make([]filemodel.Manifest, 0, len(artefactsAfterUpdate))
+	for identifier, artefact := range artefactsAfterUpdate {
+		manifest, err := d.ControllerClient.FetchManifest(ctx, artefact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch manifest of %s: %w", identifier, err)
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return slices.DeleteFunc(networkmodel.FindFileConflicts(manifests), func(conflict networkmodel.FileConflict) bool {
+		return !slices.ContainsFunc(checkedIdentifiers, conflict.Involves)
+	}), nil
+}
+
+// checkNoUpdateFileConflicts refuses an update whose artefacts would ship files shipped by other artefacts of the
+// server, as installing either would overwrite the file of the other and uninstalling either would delete it.
+func (d DockerBasedManager) checkNoUpdateFileConflicts(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	missmatches []networkmodel.ArtefactVersionMissmatch,
+) error {
+	conflicts, err := d.findUpdateFileConflicts(ctx, serverModel, missmatches)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	conflictDescriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		conflictDescriptions = append(conflictDescriptions, conflict.String())
+	}
+
+	return fmt.Errorf("files shipped by multiple artefacts %s: %w", strings.Join(conflictDescriptions, ", "), networkmodel.ErrFileConflict)
+}
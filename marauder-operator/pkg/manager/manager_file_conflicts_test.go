@@ -0,0 +1,114 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Detecting files shipped by multiple artefacts", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+		spellcore        uuid.UUID
+		spellbook        uuid.UUID
+	)
+
+	installMissmatchAllowingConflicts := func(identifier string, artefact uuid.UUID) networkmodel.ArtefactVersionMissmatch {
+		missmatch := installMissmatch(identifier, artefact)
+		missmatch.AllowFileConflicts = true
+
+		return missmatch
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+
+		spellcore = controllerClient.addArtefact("spellcore", "1", map[string]string{
+			"plugins/spellcore.jar":   "spellcore 1",
+			"plugins/shared/lang.yml": "spellcore lang",
+		})
+		spellbook = controllerClient.addArtefact("spellbook", "1", map[string]string{
+			"plugins/spellbook.jar":   "spellbook 1",
+			"plugins/shared/lang.yml": "spellbook lang",
+		})
+	})
+
+	It("refuses to install artefacts shipping the same file", func() {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			installMissmatch("spellcore", spellcore),
+			installMissmatch("spellbook", spellbook),
+		}
+
+		err := serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)
+		Expect(err).To(MatchError(networkmodel.ErrFileConflict))
+		Expect(err).To(MatchError(ContainSubstring("plugins/shared/lang.yml (spellbook, spellcore)")))
+
+		Expect(filepath.Join(serverFolder, "plugins", "spellcore.jar")).To(Not(BeAnExistingFile()))
+		Expect(filepath.Join(serverFolder, "plugins", "spellbook.jar")).To(Not(BeAnExistingFile()))
+		Expect(controllerClient.isStates).To(BeEmpty())
+	})
+
+	It("refuses to install an artefact shipping a file of an installed artefact", func() {
+		controllerClient.isStates["spellcore"] = spellcore
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("spellbook", spellbook)}
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)).To(MatchError(networkmodel.ErrFileConflict))
+		Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcore}))
+	})
+
+	It("installs conflicting artefacts if explicitly allowed", func() {
+		controllerClient.isStates["spellcore"] = spellcore
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatchAllowingConflicts("spellbook", spellbook)}
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(serverFolder, "plugins", "shared", "lang.yml"))).To(BeEquivalentTo("spellbook lang"))
+	})
+
+	It("does not consider versions of the same artefact conflicting", func() {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("spellcore", spellcore)}
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)).To(Succeed())
+
+		spellcoreUpdate := controllerClient.addArtefact("spellcore", "2", map[string]string{
+			"plugins/spellcore.jar":   "spellcore 2",
+			"plugins/shared/lang.yml": "spellcore lang",
+		})
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{{
+			ArtefactIdentifier: "spellcore",
+			Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: spellcore, Version: "1"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: spellcoreUpdate, Version: "2"},
+				},
+			},
+		}}
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)).To(Succeed())
+		Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcoreUpdate}))
+		Expect(os.ReadFile(filepath.Join(serverFolder, "plugins", "spellcore.jar"))).To(BeEquivalentTo("spellcore 2"))
+	})
+
+	It("lists the conflicts in the update plan", func() {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			installMissmatch("spellcore", spellcore),
+			installMissmatch("spellbook", spellbook),
+		}
+
+		plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, true)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(plan.FileConflicts).To(Equal([]networkmodel.FileConflict{{
+			Path:                "plugins/shared/lang.yml",
+			ArtefactIdentifiers: []string{"spellbook", "spellcore"},
+		}}))
+	})
+})
@@ -0,0 +1,152 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+)
+
+const (
+	// deployedDirectoryMode is the mode of directories created in the server folder when deploying files.
+	deployedDirectoryMode fs.FileMode = 0o700
+
+	// defaultDeployedFileMode is the mode files are deployed with if their artefact did not record their metadata.
+	defaultDeployedFileMode fs.FileMode = 0o644
+)
+
+// metadata yields back the metadata of the passed file in the artefact and if the artefact recorded it.
+func (r deploymentRenderer) metadata(pathInTarball string) (filemodel.FileMetadata, bool) {
+	fileReference, found := r.fileReferences[pathInTarball]
+	if !found {
+		return filemodel.FileMetadata{}, false
+	}
+
+	metadata, found := fileReference.MatchedFileMetadata[pathInTarball]
+
+	return metadata, found
+}
+
+// deployedMode yields back the permissions the passed file in the artefact is deployed with.
+func (r deploymentRenderer) deployedMode(pathInTarball string) fs.FileMode {
+	metadata, found := r.metadata(pathInTarball)
+	if !found {
+		return defaultDeployedFileMode
+	}
+
+	return metadata.DeployedMode()
+}
+
+// modeDrift compares the permissions of the passed file on disk with the permissions the file in the artefact is
+// deployed with, yielding back a description of the change if they differ.
+// Files of artefacts that did not record their metadata are not compared.
+func (r deploymentRenderer) modeDrift(pathInTarball string, fileOnDisk *os.File) (string, error) {
+	metadata, found := r.metadata(pathInTarball)
+	if !found {
+		return "", nil
+	}
+
+	stat, err := fileOnDisk.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file %s: %w", fileOnDisk.Name(), err)
+	}
+
+	if stat.Mode().Perm() == metadata.DeployedMode() {
+		return "", nil
+	}
+
+	return fmt.Sprintf("mode changed from %#o to %#o", metadata.DeployedMode(), stat.Mode().Perm()), nil
+}
+
+// symlinkDrift compares the symbolic link on disk with the link in the artefact pointing to the passed target, yielding
+// back a description of the change if it does not point to the target anymore.
+func symlinkDrift(serverFolder *os.Root, pathInServerFolder string, target string) (string, error) {
+	info, err := serverFolder.Lstat(pathInServerFolder)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat symlink %s: %w", pathInServerFolder, err)
+	}
+
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return "expected a symlink to " + target, nil
+	}
+
+	actualTarget, err := serverFolder.Readlink(pathInServerFolder)
+	if err != nil {
+		return "", fmt.Errorf("failed to read symlink %s: %w", pathInServerFolder, err)
+	}
+
+	if actualTarget != filepath.FromSlash(target) {
+		return fmt.Sprintf("symlink target changed from %s to %s", target, actualTarget), nil
+	}
+
+	return "", nil
+}
+
+// writeDeployedFile writes the passed content into the file at the passed path in the server folder with the passed
+// permissions. Symbolic links at the path are replaced rather than written through.
+func writeDeployedFile(serverFolder *os.Root, pathInServerFolder string, content io.Reader, mode fs.FileMode) error {
+	if err := removeSymlink(serverFolder, pathInServerFolder); err != nil {
+		return err
+	}
+
+	targetFile, err := serverFolder.OpenFile(pathInServerFolder, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open output file %s: %w", pathInServerFolder, err)
+	}
+
+	if _, err := io.Copy(targetFile, content); err != nil {
+		_ = targetFile.Close()
+		return fmt.Errorf("failed to copy over tarball content to disk file %s: %w", pathInServerFolder, err)
+	}
+
+	if err := targetFile.Close(); err != nil {
+		return fmt.Errorf("failed to close disk file %s: %w", pathInServerFolder, err)
+	}
+
+	// The mode passed when creating the file is subject to the umask and does not apply to existing files.
+	if err := serverFolder.Chmod(pathInServerFolder, mode); err != nil {
+		return fmt.Errorf("failed to set mode of disk file %s: %w", pathInServerFolder, err)
+	}
+
+	return nil
+}
+
+// writeDeployedSymlink creates a symbolic link to the passed target at the passed path in the server folder, replacing
+// any existing file.
+func writeDeployedSymlink(serverFolder *os.Root, pathInServerFolder string, target string) error {
+	if err := serverFolder.Remove(pathInServerFolder); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to replace existing file %s with symlink: %w", pathInServerFolder, err)
+	}
+
+	if err := serverFolder.Symlink(filepath.FromSlash(target), pathInServerFolder); err != nil {
+		return fmt.Errorf("failed to create symlink %s: %w", pathInServerFolder, err)
+	}
+
+	return nil
+}
+
+// removeSymlink removes the file at the passed path in the server folder if it is a symbolic link.
+func removeSymlink(serverFolder *os.Root, pathInServerFolder string) error {
+	info, err := serverFolder.Lstat(pathInServerFolder)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to stat %s: %w", pathInServerFolder, err)
+	}
+
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return nil
+	}
+
+	if err := serverFolder.Remove(pathInServerFolder); err != nil {
+		return fmt.Errorf("failed to remove symlink %s: %w", pathInServerFolder, err)
+	}
+
+	return nil
+}
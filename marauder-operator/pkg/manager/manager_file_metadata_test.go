@@ -0,0 +1,157 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deploying file metadata", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+	)
+
+	install := func(artefact uuid.UUID) {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("spellcore", artefact)}
+	}
+
+	updateDeployments := func() error {
+		return serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)
+	}
+
+	modeOf := func(pathInServerFolder string) os.FileMode {
+		GinkgoHelper()
+
+		info, err := os.Stat(filepath.Join(serverFolder, pathInServerFolder))
+		Expect(err).To(Not(HaveOccurred()))
+
+		return info.Mode().Perm()
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+	})
+
+	Describe("file modes", func() {
+		It("deploys files with their recorded mode", func() {
+			install(controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{
+				"start.sh":                     "#!/bin/sh",
+				"plugins/spellcore/config.yml": "motd: hello",
+			}, map[string]filemodel.FileMetadata{
+				"start.sh":                     {Mode: 0o755},
+				"plugins/spellcore/config.yml": {Mode: 0o666},
+			}))
+
+			Expect(updateDeployments()).To(Succeed())
+
+			Expect(modeOf("start.sh")).To(Equal(os.FileMode(0o755)))
+			Expect(modeOf("plugins/spellcore/config.yml")).To(Equal(os.FileMode(0o644)))
+			Expect(modeOf("plugins/spellcore")).To(Equal(os.FileMode(0o700)))
+		})
+
+		It("deploys files of artefacts without metadata with default permissions", func() {
+			install(controllerClient.addArtefact("spellcore", "1", map[string]string{"start.sh": "#!/bin/sh"}))
+
+			Expect(updateDeployments()).To(Succeed())
+
+			Expect(modeOf("start.sh")).To(Equal(os.FileMode(0o644)))
+		})
+
+		It("reports changed modes as drift", func() {
+			artefact := controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{"start.sh": "#!/bin/sh"},
+				map[string]filemodel.FileMetadata{"start.sh": {Mode: 0o755}})
+			install(artefact)
+			Expect(updateDeployments()).To(Succeed())
+			controllerClient.isStates["spellcore"] = artefact
+
+			Expect(os.Chmod(filepath.Join(serverFolder, "start.sh"), 0o700)).To(Succeed())
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(drift.Files).To(ConsistOf(And(
+				HaveField("Kind", networkmodel.FileDriftModified),
+				HaveField("Detail", "mode changed from 0755 to 0700"),
+			)))
+		})
+
+		It("reports changed content alongside changed modes as drift", func() {
+			artefact := controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{"start.sh": "#!/bin/sh"},
+				map[string]filemodel.FileMetadata{"start.sh": {Mode: 0o755}})
+			install(artefact)
+			Expect(updateDeployments()).To(Succeed())
+			controllerClient.isStates["spellcore"] = artefact
+
+			Expect(os.WriteFile(filepath.Join(serverFolder, "start.sh"), []byte("#!/bin/bash"), 0o700)).To(Succeed())
+			Expect(os.Chmod(filepath.Join(serverFolder, "start.sh"), 0o700)).To(Succeed())
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(drift.Files).To(ConsistOf(And(
+				HaveField("Kind", networkmodel.FileDriftModified),
+				HaveField("Detail", "content changed, mode changed from 0755 to 0700"),
+			)))
+		})
+	})
+
+	Describe("symlinks", func() {
+		It("deploys symlinks pointing inside the server folder", func() {
+			artefact := controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{
+				"libs/spellcore-1.14.jar": "plugin",
+				"plugins/spellcore.jar":   "",
+			}, map[string]filemodel.FileMetadata{
+				"libs/spellcore-1.14.jar": {Mode: 0o644},
+				"plugins/spellcore.jar":   {Mode: 0o777, SymlinkTarget: new("../libs/spellcore-1.14.jar")},
+			})
+			install(artefact)
+
+			Expect(updateDeployments()).To(Succeed())
+
+			Expect(os.Readlink(filepath.Join(serverFolder, "plugins", "spellcore.jar"))).To(Equal("../libs/spellcore-1.14.jar"))
+			Expect(os.ReadFile(filepath.Join(serverFolder, "plugins", "spellcore.jar"))).To(BeEquivalentTo("plugin"))
+
+			controllerClient.isStates["spellcore"] = artefact
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(drift.HasDrift()).To(BeFalse())
+		})
+
+		It("reports symlinks pointing elsewhere as drift", func() {
+			artefact := controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{"plugins/spellcore.jar": ""},
+				map[string]filemodel.FileMetadata{"plugins/spellcore.jar": {Mode: 0o777, SymlinkTarget: new("../libs/spellcore-1.14.jar")}})
+			install(artefact)
+			Expect(updateDeployments()).To(Succeed())
+			controllerClient.isStates["spellcore"] = artefact
+
+			linkPath := filepath.Join(serverFolder, "plugins", "spellcore.jar")
+			Expect(os.Remove(linkPath)).To(Succeed())
+			Expect(os.Symlink("../libs/spellcore-1.15.jar", linkPath)).To(Succeed())
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(drift.Files).To(ConsistOf(HaveField("Kind", networkmodel.FileDriftModified)))
+		})
+
+		It("refuses symlinks pointing outside the server folder", func() {
+			install(controllerClient.addArtefactWithMetadata("spellcore", "1", map[string]string{"plugins/spellcore.jar": ""},
+				map[string]filemodel.FileMetadata{"plugins/spellcore.jar": {Mode: 0o777, SymlinkTarget: new("../../../spellcore.jar")}}))
+
+			Expect(updateDeployments()).To(MatchError(utils.ErrUnsafeSymlink))
+
+			_, err := os.Lstat(filepath.Join(serverFolder, "plugins", "spellcore.jar"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+})
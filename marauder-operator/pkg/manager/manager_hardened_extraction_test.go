@@ -0,0 +1,114 @@
+package manager_test
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extracting artefacts into the server folder", Label("unittest"), func() {
+	var (
+		root             string
+		outsideFolder    string
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+	)
+
+	install := func(artefact uuid.UUID) {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("spellcore", artefact)}
+	}
+
+	uninstall := func(artefact uuid.UUID) {
+		controllerClient.isStates["spellcore"] = artefact
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "spellcore", Missmatch: networkmodel.ArtefactMissmatch{
+				Uninstall: &networkmodel.ArtefactVersionMissmatchUninstall{
+					Is: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: artefact, Version: "1"},
+				},
+			}},
+		}
+	}
+
+	updateDeployments := func() error {
+		return serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, false)
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+
+		outsideFolder = filepath.Join(root, "outside")
+		Expect(os.MkdirAll(outsideFolder, 0o700)).To(Succeed())
+	})
+
+	Describe("malicious tarballs", func() {
+		for name, entry := range map[string]tar.Header{
+			"parent traversal": {Name: "files/../../../outside/escape.sh", Typeflag: tar.TypeReg, Mode: 0o644},
+			"hardlink":         {Name: "files/escape.sh", Typeflag: tar.TypeLink, Linkname: "../../../outside/escape.sh"},
+			"absolute symlink": {Name: "files/plugins", Typeflag: tar.TypeSymlink, Linkname: "/"},
+			"escaping symlink": {Name: "files/plugins", Typeflag: tar.TypeSymlink, Linkname: "../../../outside"},
+		} {
+			It("refuses an artefact with a "+name, func() {
+				install(controllerClient.addArtefactWithEntries("spellcore", "1", []tar.Header{
+					{Name: "files/plugins/spellcore.jar", Typeflag: tar.TypeReg, Mode: 0o644},
+					entry,
+				}))
+
+				Expect(updateDeployments()).To(MatchError(utils.ErrUnsafeArtefactEntry))
+				Expect(filepath.Join(serverFolder, "plugins", "spellcore.jar")).To(Not(BeAnExistingFile()))
+				Expect(os.ReadDir(outsideFolder)).To(BeEmpty())
+			})
+		}
+
+		It("ignores entries outside the files folder", func() {
+			install(controllerClient.addArtefactWithEntries("spellcore", "1", []tar.Header{
+				{Name: "files/plugins/spellcore.jar", Typeflag: tar.TypeReg, Mode: 0o644},
+				{Name: "../outside/escape.sh", Typeflag: tar.TypeReg, Mode: 0o644},
+			}))
+
+			Expect(updateDeployments()).To(Succeed())
+			Expect(filepath.Join(serverFolder, "plugins", "spellcore.jar")).To(BeAnExistingFile())
+			Expect(os.ReadDir(outsideFolder)).To(BeEmpty())
+		})
+	})
+
+	Describe("symlinked directories on disk", func() {
+		BeforeEach(func() {
+			Expect(os.Symlink(outsideFolder, filepath.Join(serverFolder, "plugins"))).To(Succeed())
+		})
+
+		It("does not write through them", func() {
+			install(controllerClient.addArtefact("spellcore", "1", map[string]string{"plugins/spellcore.jar": "plugin"}))
+
+			Expect(updateDeployments()).To(HaveOccurred())
+			Expect(os.ReadDir(outsideFolder)).To(BeEmpty())
+		})
+
+		It("does not delete through them", func() {
+			Expect(os.WriteFile(filepath.Join(outsideFolder, "spellcore.jar"), []byte("plugin"), 0o600)).To(Succeed())
+			uninstall(controllerClient.addArtefact("spellcore", "1", map[string]string{"plugins/spellcore.jar": "plugin"}))
+
+			Expect(updateDeployments()).To(HaveOccurred())
+			Expect(filepath.Join(outsideFolder, "spellcore.jar")).To(BeAnExistingFile())
+		})
+
+		It("does not compare files through them", func() {
+			Expect(os.WriteFile(filepath.Join(outsideFolder, "spellcore.jar"), []byte("plugin"), 0o600)).To(Succeed())
+			controllerClient.isStates["spellcore"] = controllerClient.addArtefact("spellcore", "1", map[string]string{"plugins/spellcore.jar": "plugin"})
+
+			_, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// ErrServerContainerNotFound is returned if no container exists for a server.
+var ErrServerContainerNotFound = errors.New("server container not found")
+
+func (d DockerBasedManager) Logs(ctx context.Context, server networkmodel.ServerModel, options networkmodel.ServerLogOptions) (io.ReadCloser, error) {
+	// Containers are created with a tty, so the logs are not multiplexed and can be streamed as is.
+	logs, err := d.DockerClient.ContainerLogs(ctx, d.computeUniqueDockerContainerNameFor(server), container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      options.Since,
+		Tail:       options.Tail,
+		Follow:     options.Follow,
+	})
+	if err != nil {
+		if utils.CheckDockerError(err, errdefs.IsNotFound) {
+			return nil, fmt.Errorf("container of server %s: %w", server.UUID, ErrServerContainerNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to request container logs: %w", err)
+	}
+
+	return logs, nil
+}
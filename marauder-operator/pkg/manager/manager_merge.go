@@ -0,0 +1,175 @@
+package manager
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// The pendingMerge holds the versions of a file that are merged with the file of the artefact to install.
+// They are read before the files of the artefact to uninstall are deleted.
+type pendingMerge struct {
+	merge filemerge.FileMerge
+
+	// base is the rendered file of the artefact to uninstall.
+	base []byte
+
+	// local is the file on disk.
+	local []byte
+}
+
+// The deploymentMerger merges the files of an artefact to install with their pending merges.
+// Merge results are recorded in the deployment state of the artefact.
+type deploymentMerger struct {
+	pendingMerges   map[string]pendingMerge
+	failOnConflicts bool
+	state           deploymentState
+}
+
+// newDeploymentMerger creates a merger for the files of an artefact with the passed pending merges and deployment state.
+func newDeploymentMerger(pendingMerges map[string]pendingMerge, failOnConflicts bool, state deploymentState) deploymentMerger {
+	return deploymentMerger{
+		pendingMerges:   pendingMerges,
+		failOnConflicts: failOnConflicts,
+		state:           state,
+	}
+}
+
+// collectPendingMerges reads the base and local versions of all files that the artefact to install deploys with a merge
+// provider and that are deployed by the artefact to uninstall as well as present on disk.
+// Local files are read through the server folder as a root, so symbolic links on disk cannot merge files outside of it.
+func (d DockerBasedManager) collectPendingMerges(
+	artefactToUninstall filemodel.Manifest,
+	artefactToUninstallOnDisk string,
+	artefactToInstall filemodel.Manifest,
+	serverFolderLocation string,
+	data templateData,
+) (map[string]pendingMerge, error) {
+	pendingMerges := make(map[string]pendingMerge)
+	newFileReferences := artefactToInstall.Files.MatchedFilesToReferenceMap()
+
+	tarballReader, err := utils.NewFriendlyTarballReaderFromPath(artefactToUninstallOnDisk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artefact tarball: %w", err)
+	}
+
+	defer func() { _ = tarballReader.Close(true) }()
+
+	serverFolder, err := os.OpenRoot(serverFolderLocation)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return pendingMerges, nil
+		}
+
+		return nil, fmt.Errorf("failed to open server folder %s: %w", serverFolderLocation, err)
+	}
+
+	defer utils.SwallowClose(serverFolder)
+
+	renderer := newDeploymentRenderer(artefactToUninstall, data)
+	for {
+		header, err := tarballReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return pendingMerges, nil
+			}
+
+			return nil, fmt.Errorf("failed to read next header from artefact: %w", err)
+		}
+
+		newFileReference, found := newFileReferences[header.Name]
+		if _, tracked := renderer.fileReferences[header.Name]; !found || !tracked || header.Typeflag == tar.TypeSymlink ||
+			newFileReference.Deployment == nil || newFileReference.Deployment.MergeProvider == nil {
+			continue
+		}
+
+		merge, found := d.FileMergeRegistry[*newFileReference.Deployment.MergeProvider]
+		if !found {
+			return nil, fmt.Errorf("%s is an unknown file merge: %w", *newFileReference.Deployment.MergeProvider, filemerge.ErrUnknownFileMerge)
+		}
+
+		filePathInServerFolder, err := utils.ArtefactPathInServerFolder(header.Name)
+		if err != nil {
+			return nil, fmt.Errorf("refusing to merge file %s: %w", header.Name, err)
+		}
+
+		local, err := serverFolder.ReadFile(filepath.FromSlash(filePathInServerFolder))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read local file %s: %w", filePathInServerFolder, err)
+		}
+
+		baseReader, err := renderer.render(header.Name, tarballReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render base file %s: %w", filePathInServerFolder, err)
+		}
+
+		base, err := io.ReadAll(baseReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read base file %s: %w", filePathInServerFolder, err)
+		}
+
+		pendingMerges[header.Name] = pendingMerge{merge: merge, base: base, local: local}
+	}
+}
+
+// merge merges the passed rendered file of the artefact to install with its pending merge.
+// It yields back the content to deploy as well as the merge result drift detection compares the file against, which is
+// nil if the file is deployed as found in the artefact.
+// Conflicts are resolved with the new version unless the merger fails on conflicts. Files that cannot be merged at all,
+// e.g. as the local file no longer parses, always fail the merge, as deploying the new version would discard the local
+// modifications without a trace.
+func (m deploymentMerger) merge(pathInTarball string, target io.Reader) (io.Reader, []byte, error) {
+	pending, found := m.pendingMerges[pathInTarball]
+	if !found {
+		return target, nil, nil
+	}
+
+	targetContent, err := io.ReadAll(target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read target file: %w", err)
+	}
+
+	result, err := pending.merge.Merge(pending.base, pending.local, targetContent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge %s: %w", pathInTarball, err)
+	}
+
+	if len(result.Conflicts) > 0 {
+		if m.failOnConflicts {
+			return nil, nil, fmt.Errorf("keys %s of %s: %w", strings.Join(result.Conflicts, ", "), pathInTarball, filemerge.ErrMergeConflict)
+		}
+
+		logrus.Warnf("resolved conflicting keys %s of %s with the new version", strings.Join(result.Conflicts, ", "), pathInTarball)
+	}
+
+	if bytes.Equal(result.Content, targetContent) {
+		return bytes.NewReader(targetContent), nil, nil
+	}
+
+	return bytes.NewReader(result.Content), result.Content, nil
+}
+
+// recordMergeResult records the passed merge result of the file at the passed path relative to the server folder in the
+// deployment state. A nil merge result, yielded back for files deployed as found in the artefact, is not recorded.
+func (m deploymentMerger) recordMergeResult(pathInServerFolder string, mergeResult []byte) error {
+	if mergeResult == nil {
+		return nil
+	}
+
+	return m.state.writeMergedFile(pathInServerFolder, mergeResult)
+}
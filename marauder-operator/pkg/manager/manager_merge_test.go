@@ -0,0 +1,207 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/filemerge"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merging files on update", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+
+		configV1, configV2 uuid.UUID
+	)
+
+	configFile := func() string {
+		return filepath.Join(serverFolder, "plugins", "spellcore", "config.yml")
+	}
+
+	mergedConfigFile := func() string {
+		return filepath.Join(serverManager.DeploymentStatePath, server.UUID.String(), configV2.String(), "merged", "plugins", "spellcore", "config.yml")
+	}
+
+	writeConfigFile := func(content string) {
+		GinkgoHelper()
+
+		Expect(os.MkdirAll(filepath.Dir(configFile()), 0o700)).To(Succeed())
+		Expect(os.WriteFile(configFile(), []byte(content), 0o640)).To(Succeed())
+	}
+
+	readConfigFile := func() string {
+		GinkgoHelper()
+
+		content, err := os.ReadFile(configFile())
+		Expect(err).To(Not(HaveOccurred()))
+
+		return string(content)
+	}
+
+	addConfigArtefact := func(version string, content string) uuid.UUID {
+		GinkgoHelper()
+
+		artefactUUID := controllerClient.addArtefact("config", version, map[string]string{"plugins/spellcore/config.yml": content})
+
+		artefact := controllerClient.artefacts[artefactUUID]
+		artefact.manifest.Files[0].Deployment = &filemodel.FileDeployment{EqualityProvider: new("yaml"), MergeProvider: new("yaml")}
+		controllerClient.artefacts[artefactUUID] = artefact
+
+		return artefactUUID
+	}
+
+	updateDeployments := func(failOnUnexpectedOldFilesOnDisk bool) error {
+		return serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, failOnUnexpectedOldFilesOnDisk)
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+		configV1 = addConfigArtefact("1", "motd: hello\nslots: 20\n")
+		configV2 = addConfigArtefact("2", "# new defaults\nmotd: hello\nslots: 30\npvp: true\n")
+
+		controllerClient.isStates["config"] = configV1
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "config", Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV1, Version: "1"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV2, Version: "2"},
+				},
+			}},
+		}
+	})
+
+	It("deploys the new version as is if the file was not modified", func() {
+		writeConfigFile("motd: hello\nslots: 20\n")
+
+		Expect(updateDeployments(true)).To(Succeed())
+
+		Expect(readConfigFile()).To(Equal("# new defaults\nmotd: hello\nslots: 30\npvp: true\n"))
+		Expect(mergedConfigFile()).To(Not(BeAnExistingFile()))
+	})
+
+	It("keeps local changes to keys the new version did not touch", func() {
+		writeConfigFile("motd: welcome\nslots: 20\n")
+
+		Expect(updateDeployments(true)).To(Succeed())
+
+		Expect(readConfigFile()).To(MatchYAML("motd: welcome\nslots: 30\npvp: true\n"))
+		Expect(os.ReadFile(mergedConfigFile())).To(BeEquivalentTo(readConfigFile()))
+		Expect(controllerClient.isStates).To(HaveKeyWithValue("config", configV2))
+
+		// The merge result is kept outside the server folder, which only holds the deployed files.
+		Expect(os.ReadDir(serverFolder)).To(ConsistOf(HaveField("Name()", "plugins")))
+	})
+
+	It("compares drift against the merge result", func() {
+		writeConfigFile("motd: welcome\nslots: 20\n")
+		Expect(updateDeployments(true)).To(Succeed())
+
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(drift.HasDrift()).To(BeFalse())
+
+		writeConfigFile("motd: welcome back\nslots: 30\npvp: true\n")
+
+		drift, err = serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(drift.Files).To(ConsistOf(HaveField("Kind", networkmodel.FileDriftModified)))
+	})
+
+	It("fails the update on conflicts and rolls back", func() {
+		writeConfigFile("motd: hello\nslots: 50\n")
+
+		Expect(updateDeployments(true)).To(MatchError(filemerge.ErrMergeConflict))
+
+		Expect(readConfigFile()).To(Equal("motd: hello\nslots: 50\n"))
+		Expect(mergedConfigFile()).To(Not(BeAnExistingFile()))
+		Expect(controllerClient.isStates).To(HaveKeyWithValue("config", configV1))
+	})
+
+	It("resolves conflicts with the new version if the update does not fail on local modifications", func() {
+		writeConfigFile("motd: welcome\nslots: 50\n")
+
+		Expect(updateDeployments(false)).To(Succeed())
+
+		Expect(readConfigFile()).To(MatchYAML("motd: welcome\nslots: 30\npvp: true\n"))
+	})
+
+	It("fails the update if the local file cannot be merged", func() {
+		writeConfigFile("motd: [welcome\n")
+
+		Expect(updateDeployments(false)).To(MatchError(ContainSubstring("failed to merge")))
+
+		Expect(readConfigFile()).To(Equal("motd: [welcome\n"))
+		Expect(controllerClient.isStates).To(HaveKeyWithValue("config", configV1))
+	})
+
+	It("plans the merge of modified files", func() {
+		writeConfigFile("motd: welcome\nslots: 20\n")
+
+		plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, true)
+		Expect(err).To(Not(HaveOccurred()))
+
+		Expect(plan.FailsOnLocalModifications).To(BeFalse())
+		Expect(plan.Artefacts).To(ConsistOf(HaveField("Files", Equal([]networkmodel.FileUpdatePlan{
+			{Path: "plugins/spellcore/config.yml", Change: networkmodel.FileUpdateMerge},
+		}))))
+	})
+
+	It("keeps the merge result of the installed version if a later update is rolled back", func() {
+		writeConfigFile("motd: welcome\nslots: 20\n")
+		Expect(updateDeployments(true)).To(Succeed())
+		merged, err := os.ReadFile(mergedConfigFile())
+		Expect(err).To(Not(HaveOccurred()))
+
+		configV3 := addConfigArtefact("3", "# new defaults\nmotd: hello\nslots: 40\npvp: true\n")
+		controllerClient.failUpdateStateOf["config"] = true
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "config", Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV2, Version: "2"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV3, Version: "3"},
+				},
+			}},
+		}
+
+		Expect(updateDeployments(true)).To(Not(Succeed()))
+
+		Expect(os.ReadFile(mergedConfigFile())).To(Equal(merged))
+		Expect(os.ReadDir(filepath.Join(serverManager.DeploymentStatePath, server.UUID.String()))).To(ConsistOf(
+			HaveField("Name()", configV2.String()),
+		))
+
+		drift, err := serverManager.Drift(context.Background(), server)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(drift.HasDrift()).To(BeFalse())
+	})
+
+	It("removes the merge result when the file is uninstalled", func() {
+		writeConfigFile("motd: welcome\nslots: 20\n")
+		Expect(updateDeployments(true)).To(Succeed())
+
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "config", Missmatch: networkmodel.ArtefactMissmatch{
+				Uninstall: &networkmodel.ArtefactVersionMissmatchUninstall{
+					Is: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: configV2, Version: "2"},
+				},
+			}},
+		}
+
+		Expect(updateDeployments(true)).To(Succeed())
+
+		Expect(configFile()).To(Not(BeAnExistingFile()))
+		Expect(mergedConfigFile()).To(Not(BeAnExistingFile()))
+	})
+})
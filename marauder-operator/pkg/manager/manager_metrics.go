@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/knockturnmc/marauder/marauder-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ContainerLabelServer is the label holding the uuid of the server a container was created for.
+	ContainerLabelServer = "com.knockturnmc.marauder.server"
+
+	// ContainerLabelEnvironment is the label holding the environment of the server a container was created for.
+	ContainerLabelEnvironment = "com.knockturnmc.marauder.environment"
+
+	// ContainerLabelName is the label holding the name of the server a container was created for.
+	ContainerLabelName = "com.knockturnmc.marauder.name"
+)
+
+// containerStatsTimeout defines the timeout for collecting the statistics of all managed containers.
+const containerStatsTimeout = 10 * time.Second
+
+// The ContainerStatsCollector collects cpu and memory statistics of all containers managed by the operator via the docker stats API.
+type ContainerStatsCollector struct {
+	dockerClient *dockerClient.Client
+
+	cpuUsage    *prometheus.Desc
+	memoryUsage *prometheus.Desc
+	memoryLimit *prometheus.Desc
+}
+
+// NewContainerStatsCollector creates a new collector for the statistics of containers managed by the operator.
+func NewContainerStatsCollector(client *dockerClient.Client) *ContainerStatsCollector {
+	labels := []string{"environment", "name"}
+
+	return &ContainerStatsCollector{
+		dockerClient: client,
+		cpuUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "container", "cpu_usage_seconds_total"),
+			"Total cpu time consumed by the server container.",
+			labels, nil,
+		),
+		memoryUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "container", "memory_usage_bytes"),
+			"Current memory usage of the server container.",
+			labels, nil,
+		),
+		memoryLimit: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "container", "memory_limit_bytes"),
+			"Memory limit of the server container.",
+			labels, nil,
+		),
+	}
+}
+
+func (c *ContainerStatsCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.cpuUsage
+	descs <- c.memoryUsage
+	descs <- c.memoryLimit
+}
+
+func (c *ContainerStatsCollector) Collect(metricsChan chan<- prometheus.Metric) {
+	ctx, cancelFunction := context.WithTimeout(context.Background(), containerStatsTimeout)
+	defer cancelFunction()
+
+	containers, err := c.dockerClient.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", ContainerLabelServer)),
+	})
+	if err != nil {
+		logrus.Warn("failed to list managed containers for metrics ", err)
+		return
+	}
+
+	for _, summary := range containers {
+		stats, err := c.fetchContainerStats(ctx, summary.ID)
+		if err != nil {
+			logrus.Warn("failed to fetch container stats for metrics ", err)
+			continue
+		}
+
+		environment := summary.Labels[ContainerLabelEnvironment]
+		name := summary.Labels[ContainerLabelName]
+		metricsChan <- prometheus.MustNewConstMetric(
+			c.cpuUsage, prometheus.CounterValue, float64(stats.CPUStats.CPUUsage.TotalUsage)/float64(time.Second), environment, name,
+		)
+		metricsChan <- prometheus.MustNewConstMetric(c.memoryUsage, prometheus.GaugeValue, float64(stats.MemoryStats.Usage), environment, name)
+		metricsChan <- prometheus.MustNewConstMetric(c.memoryLimit, prometheus.GaugeValue, float64(stats.MemoryStats.Limit), environment, name)
+	}
+}
+
+// fetchContainerStats fetches a single snapshot of the statistics of the passed container.
+func (c *ContainerStatsCollector) fetchContainerStats(ctx context.Context, containerID string) (container.StatsResponse, error) {
+	statsReader, err := c.dockerClient.ContainerStatsOneShot(ctx, containerID)
+	if err != nil {
+		return container.StatsResponse{}, fmt.Errorf("failed to request stats of container %s: %w", containerID, err)
+	}
+
+	defer func() { _ = statsReader.Body.Close() }()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(statsReader.Body).Decode(&stats); err != nil {
+		return container.StatsResponse{}, fmt.Errorf("failed to decode stats of container %s: %w", containerID, err)
+	}
+
+	return stats, nil
+}
@@ -31,6 +31,11 @@ func (d DockerBasedManager) Start(ctx context.Context, server networkmodel.Serve
 		return fmt.Errorf("failed to retrieve the container information: %w", err)
 	}
 
+	// A pending journal indicates an update in progress or an interrupted update, leaving the server folder inconsistent.
+	if err := d.checkNoPendingUpdateJournal(ctx, server); err != nil {
+		return err
+	}
+
 	if err := d.ensureLocalImageExists(ctx, server.Image); err != nil {
 		return fmt.Errorf("failed to ensure local server image exists: %w", err)
 	}
@@ -60,6 +65,12 @@ func (d DockerBasedManager) Start(ctx context.Context, server networkmodel.Serve
 		return err
 	}
 
+	if d.StartTimeout > 0 && strings.TrimSpace(server.Image) != "" {
+		if err := d.awaitRunning(ctx, server); err != nil {
+			return fmt.Errorf("failed to await server running: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -99,6 +110,11 @@ func (d DockerBasedManager) starDockerContainer(ctx context.Context, server netw
 			Tty:          true,
 			OpenStdin:    true,
 			Env:          environment,
+			Labels: map[string]string{
+				ContainerLabelServer:      server.UUID.String(),
+				ContainerLabelEnvironment: server.Environment,
+				ContainerLabelName:        server.Name,
+			},
 		},
 		&container.HostConfig{
 			Mounts: []mount.Mount{{
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/knockturnmc/marauder/marauder-proto/src/main/golang/marauderpb"
+)
+
+// ErrServerNotRunningInTime is returned if a started server did not report itself as running in time.
+var ErrServerNotRunningInTime = errors.New("server not running in time")
+
+// ErrServerContainerExited is returned if the container of a started server exited before the server reported itself as running.
+var ErrServerContainerExited = errors.New("server container exited")
+
+// statusPollInterval defines the interval in which the status of a starting server is polled.
+const statusPollInterval = 2 * time.Second
+
+// managementStatusTimeout defines the timeout for a single status request over the management socket.
+const managementStatusTimeout = 5 * time.Second
+
+func (d DockerBasedManager) Status(ctx context.Context, server networkmodel.ServerModel) (networkmodel.ServerRuntimeStatus, error) {
+	status := networkmodel.ServerRuntimeStatus{
+		ServerUUID:     server.UUID,
+		Environment:    server.Environment,
+		Name:           server.Name,
+		ContainerState: networkmodel.ContainerStateMissing,
+	}
+
+	containerInfo, err := d.retrieveContainerInfo(ctx, server)
+	if err != nil {
+		if utils.CheckDockerError(err, errdefs.IsNotFound) {
+			return status, nil
+		}
+
+		return networkmodel.ServerRuntimeStatus{}, fmt.Errorf("failed to retrieve the container information: %w", err)
+	}
+
+	if containerInfo.State != nil {
+		status.ContainerState = containerInfo.State.Status
+		if startedAt, err := time.Parse(time.RFC3339Nano, containerInfo.State.StartedAt); err == nil {
+			status.ContainerStartedAt = &startedAt
+		}
+	}
+
+	if status.ContainerState != networkmodel.ContainerStateRunning || server.ManagementSocketPath == "" {
+		return status, nil
+	}
+
+	withTimeout, cancelFunction := context.WithTimeout(ctx, managementStatusTimeout)
+	defer cancelFunction()
+
+	var statusResponse marauderpb.ServerStatusRequest_Response
+	if err := d.ExchangeManagementMessage(withTimeout, server, &marauderpb.ServerStatusRequest{}, &statusResponse); err != nil {
+		status.ManagementError = new(err.Error())
+		return status, nil
+	}
+
+	status.ManagementStatus = new(statusResponse.GetStatus().String())
+
+	return status, nil
+}
+
+// awaitRunning polls the status of the passed server until it reports itself as running or the start timeout is exceeded.
+// Exceeding the deadline of the passed context is reported as ErrServerNotRunningInTime as well.
+func (d DockerBasedManager) awaitRunning(ctx context.Context, server networkmodel.ServerModel) error {
+	startTimeout := time.NewTimer(d.StartTimeout)
+	defer startTimeout.Stop()
+
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := d.Status(ctx, server)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return fmt.Errorf("server did not report running before the deadline: %w: %w", ErrServerNotRunningInTime, err)
+			}
+
+			return fmt.Errorf("failed to fetch server status: %w", err)
+		}
+
+		if status.IsRunning(server) {
+			return nil
+		}
+
+		switch status.ContainerState {
+		case container.StateRunning, container.StateCreated, container.StateRestarting:
+		default:
+			return fmt.Errorf("container is %s: %w", status.ContainerState, ErrServerContainerExited)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("server did not report running before the deadline: %w: %w", ErrServerNotRunningInTime, ctx.Err())
+			}
+
+			return fmt.Errorf("failed to await server running: %w", ctx.Err())
+		case <-startTimeout.C:
+			return fmt.Errorf("server did not report running within %s: %w", d.StartTimeout, ErrServerNotRunningInTime)
+		case <-ticker.C:
+		}
+	}
+}
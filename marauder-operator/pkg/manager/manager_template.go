@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+// The templateData is the data files of artefacts deployed as templates are executed with.
+type templateData struct {
+	networkmodel.ServerModel
+
+	// Variables holds the variables defined for the server on the controller.
+	Variables map[string]string `json:"variables"`
+}
+
+// fetchTemplateData fetches the data files deployed as templates onto the passed server are executed with.
+func (d DockerBasedManager) fetchTemplateData(ctx context.Context, server networkmodel.ServerModel) (templateData, error) {
+	variables, err := d.ControllerClient.FetchServerVariables(ctx, server.UUID)
+	if err != nil {
+		return templateData{}, fmt.Errorf("failed to fetch variables of server %s: %w", server.UUID.String(), err)
+	}
+
+	data := templateData{ServerModel: server, Variables: make(map[string]string, len(variables))}
+	for _, variable := range variables {
+		data.Variables[variable.Key] = variable.Value
+	}
+
+	return data, nil
+}
+
+// The deploymentRenderer renders the files of a single artefact as they are deployed onto a server.
+type deploymentRenderer struct {
+	fileReferences map[string]*filemodel.FileReference
+	data           templateData
+}
+
+// newDeploymentRenderer creates a renderer for the files of the artefact with the passed manifest.
+func newDeploymentRenderer(manifest filemodel.Manifest, data templateData) deploymentRenderer {
+	return deploymentRenderer{fileReferences: manifest.Files.MatchedFilesToReferenceMap(), data: data}
+}
+
+// mode yields back the deployment mode of the passed file in the artefact.
+func (r deploymentRenderer) mode(pathInTarball string) filemodel.FileDeploymentMode {
+	return deploymentModeOf(r.fileReferences, pathInTarball)
+}
+
+// render yields back the content of the passed file in the artefact as deployed onto the server.
+// Files marked as templates are rendered, all other files are yielded back as is.
+func (r deploymentRenderer) render(pathInTarball string, content io.Reader) (io.Reader, error) {
+	fileReference, found := r.fileReferences[pathInTarball]
+	if !found || fileReference.Deployment == nil || !fileReference.Deployment.Template {
+		return content, nil
+	}
+
+	rendered, err := utils.ExecuteFileTemplate(pathInTarball, content, r.data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return bytes.NewReader(rendered), nil
+}
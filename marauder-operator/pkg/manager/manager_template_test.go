@@ -0,0 +1,129 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deploying templates", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+
+		motdV1, motdV2 uuid.UUID
+	)
+
+	configFile := func() string {
+		return filepath.Join(serverFolder, "plugins", "motd", "config.yml")
+	}
+
+	addTemplateArtefact := func(version string, content string) uuid.UUID {
+		GinkgoHelper()
+
+		artefactUUID := controllerClient.addArtefact("motd", version, map[string]string{"plugins/motd/config.yml": content})
+
+		artefact := controllerClient.artefacts[artefactUUID]
+		artefact.manifest.Files[0].Deployment = &filemodel.FileDeployment{Template: true}
+		controllerClient.artefacts[artefactUUID] = artefact
+
+		return artefactUUID
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+		server.Port = 25565
+		controllerClient.variables["region"] = "eu"
+
+		motdV1 = addTemplateArtefact("1", "name: {{.Name}}\nport: {{.Port}}\nregion: {{.Variables.region}}\n")
+		motdV2 = addTemplateArtefact("2", "name: {{.Name}}\nregion: {{.Variables.region}}\n")
+	})
+
+	It("renders templates with the server and its variables on install", func() {
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("motd", motdV1)}
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+
+		Expect(os.ReadFile(configFile())).To(BeEquivalentTo("name: lobby\nport: 25565\nregion: eu\n"))
+	})
+
+	It("fails the update if a template references an unknown variable", func() {
+		delete(controllerClient.variables, "region")
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("motd", motdV1)}
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(MatchError(ContainSubstring("render")))
+
+		Expect(configFile()).To(Not(BeAnExistingFile()))
+		Expect(controllerClient.isStates).To(BeEmpty())
+	})
+
+	Describe("with an installed template", func() {
+		BeforeEach(func() {
+			controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{installMissmatch("motd", motdV1)}
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+		})
+
+		updateMissmatch := func() networkmodel.ArtefactVersionMissmatch {
+			return networkmodel.ArtefactVersionMissmatch{ArtefactIdentifier: "motd", Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: motdV1, Version: "1"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: motdV2, Version: "2"},
+				},
+			}}
+		}
+
+		It("compares the old files against their rendered output when updating", func() {
+			controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{updateMissmatch()}
+
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("name: lobby\nregion: eu\n"))
+		})
+
+		It("compares the old files against the variables they were rendered with when updating", func() {
+			controllerClient.variables["region"] = "us"
+			controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{updateMissmatch()}
+
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+
+			Expect(os.ReadFile(configFile())).To(BeEquivalentTo("name: lobby\nregion: us\n"))
+			Expect(filepath.Join(serverManager.DeploymentStatePath, server.UUID.String(), motdV1.String())).To(Not(BeAnExistingFile()))
+		})
+
+		It("reports no drift for a rendered template", func() {
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(drift.HasDrift()).To(BeFalse())
+		})
+
+		It("reports no drift if a variable changed since the template was rendered", func() {
+			controllerClient.variables["region"] = "us"
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(drift.HasDrift()).To(BeFalse())
+		})
+
+		It("reports drift if a rendered template was modified", func() {
+			Expect(os.WriteFile(configFile(), []byte("name: lobby\nport: 25565\nregion: us\n"), 0o600)).To(Succeed())
+
+			drift, err := serverManager.Drift(context.Background(), server)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(drift.Files).To(ConsistOf(HaveField("Kind", networkmodel.FileDriftModified)))
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package manager
+
+import (
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// serverAttributes computes the span attributes identifying the passed server.
+func serverAttributes(server networkmodel.ServerModel, additional ...attribute.KeyValue) []attribute.KeyValue {
+	return append([]attribute.KeyValue{
+		attribute.String("marauder.server.uuid", server.UUID.String()),
+		attribute.String("marauder.server.environment", server.Environment),
+		attribute.String("marauder.server.name", server.Name),
+	}, additional...)
+}
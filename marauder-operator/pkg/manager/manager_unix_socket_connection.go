@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-proto/src/main/golang/marauderpb"
 	"google.golang.org/protobuf/proto"
@@ -39,6 +40,24 @@ func (d DockerBasedManager) ExchangeManagementMessage(
 	model networkmodel.ServerModel,
 	outgoing proto.Message,
 	incoming proto.Message,
+) error {
+	messageName := string(outgoing.ProtoReflect().Descriptor().Name())
+	ctx, span := tracing.Start(ctx, "management exchange "+messageName, serverAttributes(model)...)
+
+	exchangeStart := time.Now()
+	err := d.exchangeManagementMessage(ctx, model, outgoing, incoming)
+	d.Metrics.ObserveManagementExchange(model, messageName, err, time.Since(exchangeStart))
+	tracing.End(span, err)
+
+	return err
+}
+
+// exchangeManagementMessage implements the actual exchange of ExchangeManagementMessage.
+func (d DockerBasedManager) exchangeManagementMessage(
+	ctx context.Context,
+	model networkmodel.ServerModel,
+	outgoing proto.Message,
+	incoming proto.Message,
 ) error {
 	if model.ManagementSocketPath == "" {
 		return ErrServerWithoutManagementSocket
@@ -58,7 +77,7 @@ func (d DockerBasedManager) ExchangeManagementMessage(
 
 	defer func() { utils.Swallow(conn.Close()) }()
 
-	if err := writeProtoMessage(conn, outgoing); err != nil {
+	if err := writeProtoMessage(conn, outgoing, tracing.InjectMap(ctx)); err != nil {
 		return fmt.Errorf("failed to write proto message: %w", err)
 	}
 
@@ -90,7 +109,8 @@ func openUnixSocketConn(d DockerBasedManager, model networkmodel.ServerModel) (n
 }
 
 // writeProtoMessage marshals a Protobuf message and writes it to the provided writer.
-func writeProtoMessage[T proto.Message](writer io.Writer, outgoing T) error {
+// The passed trace context is sent alongside the message, allowing the server to continue the trace.
+func writeProtoMessage[T proto.Message](writer io.Writer, outgoing T, traceContext map[string]string) error {
 	payload, err := anypb.New(outgoing)
 	if err != nil {
 		return fmt.Errorf("failed to marshal outgoing into any: %w", err)
@@ -98,7 +118,7 @@ func writeProtoMessage[T proto.Message](writer io.Writer, outgoing T) error {
 
 	payload.TypeUrl = TypeURLPrefix + string(outgoing.ProtoReflect().Descriptor().FullName())
 
-	marshal, err := proto.Marshal(marauderpb.Message_builder{Payload: payload}.Build())
+	marshal, err := proto.Marshal(marauderpb.Message_builder{Payload: payload, TraceContext: traceContext}.Build())
 	if err != nil {
 		return fmt.Errorf("failed to marshal outgoing message: %w", err)
 	}
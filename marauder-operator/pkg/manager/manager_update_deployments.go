@@ -6,34 +6,47 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/Goldziher/go-utils/maputils"
 	"github.com/Goldziher/go-utils/sliceutils"
 	"github.com/containerd/errdefs"
 	"github.com/google/uuid"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 	"github.com/knockturnmc/marauder/marauder-proto/src/main/golang/marauderpb"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ErrServerRunning is returned by UpdateDeployments if the server is running.
 var ErrServerRunning = errors.New("server is running")
 
+// UpdateDeployments updates the deployments of the server to their TARGET state.
+// Updates requiring a restart are refused with ErrServerRunning while the server container is running.
 func (d DockerBasedManager) UpdateDeployments(
 	ctx context.Context,
 	serverModel networkmodel.ServerModel,
 	requiresRestart bool,
 	failOnUnexpectedOldFilesOnDisk bool,
-) error {
-	_, err := d.retrieveContainerInfo(ctx, serverModel)
+) (err error) {
+	ctx, span := tracing.Start(ctx, "update deployments", serverAttributes(
+		serverModel,
+		attribute.Bool("marauder.update.requires_restart", requiresRestart),
+	)...)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = d.retrieveContainerInfo(ctx, serverModel)
 	var serverRunning bool
 	if err == nil {
 		serverRunning = true
@@ -45,22 +58,112 @@ func (d DockerBasedManager) UpdateDeployments(
 		return fmt.Errorf("server %s is running: %w", serverModel.UUID.String(), ErrServerRunning)
 	}
 
+	return d.updateDeployments(ctx, serverModel, requiresRestart, failOnUnexpectedOldFilesOnDisk, serverRunning)
+}
+
+// updateDeployments updates all deployments of the server as a single transaction.
+// Every file deleted, overwritten or created on disk is staged in an update journal, so that any failure, be it while
+// updating the files or the IS states on the controller, restores the exact on-disk and IS state from before the update.
+func (d DockerBasedManager) updateDeployments(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	requiresRestart bool,
+	failOnUnexpectedOldFilesOnDisk bool,
+	serverRunning bool,
+) error {
 	missmatches, err := d.ControllerClient.FetchMissmatchesFor(ctx, serverModel.UUID, requiresRestart)
 	if err != nil {
 		return fmt.Errorf("failed to fetch missmatches for %s: %w", serverModel.UUID, err)
 	}
 
+	if len(missmatches) == 0 {
+		return nil
+	}
+
+	if err := d.checkNoUpdateFileConflicts(ctx, serverModel, missmatches); err != nil {
+		return err
+	}
+
+	serverFolderLocation, err := d.computeServerFolderLocation(serverModel)
+	if err != nil {
+		return fmt.Errorf("failed to compute server folder location: %w", err)
+	}
+
+	data, err := d.fetchTemplateData(ctx, serverModel)
+	if err != nil {
+		return fmt.Errorf("failed to fetch template data: %w", err)
+	}
+
+	journal, err := d.openUpdateJournal(ctx, serverModel, serverFolderLocation)
+	if err != nil {
+		return fmt.Errorf("failed to open update journal: %w", err)
+	}
+
+	defer journal.unlock()
+
 	for _, update := range missmatches {
-		if err := d.updateSingleDeployment(ctx, serverModel, update, failOnUnexpectedOldFilesOnDisk, serverRunning); err != nil {
-			return fmt.Errorf("failed to update %s on %s: %w", update.ArtefactIdentifier, serverModel.UUID.String(), err)
+		updateCtx, updateSpan := tracing.Start(ctx, "update deployment "+update.ArtefactIdentifier, serverAttributes(
+			serverModel,
+			attribute.String("marauder.artefact.identifier", update.ArtefactIdentifier),
+		)...)
+
+		updateStart := time.Now()
+		err := d.updateSingleDeployment(updateCtx, serverModel, update, failOnUnexpectedOldFilesOnDisk, serverFolderLocation, data, journal)
+		d.Metrics.ObserveArtefactUpdate(serverModel, update.ArtefactIdentifier, metrics.Outcome(err), time.Since(updateStart))
+		tracing.End(updateSpan, err)
+
+		if err != nil {
+			return d.rollbackUpdate(ctx, journal, fmt.Errorf("failed to update %s on %s: %w", update.ArtefactIdentifier, serverModel.UUID.String(), err))
+		}
+	}
+
+	// The IS states are only updated once all files were updated, so the controller never tracks a partially updated server.
+	for _, update := range missmatches {
+		var previousArtefact, installedArtefact *uuid.UUID
+		if artefactToUninstall := update.Missmatch.ArtefactToUninstall(); artefactToUninstall != nil {
+			previousArtefact = &artefactToUninstall.Artefact
+		}
+		if artefactToInstall := update.Missmatch.ArtefactToInstall(); artefactToInstall != nil {
+			installedArtefact = &artefactToInstall.Artefact
+		}
+
+		if err := journal.recordIsState(update.ArtefactIdentifier, previousArtefact); err != nil {
+			return d.rollbackUpdate(ctx, journal, fmt.Errorf("failed to journal is state of %s: %w", update.ArtefactIdentifier, err))
+		}
+
+		if err := d.updateIsState(ctx, serverModel, update.ArtefactIdentifier, installedArtefact); err != nil {
+			return d.rollbackUpdate(ctx, journal, err)
 		}
+	}
+
+	if err := journal.complete(); err != nil {
+		logrus.Warn("failed to complete update journal of server ", serverModel.Environment, "/", serverModel.Name, ": ", err)
+	}
 
+	for _, update := range missmatches {
 		logrus.Info("upgraded deployment ", update.ArtefactIdentifier, " on server ", serverModel.Environment, "/", serverModel.Name)
+
+		if err := d.possiblySendUpdateNotification(ctx, serverModel, update, serverRunning); err != nil {
+			logrus.Warn("failed to notify server ", serverModel.Environment, "/", serverModel.Name, " about upgrade: ", err)
+		}
 	}
 
 	return nil
 }
 
+// rollbackUpdate rolls back the update journaled in the passed journal after the update failed with the passed cause.
+func (d DockerBasedManager) rollbackUpdate(ctx context.Context, journal *updateJournal, cause error) error {
+	ctx, span := tracing.Start(ctx, "rollback update")
+	err := journal.rollback(ctx, d.ControllerClient)
+	tracing.End(span, err)
+
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to rollback update: %w", err))
+	}
+
+	return cause
+}
+
 // updateSingleDeployment updates a single deployment on the server.
 //
 //nolint:cyclop
@@ -69,38 +172,59 @@ func (d DockerBasedManager) updateSingleDeployment(
 	serverModel networkmodel.ServerModel,
 	update networkmodel.ArtefactVersionMissmatch,
 	force bool,
-	serverIsRunning bool,
+	serverFolderLocation string,
+	data templateData,
+	journal *updateJournal,
 ) error {
-	serverFolderLocation, err := d.computeServerFolderLocation(serverModel)
-	if err != nil {
-		return fmt.Errorf("failed to compute server folder location: %w", err)
-	}
-
 	artefactToInstall := update.Missmatch.ArtefactToInstall()
 	artefactToUninstall := update.Missmatch.ArtefactToUninstall()
 	var (
 		artefactToUninstallManifest filemodel.Manifest
+		artefactToInstallManifest   filemodel.Manifest
 		artefactToUninstallOnDisk   string
 		artefactToInstallOnDisk     string
+		artefactToUninstallState    deploymentState
+		oldDrift                    []networkmodel.FileDrift
+		err                         error
 	)
 
 	if artefactToUninstall != nil {
-		artefactToUninstallOnDisk, err = d.ControllerClient.DownloadArtefact(ctx, artefactToUninstall.Artefact)
+		artefactToUninstallOnDisk, err = d.downloadArtefact(ctx, artefactToUninstall.Artefact)
 		if err != nil {
 			return fmt.Errorf("failed to fetch old artefact to disk: %w", err)
 		}
 
+		defer func() { _ = os.Remove(artefactToUninstallOnDisk) }()
+
 		artefactToUninstallManifest, err = d.ControllerClient.FetchManifest(ctx, artefactToUninstall.Artefact)
 		if err != nil {
 			return fmt.Errorf("failed to fetch old artefact manifest: %w", err)
 		}
 
-		if err := d.validateOldDeploymentFilesOnDisk(
+		if err := artefactToUninstallManifest.Files.ValidateDeployments(); err != nil {
+			return fmt.Errorf("failed to validate old artefact manifest: %w", err)
+		}
+
+		// The files of the artefact to uninstall are compared against the template data they were rendered with, not the
+		// current one, so that variables changed since are not mistaken for local modifications.
+		artefactToUninstallState, err = d.readDeploymentState(serverModel, artefactToUninstall.Artefact, data)
+		if err != nil {
+			return fmt.Errorf("failed to read state of old artefact: %w", err)
+		}
+
+		oldDrift, err = d.validateOldDeploymentFilesOnDisk(
 			artefactToUninstallManifest,
 			artefactToUninstallOnDisk,
 			serverFolderLocation,
 			d.FileEqualityRegistry,
-		); err != nil {
+			artefactToUninstallState,
+		)
+		if oldDrift == nil {
+			// Without the drift, files kept on disk if modified cannot be told apart from unmodified ones.
+			return fmt.Errorf("failed to compare old deployment with server folder: %w", err)
+		}
+
+		if err != nil {
 			if force {
 				return fmt.Errorf("failed to validate old deployment hashes: %w", err)
 			}
@@ -113,49 +237,120 @@ func (d DockerBasedManager) updateSingleDeployment(
 	}
 
 	if artefactToInstall != nil {
-		artefactToInstallOnDisk, err = d.ControllerClient.DownloadArtefact(ctx, artefactToInstall.Artefact)
+		artefactToInstallOnDisk, err = d.downloadArtefact(ctx, artefactToInstall.Artefact)
 		if err != nil {
 			return fmt.Errorf("failed to download target artefact: %w", err)
 		}
+
+		defer func() { _ = os.Remove(artefactToInstallOnDisk) }()
+
+		artefactToInstallManifest, err = d.ControllerClient.FetchManifest(ctx, artefactToInstall.Artefact)
+		if err != nil {
+			return fmt.Errorf("failed to fetch target artefact manifest: %w", err)
+		}
+
+		if err := artefactToInstallManifest.Files.ValidateDeployments(); err != nil {
+			return fmt.Errorf("failed to validate target artefact manifest: %w", err)
+		}
+	}
+
+	pendingMerges := make(map[string]pendingMerge)
+	if artefactToUninstall != nil && artefactToInstall != nil {
+		pendingMerges, err = d.collectPendingMerges(
+			artefactToUninstallManifest, artefactToUninstallOnDisk, artefactToInstallManifest, serverFolderLocation, artefactToUninstallState.data,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to collect files to merge: %w", err)
+		}
 	}
 
 	if artefactToUninstall != nil {
-		// Delete old artefact files after downloading the new one to fail before moving the server into a non-start-able state
-		// This needs further improvements down the line to do a proper rollback, for now this should be fine.
-		if err := d.deleteOldArtefact(artefactToUninstallManifest, serverFolderLocation, force); err != nil {
+		keptFiles := oldFilesKeptOnDisk(artefactToUninstallManifest, artefactToInstallManifest, oldDrift)
+
+		// Delete old artefact files after downloading the new one to fail before moving the server into a non-start-able state.
+		if err := d.deleteOldArtefact(artefactToUninstallManifest, serverFolderLocation, keptFiles, force, journal); err != nil {
 			return fmt.Errorf("failed to delete old artefact from server folder: %w", err)
 		}
+
+		journal.recordUninstalledDeploymentState(artefactToUninstall.Artefact)
 	}
 
-	var artefactToInstallUUID *uuid.UUID
 	if artefactToInstall != nil {
-		// Extract the new artefact to the server directory
-		if err := d.unpackArtefactIntoServer(serverModel, artefactToInstallOnDisk, serverFolderLocation); err != nil {
-			var oldArtefactUUID *uuid.UUID
-			if artefactToUninstall != nil {
-				oldArtefactUUID = &artefactToUninstall.Artefact
-			}
-
-			// Unpacking failed, undo all potentially unpacked files.
-			if err := d.rollbackFailedArtefactInstall(ctx, serverModel, artefactToInstallOnDisk, oldArtefactUUID, serverFolderLocation); err != nil {
-				return fmt.Errorf("failed to rollback failed artefact installation: %w", err)
-			}
+		artefactToInstallState, err := d.writeDeploymentState(serverModel, artefactToInstall.Artefact, data, journal)
+		if err != nil {
+			return fmt.Errorf("failed to write state of new artefact: %w", err)
+		}
 
+		if err := d.tracedUnpackArtefactIntoServer(
+			ctx,
+			serverModel,
+			artefactToInstall.Artefact,
+			artefactToInstallOnDisk,
+			newDeploymentRenderer(artefactToInstallManifest, data),
+			newDeploymentMerger(pendingMerges, force, artefactToInstallState),
+			serverFolderLocation,
+			journal,
+		); err != nil {
 			return fmt.Errorf("failed to unpack new artefact: %w", err)
 		}
-
-		artefactToInstallUUID = &artefactToInstall.Artefact
 	}
 
-	if err := d.ControllerClient.UpdateState(ctx, serverModel.UUID, networkmodel.IS, networkmodel.UpdateServerStateRequest{
-		ArtefactIdentifier: update.ArtefactIdentifier,
-		ArtefactUUID:       artefactToInstallUUID,
-	}); err != nil {
-		return fmt.Errorf("failed to update controllers is state for server: %w", err)
+	return nil
+}
+
+// downloadArtefact downloads and verifies the passed artefact through the controller client in its own span.
+// The yielded back path is a private copy of the artefact that has to be removed by the caller once done with it.
+func (d DockerBasedManager) downloadArtefact(ctx context.Context, artefact uuid.UUID) (string, error) {
+	ctx, span := tracing.Start(ctx, "download artefact", attribute.String("marauder.artefact.uuid", artefact.String()))
+
+	artefactOnDisk, err := d.downloadVerifiedArtefact(ctx, artefact)
+	tracing.End(span, err)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to download artefact %s: %w", artefact, err)
 	}
 
-	if err := d.possiblySendUpdateNotification(ctx, serverModel, update, serverIsRunning, artefactToInstall, artefactToUninstall); err != nil {
-		return err
+	return artefactOnDisk, nil
+}
+
+// tracedUnpackArtefactIntoServer unpacks the passed artefact into the server in its own span.
+func (d DockerBasedManager) tracedUnpackArtefactIntoServer(
+	ctx context.Context,
+	server networkmodel.ServerModel,
+	artefact uuid.UUID,
+	artefactPath string,
+	renderer deploymentRenderer,
+	merger deploymentMerger,
+	serverFolderLocation string,
+	journal *updateJournal,
+) error {
+	_, span := tracing.Start(ctx, "unpack artefact", serverAttributes(server, attribute.String("marauder.artefact.uuid", artefact.String()))...)
+	err := d.unpackArtefactIntoServer(server, artefactPath, renderer, merger, serverFolderLocation, journal)
+	tracing.End(span, err)
+
+	return err
+}
+
+// updateIsState updates the IS state of the passed artefact on the server on the controller in its own span.
+func (d DockerBasedManager) updateIsState(
+	ctx context.Context,
+	server networkmodel.ServerModel,
+	artefactIdentifier string,
+	artefactUUID *uuid.UUID,
+) error {
+	ctx, span := tracing.Start(ctx, "update is state", serverAttributes(
+		server,
+		attribute.String("marauder.artefact.identifier", artefactIdentifier),
+	)...)
+
+	err := d.ControllerClient.UpdateState(ctx, server.UUID, networkmodel.IS, networkmodel.UpdateServerStateRequest{
+		ArtefactIdentifier: artefactIdentifier,
+		ArtefactUUID:       artefactUUID,
+	})
+	tracing.End(span, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to update controllers is state for server: %w", err)
 	}
 
 	return nil
@@ -166,13 +361,14 @@ func (d DockerBasedManager) possiblySendUpdateNotification(
 	serverModel networkmodel.ServerModel,
 	update networkmodel.ArtefactVersionMissmatch,
 	serverIsRunning bool,
-	artefactToInstall *networkmodel.ArtefactVersionMissmatchArtefactInfo,
-	artefactToUninstall *networkmodel.ArtefactVersionMissmatchArtefactInfo,
 ) error {
 	if !serverIsRunning || serverModel.ManagementSocketPath == "" {
 		return nil
 	}
 
+	artefactToInstall := update.Missmatch.ArtefactToInstall()
+	artefactToUninstall := update.Missmatch.ArtefactToUninstall()
+
 	var oldVersion, newVersion *string
 	if artefactToInstall != nil {
 		newVersion = &artefactToInstall.Version
@@ -196,13 +392,26 @@ func (d DockerBasedManager) possiblySendUpdateNotification(
 	return nil
 }
 
-// deleteOldArtefact deletes an old artefact in the server folder.
+// deleteOldArtefact deletes an old artefact in the server folder, keeping the passed files on disk.
+// Files are deleted through the server folder as a root, so neither paths of the manifest nor symbolic links on disk can
+// lead to deleting files outside the server folder.
 func (d DockerBasedManager) deleteOldArtefact(
 	oldArtefact filemodel.Manifest,
 	serverFolderLocation string,
+	keptFiles map[string]bool,
 	errorOnMissingFiles bool,
+	journal *updateJournal,
 ) error {
-	relativePotentiallyEmptyParentDirsAsMap, err := d.deleteOldFilesAndYieldParents(oldArtefact, serverFolderLocation, errorOnMissingFiles)
+	serverFolder, err := os.OpenRoot(serverFolderLocation)
+	if err != nil {
+		return fmt.Errorf("failed to open server folder %s: %w", serverFolderLocation, err)
+	}
+
+	defer utils.SwallowClose(serverFolder)
+
+	relativePotentiallyEmptyParentDirsAsMap, err := d.deleteOldFilesAndYieldParents(
+		oldArtefact, serverFolder, keptFiles, errorOnMissingFiles, journal,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to delete old files: %w", err)
 	}
@@ -215,13 +424,13 @@ func (d DockerBasedManager) deleteOldArtefact(
 
 	// Iterate and remove is possible.
 	for _, dir := range potentiallyEmptyDirs {
-		dirOutput, err := os.ReadDir(path.Join(serverFolderLocation, dir))
+		dirOutput, err := fs.ReadDir(serverFolder.FS(), dir)
 		if err != nil {
 			return fmt.Errorf("failed to read potentially empty parent dir %s: %w", dir, err)
 		}
 
 		if len(dirOutput) == 0 {
-			if err := os.Remove(path.Join(serverFolderLocation, dir)); err != nil {
+			if err := serverFolder.Remove(filepath.FromSlash(dir)); err != nil {
 				return fmt.Errorf("failed to remove empty parent dir %s: %w", dir, err)
 			}
 		}
@@ -231,20 +440,39 @@ func (d DockerBasedManager) deleteOldArtefact(
 }
 
 // deleteOldFilesAndYieldParents deletes all old files of a manifest and yields back a map of all parent dirs that might need to be deleted if empty.
+// Files in keptFiles, as computed from their deployment mode by oldFilesKeptOnDisk, are left untouched.
 func (d DockerBasedManager) deleteOldFilesAndYieldParents(
 	oldArtefact filemodel.Manifest,
-	serverFolderLocation string,
+	serverFolder *os.Root,
+	keptFiles map[string]bool,
 	errorOnMissingFiles bool,
+	journal *updateJournal,
 ) (map[string]bool, error) {
 	relativePotentiallyEmptyParentDirsAsMap := make(map[string]bool)
 
-	// Delete all files
+	// Collect all files to delete, so they are journaled at once before the first one is deleted.
+	filesToDelete := make(map[string]string)
 	for filePathWithPrefix := range oldArtefact.Files.MatchedFilesToReferenceMap() {
 		filePathWithoutPrefix, _ := strings.CutPrefix(filePathWithPrefix, pkg.FileParentDirectoryInArtefact)
-		cleanedFilePathWithoutPrefix := utils.CleanPathAndJoin(".", filePathWithoutPrefix)
+		if keptFiles[filePathWithoutPrefix] {
+			continue
+		}
+
+		cleanedFilePathWithoutPrefix, err := utils.ArtefactPathInServerFolder(filePathWithPrefix)
+		if err != nil {
+			return relativePotentiallyEmptyParentDirsAsMap, fmt.Errorf("refusing to delete file %s: %w", filePathWithPrefix, err)
+		}
+
+		filesToDelete[filePathWithPrefix] = cleanedFilePathWithoutPrefix
+	}
 
-		fullFilePath := utils.CleanPathAndJoin(serverFolderLocation, filePathWithoutPrefix)
-		if err := os.Remove(fullFilePath); err != nil {
+	if err := journal.stageForDeletion(serverFolder, maputils.Values(filesToDelete)...); err != nil {
+		return relativePotentiallyEmptyParentDirsAsMap, fmt.Errorf("failed to journal files to delete: %w", err)
+	}
+
+	// Delete all files
+	for filePathWithPrefix, cleanedFilePathWithoutPrefix := range filesToDelete {
+		if err := serverFolder.Remove(filepath.FromSlash(cleanedFilePathWithoutPrefix)); err != nil {
 			if !os.IsNotExist(err) {
 				return relativePotentiallyEmptyParentDirsAsMap, fmt.Errorf("unexpected failure to delete file %s: %w", filePathWithPrefix, err)
 			}
@@ -290,11 +518,32 @@ func (d DockerBasedManager) deleteOldFilesAndYieldParents(
 }
 
 // unpackArtefactIntoServer unpacks the passed artefact into the server.
+// Every entry of the artefact is validated to stay inside the server folder, which files are written through as a root
+// so that symbolic links on disk cannot redirect writes outside of it either.
 func (d DockerBasedManager) unpackArtefactIntoServer(
 	server networkmodel.ServerModel,
 	artefactPath string,
+	renderer deploymentRenderer,
+	merger deploymentMerger,
 	serverFolderLocation string,
+	journal *updateJournal,
 ) error {
+	diskConfig, err := d.FindDiskConfig(server)
+	if err != nil {
+		return fmt.Errorf("failed to find disk config: %w", err)
+	}
+
+	if err := os.MkdirAll(serverFolderLocation, deployedDirectoryMode); err != nil {
+		return fmt.Errorf("failed to create server folder %s: %w", serverFolderLocation, err)
+	}
+
+	serverFolder, err := os.OpenRoot(serverFolderLocation)
+	if err != nil {
+		return fmt.Errorf("failed to open server folder %s: %w", serverFolderLocation, err)
+	}
+
+	defer utils.SwallowClose(serverFolder)
+
 	tarballReader, err := utils.NewFriendlyTarballReaderFromPath(artefactPath)
 	if err != nil {
 		return fmt.Errorf("failed to open artefact tar: %w", err)
@@ -302,6 +551,10 @@ func (d DockerBasedManager) unpackArtefactIntoServer(
 
 	defer func() { _ = tarballReader.Close(true) }()
 
+	if err := stageFilesToUnpack(renderer, serverFolder, journal); err != nil {
+		return err
+	}
+
 	for {
 		tarballHeader, err := tarballReader.Next()
 		if err != nil {
@@ -317,56 +570,147 @@ func (d DockerBasedManager) unpackArtefactIntoServer(
 			continue
 		}
 
-		if err := d.extractFileToServer(server, tarballHeader, tarballReader.Reader, serverFolderLocation); err != nil {
+		if err := utils.ValidateArtefactEntry(tarballHeader); err != nil {
+			return fmt.Errorf("refusing to extract tar file: %w", err)
+		}
+
+		filePathInServerFolder, _ := utils.ArtefactPathInServerFolder(tarballHeader.Name)
+
+		alreadyInstalled, err := isInstalledOnlyIfAbsentAndPresent(renderer, tarballHeader.Name, serverFolder)
+		if err != nil {
+			return err
+		}
+
+		if alreadyInstalled {
+			continue
+		}
+
+		if tarballHeader.Typeflag == tar.TypeSymlink {
+			if err := extractFileToServer(filePathInServerFolder, tarballHeader, nil, 0, serverFolder, diskConfig.FolderOwner, journal); err != nil {
+				return fmt.Errorf("failed to extract tar symlink: %w", err)
+			}
+
+			continue
+		}
+
+		content, err := renderer.render(tarballHeader.Name, tarballReader.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to render tar file %s: %w", tarballHeader.Name, err)
+		}
+
+		content, mergeResult, err := merger.merge(tarballHeader.Name, content)
+		if err != nil {
+			return fmt.Errorf("failed to merge tar file %s: %w", tarballHeader.Name, err)
+		}
+
+		mode := renderer.deployedMode(tarballHeader.Name)
+		if err := extractFileToServer(filePathInServerFolder, tarballHeader, content, mode, serverFolder, diskConfig.FolderOwner, journal); err != nil {
 			return fmt.Errorf("failed to extract tar file: %w", err)
 		}
+
+		if err := merger.recordMergeResult(filePathInServerFolder, mergeResult); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// extractFileToServer extracts the file in the tarball to the server folder location.
-func (d DockerBasedManager) extractFileToServer(
-	server networkmodel.ServerModel,
-	tarballHeader *tar.Header,
-	tarballReader *tar.Reader,
-	serverFolderLocation string,
-) error {
-	filePathInServerFolder, _ := strings.CutPrefix(tarballHeader.Name, pkg.FileParentDirectoryInArtefact)
-	cleanedFilePathInServerFolder := path.Clean(filePathInServerFolder)
-	filePathOnSystem := utils.CleanPathAndJoin(serverFolderLocation, cleanedFilePathInServerFolder)
+// stageFilesToUnpack journals all files of the artefact listed in its manifest at once before the first one is written.
+// Files the artefact does not overwrite as they are present are not journaled. Entries of the tarball not listed in the
+// manifest are still journaled individually when they are extracted.
+func stageFilesToUnpack(renderer deploymentRenderer, serverFolder *os.Root, journal *updateJournal) error {
+	filesToUnpack := make([]string, 0, len(renderer.fileReferences))
+	for pathInTarball := range renderer.fileReferences {
+		// Like when extracting the artefact, files outside the files folder are ignored.
+		if !strings.HasPrefix(pathInTarball, pkg.FileParentDirectoryInArtefact) {
+			continue
+		}
+
+		filePathInServerFolder, err := utils.ArtefactPathInServerFolder(pathInTarball)
+		if err != nil {
+			return fmt.Errorf("refusing to extract file %s: %w", pathInTarball, err)
+		}
+
+		alreadyInstalled, err := isInstalledOnlyIfAbsentAndPresent(renderer, pathInTarball, serverFolder)
+		if err != nil {
+			return err
+		}
+
+		if !alreadyInstalled {
+			filesToUnpack = append(filesToUnpack, filePathInServerFolder)
+		}
+	}
+
+	if err := journal.stage(serverFolder, filesToUnpack...); err != nil {
+		return fmt.Errorf("failed to journal files to extract: %w", err)
+	}
+
+	return nil
+}
 
-	if err := os.MkdirAll(path.Dir(filePathOnSystem), 0o700); err != nil {
-		return fmt.Errorf("failed to create parent directory for %s: %w", filePathOnSystem, err)
+// isInstalledOnlyIfAbsentAndPresent returns if the passed file in the tarball is deployed in a mode that only extracts it
+// if absent and is present in the server folder.
+func isInstalledOnlyIfAbsentAndPresent(renderer deploymentRenderer, pathInTarball string, serverFolder *os.Root) (bool, error) {
+	if !installedOnlyIfAbsent(renderer.mode(pathInTarball)) {
+		return false, nil
 	}
 
-	targetFileOnSystem, err := os.Create(filepath.Clean(filePathOnSystem))
+	filePathInServerFolder, err := utils.ArtefactPathInServerFolder(pathInTarball)
 	if err != nil {
-		return fmt.Errorf("failed to open output file %s: %w", filePathOnSystem, err)
+		return false, err //nolint:wrapcheck
 	}
 
-	if _, err := io.CopyN(targetFileOnSystem, tarballReader, tarballHeader.Size); err != nil {
-		_ = targetFileOnSystem.Close()
-		return fmt.Errorf("failed to copy over tarball content to disk file %s: %w", filePathOnSystem, err)
+	if _, err := serverFolder.Lstat(filepath.FromSlash(filePathInServerFolder)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to stat file %s: %w", filePathInServerFolder, err)
 	}
 
-	_ = targetFileOnSystem.Close()
+	return true, nil
+}
 
-	diskConfig, err := d.FindDiskConfig(server)
-	if err != nil {
-		return fmt.Errorf("failed to find disk config: %w", err)
+// extractFileToServer extracts the file in the tarball to the passed path in the server folder, writing the passed
+// content with the passed permissions and chowning it and its parents to the owner, if passed.
+// Symbolic links in the tarball are recreated as links, their content and permissions are ignored.
+func extractFileToServer(
+	filePathInServerFolder string,
+	tarballHeader *tar.Header,
+	content io.Reader,
+	mode fs.FileMode,
+	serverFolder *os.Root,
+	owner *FolderOwner,
+	journal *updateJournal,
+) error {
+	filePathOnSystem := filepath.FromSlash(filePathInServerFolder)
+
+	if err := journal.stage(serverFolder, filePathInServerFolder); err != nil {
+		return fmt.Errorf("failed to journal file %s: %w", filePathInServerFolder, err)
 	}
 
-	if diskConfig.FolderOwner != nil {
-		chownedFilePath := cleanedFilePathInServerFolder
-		for chownedFilePath != "." {
-			onDiskPath := utils.CleanPathAndJoin(serverFolderLocation, chownedFilePath)
-			if err := os.Chown(onDiskPath, diskConfig.FolderOwner.UID, diskConfig.FolderOwner.GID); err != nil {
-				return fmt.Errorf("failed to chown deployed file %s: %w", chownedFilePath, err)
-			}
+	if err := serverFolder.MkdirAll(filepath.Dir(filePathOnSystem), deployedDirectoryMode); err != nil {
+		return fmt.Errorf("failed to create parent directory for %s: %w", filePathInServerFolder, err)
+	}
 
-			chownedFilePath = path.Dir(chownedFilePath)
+	if tarballHeader.Typeflag == tar.TypeSymlink {
+		if err := writeDeployedSymlink(serverFolder, filePathOnSystem, tarballHeader.Linkname); err != nil {
+			return err
 		}
+	} else if err := writeDeployedFile(serverFolder, filePathOnSystem, content, mode); err != nil {
+		return err
 	}
+
+	if owner == nil {
+		return nil
+	}
+
+	for chownedFilePath := filePathInServerFolder; chownedFilePath != "."; chownedFilePath = path.Dir(chownedFilePath) {
+		if err := serverFolder.Lchown(filepath.FromSlash(chownedFilePath), owner.UID, owner.GID); err != nil {
+			return fmt.Errorf("failed to chown deployed file %s: %w", chownedFilePath, err)
+		}
+	}
+
 	return nil
 }
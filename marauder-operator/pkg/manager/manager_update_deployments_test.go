@@ -0,0 +1,376 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	. "github.com/knockturnmc/marauder/marauder-operator/pkg/manager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Updating deployments", Label("unittest"), func() {
+	var (
+		serverFolder     string
+		journalFolder    string
+		server           networkmodel.ServerModel
+		controllerClient *fakeControllerClient
+		serverManager    *DockerBasedManager
+
+		spellcoreV1, spellcoreV2, legacyV1, economyV1 uuid.UUID
+	)
+
+	spellcoreV1Files := map[string]string{
+		"plugins/spellcore.jar":        "spellcore 1",
+		"plugins/spellcore/config.yml": "config 1",
+	}
+
+	legacyV1Files := map[string]string{
+		"plugins/legacy.jar": "legacy 1",
+	}
+
+	serverFile := func(pathInFolder string) string {
+		return filepath.Join(serverFolder, filepath.FromSlash(pathInFolder))
+	}
+
+	expectServerFiles := func(files map[string]string) {
+		GinkgoHelper()
+
+		for pathInFolder, content := range files {
+			Expect(os.ReadFile(serverFile(pathInFolder))).To(BeEquivalentTo(content), pathInFolder)
+		}
+	}
+
+	expectStateBeforeUpdate := func() {
+		GinkgoHelper()
+
+		expectServerFiles(spellcoreV1Files)
+		expectServerFiles(legacyV1Files)
+		expectServerFiles(map[string]string{"server.properties": "motd=hello"})
+
+		Expect(serverFile("plugins/spellcore/messages.yml")).To(Not(BeAnExistingFile()))
+		Expect(serverFile("plugins/economy.jar")).To(Not(BeAnExistingFile()))
+		Expect(serverFile("plugins/economy")).To(Not(BeADirectory()))
+
+		Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcoreV1, "legacy": legacyV1}))
+		Expect(filepath.Join(journalFolder, server.UUID.String())).To(Not(BeADirectory()))
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		server, serverFolder = newTestServer(root)
+		serverManager, controllerClient = newTestManager(root)
+		journalFolder = serverManager.UpdateJournalPath
+		Expect(os.MkdirAll(journalFolder, 0o700)).To(Succeed())
+
+		spellcoreV1 = controllerClient.addArtefact("spellcore", "1", spellcoreV1Files)
+		spellcoreV2 = controllerClient.addArtefact("spellcore", "2", map[string]string{
+			"plugins/spellcore.jar":          "spellcore 2",
+			"plugins/spellcore/config.yml":   "config 2",
+			"plugins/spellcore/messages.yml": "messages 2",
+		})
+		legacyV1 = controllerClient.addArtefact("legacy", "1", legacyV1Files)
+		economyV1 = controllerClient.addArtefact("economy", "1", map[string]string{
+			"plugins/economy.jar":             "economy 1",
+			"plugins/economy/data/ledger.yml": "ledger 1",
+		})
+
+		controllerClient.isStates["spellcore"] = spellcoreV1
+		controllerClient.isStates["legacy"] = legacyV1
+		controllerClient.missmatches = []networkmodel.ArtefactVersionMissmatch{
+			{ArtefactIdentifier: "spellcore", RequiresRestart: true, Missmatch: networkmodel.ArtefactMissmatch{
+				Update: &networkmodel.ArtefactVersionMissmatchUpdate{
+					Is:     networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: spellcoreV1, Version: "1"},
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: spellcoreV2, Version: "2"},
+				},
+			}},
+			{ArtefactIdentifier: "legacy", RequiresRestart: true, Missmatch: networkmodel.ArtefactMissmatch{
+				Uninstall: &networkmodel.ArtefactVersionMissmatchUninstall{
+					Is: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: legacyV1, Version: "1"},
+				},
+			}},
+			{ArtefactIdentifier: "economy", RequiresRestart: true, Missmatch: networkmodel.ArtefactMissmatch{
+				Install: &networkmodel.ArtefactVersionMissmatchInstall{
+					Target: networkmodel.ArtefactVersionMissmatchArtefactInfo{Artefact: economyV1, Version: "1"},
+				},
+			}},
+		}
+
+		for pathInFolder, content := range map[string]string{
+			"plugins/spellcore.jar":        "spellcore 1",
+			"plugins/spellcore/config.yml": "config 1",
+			"plugins/legacy.jar":           "legacy 1",
+			"server.properties":            "motd=hello",
+		} {
+			Expect(os.MkdirAll(filepath.Dir(serverFile(pathInFolder)), 0o700)).To(Succeed())
+			Expect(os.WriteFile(serverFile(pathInFolder), []byte(content), 0o640)).To(Succeed())
+		}
+	})
+
+	It("applies all missmatches and updates the is states", func() {
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+
+		expectServerFiles(map[string]string{
+			"plugins/spellcore.jar":           "spellcore 2",
+			"plugins/spellcore/config.yml":    "config 2",
+			"plugins/spellcore/messages.yml":  "messages 2",
+			"plugins/economy.jar":             "economy 1",
+			"plugins/economy/data/ledger.yml": "ledger 1",
+			"server.properties":               "motd=hello",
+		})
+		Expect(serverFile("plugins/legacy.jar")).To(Not(BeAnExistingFile()))
+
+		Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcoreV2, "economy": economyV1}))
+		Expect(filepath.Join(journalFolder, server.UUID.String())).To(Not(BeADirectory()))
+	})
+
+	It("rolls back all earlier artefacts if a later artefact fails", func() {
+		controllerClient.failDownloadOf[economyV1] = true
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(MatchError(ContainSubstring("economy")))
+
+		expectStateBeforeUpdate()
+	})
+
+	It("rolls back the files and is states if an is state cannot be updated", func() {
+		controllerClient.failUpdateStateOf["economy"] = true
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Not(Succeed()))
+
+		expectStateBeforeUpdate()
+	})
+
+	It("restores the file modes of deleted files", func() {
+		controllerClient.failDownloadOf[economyV1] = true
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Not(Succeed()))
+
+		info, err := os.Stat(serverFile("plugins/legacy.jar"))
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(info.Mode().Perm()).To(BeEquivalentTo(0o640))
+	})
+
+	It("rolls back partially extracted artefacts", func() {
+		// Truncate the tarball of the last artefact, failing its extraction after some files may have been written.
+		economyTarball, err := controllerClient.DownloadArtefact(context.Background(), economyV1)
+		Expect(err).To(Not(HaveOccurred()))
+
+		content, err := os.ReadFile(economyTarball)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(os.WriteFile(economyTarball, content[:len(content)/2], 0o600)).To(Succeed())
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Not(Succeed()))
+
+		expectStateBeforeUpdate()
+	})
+
+	Describe("recovering interrupted updates", func() {
+		// recoverWithNewManager recovers the update journals with a new manager, as done by a restarted operator.
+		recoverWithNewManager := func() error {
+			restartedManager := *serverManager
+			return restartedManager.RecoverUpdateJournals(context.Background())
+		}
+
+		It("rolls back files changed before the crash", func() {
+			controllerClient.crashOnDownloadOf[economyV1] = true
+
+			simulateCrash(func() { _ = serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true) })
+
+			Expect(serverFile("plugins/legacy.jar")).To(Not(BeAnExistingFile()))
+			Expect(filepath.Join(journalFolder, server.UUID.String())).To(BeADirectory())
+
+			Expect(recoverWithNewManager()).To(Succeed())
+			expectStateBeforeUpdate()
+		})
+
+		It("stages deleted files by linking rather than copying them", func() {
+			deletedFile, err := os.Stat(serverFile("plugins/legacy.jar"))
+			Expect(err).To(Not(HaveOccurred()))
+
+			controllerClient.crashOnDownloadOf[economyV1] = true
+			simulateCrash(func() { _ = serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true) })
+
+			stagedFile, err := os.Stat(filepath.Join(journalFolder, server.UUID.String(), "files", "plugins", "legacy.jar"))
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(os.SameFile(deletedFile, stagedFile)).To(BeTrue())
+
+			Expect(recoverWithNewManager()).To(Succeed())
+			expectStateBeforeUpdate()
+		})
+
+		It("rolls back is states updated before the crash", func() {
+			controllerClient.crashOnUpdateStateOf["economy"] = true
+
+			simulateCrash(func() { _ = serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true) })
+
+			Expect(controllerClient.isStates).To(HaveKeyWithValue("spellcore", spellcoreV2))
+
+			Expect(recoverWithNewManager()).To(Succeed())
+			expectStateBeforeUpdate()
+		})
+
+		It("keeps the journal and refuses updates until the controller is reachable again", func() {
+			controllerClient.crashOnUpdateStateOf["economy"] = true
+			simulateCrash(func() { _ = serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true) })
+
+			controllerClient.unreachable = true
+			Expect(recoverWithNewManager()).To(Not(Succeed()))
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(MatchError(ErrUpdateJournalPending))
+
+			controllerClient.unreachable = false
+			Expect(recoverWithNewManager()).To(Succeed())
+			expectStateBeforeUpdate()
+
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+			Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcoreV2, "economy": economyV1}))
+		})
+
+		It("retries the rollback on the next update once the controller is reachable again", func() {
+			controllerClient.crashOnUpdateStateOf["economy"] = true
+			simulateCrash(func() { _ = serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true) })
+
+			controllerClient.unreachable = true
+			Expect(recoverWithNewManager()).To(Not(Succeed()))
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(MatchError(ErrUpdateJournalPending))
+
+			controllerClient.unreachable = false
+			Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(Succeed())
+			Expect(controllerClient.isStates).To(Equal(map[string]uuid.UUID{"spellcore": spellcoreV2, "economy": economyV1}))
+		})
+
+		It("removes journals of completed updates without rolling them back", func() {
+			stagingFolder := filepath.Join(journalFolder, server.UUID.String())
+			Expect(os.MkdirAll(stagingFolder, 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(stagingFolder, "journal.json"), []byte(`{
+				"serverUUID": "`+server.UUID.String()+`",
+				"serverFolder": "`+serverFolder+`",
+				"files": [{"path": "plugins/spellcore.jar", "existed": false}],
+				"isStates": [{"artefactIdentifier": "spellcore"}],
+				"completed": true
+			}`), 0o600)).To(Succeed())
+
+			Expect(recoverWithNewManager()).To(Succeed())
+
+			expectStateBeforeUpdate()
+		})
+
+		It("moves the deployment states of completed updates into place", func() {
+			stateFolder := filepath.Join(serverManager.DeploymentStatePath, server.UUID.String())
+			Expect(os.MkdirAll(filepath.Join(stateFolder, legacyV1.String()), 0o700)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(stateFolder, economyV1.String()+".pending"), 0o700)).To(Succeed())
+
+			stagingFolder := filepath.Join(journalFolder, server.UUID.String())
+			Expect(os.MkdirAll(stagingFolder, 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(stagingFolder, "journal.json"), []byte(`{
+				"serverUUID": "`+server.UUID.String()+`",
+				"serverFolder": "`+serverFolder+`",
+				"deploymentStateFolder": "`+stateFolder+`",
+				"installedDeploymentStates": ["`+economyV1.String()+`"],
+				"uninstalledDeploymentStates": ["`+legacyV1.String()+`"],
+				"completed": true
+			}`), 0o600)).To(Succeed())
+
+			Expect(recoverWithNewManager()).To(Succeed())
+
+			Expect(os.ReadDir(stateFolder)).To(ConsistOf(HaveField("Name()", economyV1.String())))
+			Expect(filepath.Join(journalFolder, server.UUID.String())).To(Not(BeADirectory()))
+		})
+
+		It("removes journals that were never persisted", func() {
+			Expect(os.MkdirAll(filepath.Join(journalFolder, server.UUID.String()), 0o700)).To(Succeed())
+
+			Expect(recoverWithNewManager()).To(Succeed())
+			Expect(filepath.Join(journalFolder, server.UUID.String())).To(Not(BeADirectory()))
+		})
+	})
+
+	It("refuses to update a server while another update is in progress", func() {
+		unlock, err := serverManager.LockUpdateJournal(server.UUID)
+		Expect(err).To(Not(HaveOccurred()))
+		defer unlock()
+
+		Expect(serverManager.UpdateDeploymentsOfStoppedServer(context.Background(), server, true)).To(MatchError(ErrUpdateJournalPending))
+
+		expectServerFiles(spellcoreV1Files)
+		Expect(controllerClient.isStates).To(HaveKeyWithValue("spellcore", spellcoreV1))
+	})
+
+	Describe("planning the update", func() {
+		// filePlans maps the planned files of each artefact from their path to their change.
+		filePlans := func(plan networkmodel.UpdatePlan) map[string]map[string]networkmodel.FileUpdatePlan {
+			result := make(map[string]map[string]networkmodel.FileUpdatePlan)
+			for _, artefact := range plan.Artefacts {
+				result[artefact.ArtefactIdentifier] = make(map[string]networkmodel.FileUpdatePlan)
+				for _, file := range artefact.Files {
+					result[artefact.ArtefactIdentifier][file.Path] = file
+				}
+			}
+
+			return result
+		}
+
+		It("plans all file changes without touching the server folder", func() {
+			plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, true)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(plan.RequiresRestart).To(BeTrue())
+			Expect(plan.FailsOnLocalModifications).To(BeFalse())
+			Expect(plan.LosesLocalModifications()).To(BeFalse())
+			Expect(filePlans(plan)).To(Equal(map[string]map[string]networkmodel.FileUpdatePlan{
+				"spellcore": {
+					"plugins/spellcore.jar":          {Path: "plugins/spellcore.jar", Change: networkmodel.FileUpdateOverwrite},
+					"plugins/spellcore/config.yml":   {Path: "plugins/spellcore/config.yml", Change: networkmodel.FileUpdateOverwrite},
+					"plugins/spellcore/messages.yml": {Path: "plugins/spellcore/messages.yml", Change: networkmodel.FileUpdateCreate},
+				},
+				"legacy": {
+					"plugins/legacy.jar": {Path: "plugins/legacy.jar", Change: networkmodel.FileUpdateDelete},
+				},
+				"economy": {
+					"plugins/economy.jar":             {Path: "plugins/economy.jar", Change: networkmodel.FileUpdateCreate},
+					"plugins/economy/data/ledger.yml": {Path: "plugins/economy/data/ledger.yml", Change: networkmodel.FileUpdateCreate},
+				},
+			}))
+
+			expectStateBeforeUpdate()
+		})
+
+		It("plans the local modifications lost by the update", func() {
+			Expect(os.WriteFile(serverFile("plugins/spellcore/config.yml"), []byte("hand edited"), 0o640)).To(Succeed())
+			Expect(os.WriteFile(serverFile("plugins/economy.jar"), []byte("manually dropped in"), 0o640)).To(Succeed())
+
+			plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, false)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(plan.FailsOnLocalModifications).To(BeFalse())
+			Expect(plan.LosesLocalModifications()).To(BeTrue())
+			Expect(filePlans(plan)["spellcore"]["plugins/spellcore/config.yml"].LosesLocalModification).To(BeTrue())
+			Expect(filePlans(plan)["spellcore"]["plugins/spellcore.jar"].LosesLocalModification).To(BeFalse())
+			Expect(filePlans(plan)["economy"]["plugins/economy.jar"]).To(Equal(networkmodel.FileUpdatePlan{
+				Path: "plugins/economy.jar", Change: networkmodel.FileUpdateOverwrite, LosesLocalModification: true,
+			}))
+		})
+
+		It("plans the failure of an update that is not forced on local modifications", func() {
+			Expect(os.Remove(serverFile("plugins/legacy.jar"))).To(Succeed())
+
+			plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, true, true)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(plan.FailsOnLocalModifications).To(BeTrue())
+		})
+
+		It("defers artefacts requiring a restart if the update does not restart the server", func() {
+			plan, err := serverManager.PlanUpdateDeploymentsOfStoppedServer(context.Background(), server, false, true)
+			Expect(err).To(Not(HaveOccurred()))
+
+			Expect(plan.Artefacts).To(HaveLen(3))
+			for _, artefact := range plan.Artefacts {
+				Expect(artefact.Deferred).To(BeTrue())
+				Expect(artefact.Files).To(BeEmpty())
+			}
+		})
+	})
+})
@@ -0,0 +1,620 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/controller"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+)
+
+var (
+	// ErrUpdateJournalPending is returned if a server is updated or started while the journal of another update exists.
+	ErrUpdateJournalPending = errors.New("update journal pending")
+
+	// ErrUnsafeJournalPath is returned if a file outside the server folder is journaled.
+	ErrUnsafeJournalPath = errors.New("unsafe journal path")
+)
+
+const (
+	// journalStagedFilesFolder is the folder in the staging folder of a journal holding the staged files.
+	journalStagedFilesFolder = "files"
+
+	// journalStateFile is the file in the staging folder of a journal persisting its state.
+	journalStateFile = "journal.json"
+
+	// journalLockFileSuffix is appended to the server uuid to name the file in the update journal folder that is locked
+	// by the update or replay owning the journal of the server.
+	journalLockFileSuffix = ".lock"
+)
+
+// The updateJournal stages the previous on-disk state of every file that an update of a server deletes, overwrites or
+// creates, as well as the previous IS states of all updated artefacts.
+// This allows an update of multiple artefacts to be rolled back as a whole.
+// The state of the journal is persisted before the changes it journals, so an update interrupted by a crash of the
+// operator can be rolled back on its next start. Files are staged in batches, persisting the journal once per batch.
+type updateJournal struct {
+	stagingFolder  string
+	journaledPaths map[string]struct{}
+	lock           *os.File
+
+	state updateJournalState
+}
+
+// The updateJournalState is the persisted state of an update journal.
+type updateJournalState struct {
+	ServerUUID   uuid.UUID    `json:"serverUUID"`
+	ServerFolder string       `json:"serverFolder"`
+	Owner        *FolderOwner `json:"owner,omitempty"`
+
+	Files              []journaledFile    `json:"files"`
+	CreatedDirectories []string           `json:"createdDirectories"`
+	IsStates           []journaledIsState `json:"isStates"`
+
+	// DeploymentStateFolder is the folder holding the states of the artefacts deployed onto the server.
+	DeploymentStateFolder string `json:"deploymentStateFolder,omitempty"`
+
+	// InstalledDeploymentStates are the artefacts whose pending state replaces their state once the update is completed.
+	InstalledDeploymentStates []uuid.UUID `json:"installedDeploymentStates,omitempty"`
+
+	// UninstalledDeploymentStates are the artefacts whose state is removed once the update is completed.
+	UninstalledDeploymentStates []uuid.UUID `json:"uninstalledDeploymentStates,omitempty"`
+
+	// Completed defines that the update applied all changes and only the removal of the journal is outstanding.
+	Completed bool `json:"completed"`
+}
+
+// A journaledFile is a file in the server folder touched by an update.
+type journaledFile struct {
+	// Path is the slash separated path of the file relative to the server folder.
+	Path string `json:"path"`
+
+	// Existed defines if the file existed before the update. If so, its content is staged in the journal.
+	Existed bool `json:"existed"`
+
+	// SymlinkTarget holds the target of the file if it was a symbolic link before the update.
+	// Symbolic links are recorded by their target instead of being staged.
+	SymlinkTarget *string `json:"symlinkTarget,omitempty"`
+}
+
+// A journaledIsState is the IS state of an artefact on the server before the update.
+type journaledIsState struct {
+	ArtefactIdentifier string `json:"artefactIdentifier"`
+
+	// PreviousArtefact is the artefact installed before the update, nil if the artefact was not installed.
+	PreviousArtefact *uuid.UUID `json:"previousArtefact,omitempty"`
+}
+
+// openUpdateJournal opens a new journal for an update of the passed server.
+// The journal is locked until unlocked by the update, even if the update could not be rolled back.
+func (d DockerBasedManager) openUpdateJournal(ctx context.Context, server networkmodel.ServerModel, serverFolder string) (*updateJournal, error) {
+	diskConfig, err := d.FindDiskConfig(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find disk config: %w", err)
+	}
+
+	// The lock is held for the entire update, so the journal of the update is never replayed while it is in progress.
+	lock, locked, err := d.lockUpdateJournal(server.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, fmt.Errorf("journal of server %s is locked by another update: %w", server.UUID.String(), ErrUpdateJournalPending)
+	}
+
+	// A journal left behind by a previous update that could not be rolled back is replayed before updating again.
+	stagingFolder := d.computeUpdateJournalLocation(server)
+	if err := d.replayPendingUpdateJournal(ctx, server.UUID); err != nil {
+		utils.SwallowClose(lock)
+		return nil, err
+	}
+
+	if err := os.Mkdir(stagingFolder, 0o700); err != nil {
+		utils.SwallowClose(lock)
+		return nil, fmt.Errorf("failed to create journal staging folder %s: %w", stagingFolder, err)
+	}
+
+	journal := &updateJournal{
+		stagingFolder:  stagingFolder,
+		journaledPaths: make(map[string]struct{}),
+		lock:           lock,
+		state: updateJournalState{
+			ServerUUID:            server.UUID,
+			ServerFolder:          serverFolder,
+			Owner:                 diskConfig.FolderOwner,
+			DeploymentStateFolder: d.computeServerDeploymentStateLocation(server),
+		},
+	}
+
+	if err := journal.persist(); err != nil {
+		journal.unlock()
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// computeUpdateJournalLocation computes the staging folder of the update journal of the passed server.
+func (d DockerBasedManager) computeUpdateJournalLocation(server networkmodel.ServerModel) string {
+	return filepath.Join(d.UpdateJournalPath, server.UUID.String())
+}
+
+// loadUpdateJournal loads the journal persisted in the passed staging folder.
+// If the journal was never persisted, nil is returned as nothing was journaled.
+func loadUpdateJournal(stagingFolder string) (*updateJournal, error) {
+	content, err := os.ReadFile(filepath.Join(stagingFolder, journalStateFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("failed to read journal state: %w", err)
+	}
+
+	journal := &updateJournal{stagingFolder: stagingFolder, journaledPaths: make(map[string]struct{})}
+	if err := json.Unmarshal(content, &journal.state); err != nil {
+		return nil, fmt.Errorf("failed to parse journal state: %w", err)
+	}
+
+	for _, file := range journal.state.Files {
+		journal.journaledPaths[file.Path] = struct{}{}
+	}
+
+	return journal, nil
+}
+
+// persist atomically writes the state of the journal into its staging folder.
+func (j *updateJournal) persist() error {
+	content, err := json.Marshal(j.state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal state: %w", err)
+	}
+
+	statePath := filepath.Join(j.stagingFolder, journalStateFile)
+	temporaryPath := statePath + ".tmp"
+
+	stateFile, err := os.OpenFile(filepath.Clean(temporaryPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create journal state file: %w", err)
+	}
+
+	if _, err := stateFile.Write(content); err != nil {
+		_ = stateFile.Close()
+		return fmt.Errorf("failed to write journal state: %w", err)
+	}
+
+	if err := stateFile.Sync(); err != nil {
+		_ = stateFile.Close()
+		return fmt.Errorf("failed to sync journal state: %w", err)
+	}
+
+	if err := stateFile.Close(); err != nil {
+		return fmt.Errorf("failed to close journal state file: %w", err)
+	}
+
+	if err := os.Rename(temporaryPath, statePath); err != nil {
+		return fmt.Errorf("failed to move journal state into place: %w", err)
+	}
+
+	return syncDirectory(j.stagingFolder)
+}
+
+// stage stages the current state of the files at the passed paths relative to the server folder before they are
+// overwritten or created, persisting the journal once for all of them. Files already staged by the journal are not
+// staged again, as their state before the update was already recorded.
+// The files are read through the passed server folder as a root, so symbolic links on disk cannot stage files outside it.
+func (j *updateJournal) stage(serverFolder *os.Root, pathsInServerFolder ...string) error {
+	return j.stageFiles(serverFolder, pathsInServerFolder, copyFileWithMode)
+}
+
+// stageForDeletion stages the files at the passed paths relative to the server folder like stage before they are
+// deleted. As deleted files are not written to anymore, they are hard linked into the journal instead of being copied,
+// falling back to a copy if they cannot be linked.
+func (j *updateJournal) stageForDeletion(serverFolder *os.Root, pathsInServerFolder ...string) error {
+	return j.stageFiles(serverFolder, pathsInServerFolder, linkOrCopyFile)
+}
+
+// stageFiles stages the files at the passed paths with the passed function and persists the journal if any file was
+// newly staged.
+func (j *updateJournal) stageFiles(
+	serverFolder *os.Root,
+	pathsInServerFolder []string,
+	stageFile func(sourceRoot *os.Root, source string, target string, mode fs.FileMode) error,
+) error {
+	newlyStaged := false
+	for _, pathInServerFolder := range pathsInServerFolder {
+		staged, err := j.stageFile(serverFolder, pathInServerFolder, stageFile)
+		if err != nil {
+			return err
+		}
+
+		newlyStaged = newlyStaged || staged
+	}
+
+	if !newlyStaged {
+		return nil
+	}
+
+	// The files are only changed once the journal persisted them, allowing a rollback of the changes after a crash.
+	return j.persist()
+}
+
+// stageFile stages the current state of the file at the passed path relative to the server folder without persisting
+// the journal, reporting if the file was newly staged.
+func (j *updateJournal) stageFile(
+	serverFolder *os.Root,
+	pathInServerFolder string,
+	stageFile func(sourceRoot *os.Root, source string, target string, mode fs.FileMode) error,
+) (bool, error) {
+	cleanedPath := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(pathInServerFolder)), "/")
+	if !filepath.IsLocal(filepath.FromSlash(cleanedPath)) {
+		return false, fmt.Errorf("failed to stage %s: %w", pathInServerFolder, ErrUnsafeJournalPath)
+	}
+
+	if _, journaled := j.journaledPaths[cleanedPath]; journaled {
+		return false, nil
+	}
+
+	pathInRoot := filepath.FromSlash(cleanedPath)
+
+	info, err := serverFolder.Lstat(pathInRoot)
+	existed := err == nil
+
+	var symlinkTarget *string
+	switch {
+	case existed && info.Mode()&fs.ModeSymlink != 0:
+		target, err := serverFolder.Readlink(pathInRoot)
+		if err != nil {
+			return false, fmt.Errorf("failed to read symlink %s: %w", cleanedPath, err)
+		}
+
+		symlinkTarget = &target
+	case existed:
+		if err := stageFile(serverFolder, pathInRoot, j.stagedPathOf(cleanedPath), info.Mode().Perm()); err != nil {
+			return false, fmt.Errorf("failed to stage %s: %w", cleanedPath, err)
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		if err := j.recordCreatedDirectories(serverFolder, cleanedPath); err != nil {
+			return false, err
+		}
+	default:
+		return false, fmt.Errorf("failed to stat %s: %w", cleanedPath, err)
+	}
+
+	j.journaledPaths[cleanedPath] = struct{}{}
+	j.state.Files = append(j.state.Files, journaledFile{Path: cleanedPath, Existed: existed, SymlinkTarget: symlinkTarget})
+
+	return true, nil
+}
+
+// recordCreatedDirectories records all parent directories of the passed path that do not exist yet, so they can be
+// removed again on rollback.
+func (j *updateJournal) recordCreatedDirectories(serverFolder *os.Root, pathInServerFolder string) error {
+	for directory := path.Dir(pathInServerFolder); directory != "."; directory = path.Dir(directory) {
+		if slices.Contains(j.state.CreatedDirectories, directory) {
+			return nil
+		}
+
+		if _, err := serverFolder.Lstat(filepath.FromSlash(directory)); err == nil {
+			return nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to stat directory %s: %w", directory, err)
+		}
+
+		j.state.CreatedDirectories = append(j.state.CreatedDirectories, directory)
+	}
+
+	return nil
+}
+
+// recordIsState records the IS state of the passed artefact before it is updated on the controller.
+func (j *updateJournal) recordIsState(artefactIdentifier string, previousArtefact *uuid.UUID) error {
+	j.state.IsStates = append(j.state.IsStates, journaledIsState{ArtefactIdentifier: artefactIdentifier, PreviousArtefact: previousArtefact})
+
+	return j.persist()
+}
+
+// recordInstalledDeploymentState records that the state of the passed artefact is written into its pending state folder.
+func (j *updateJournal) recordInstalledDeploymentState(artefact uuid.UUID) error {
+	j.state.InstalledDeploymentStates = append(j.state.InstalledDeploymentStates, artefact)
+
+	return j.persist()
+}
+
+// recordUninstalledDeploymentState records that the state of the passed artefact is removed once the update is completed.
+// The journal is not persisted, as the state is only removed by a completed update, which persists the journal.
+func (j *updateJournal) recordUninstalledDeploymentState(artefact uuid.UUID) {
+	j.state.UninstalledDeploymentStates = append(j.state.UninstalledDeploymentStates, artefact)
+}
+
+// complete marks the journaled update as completed, moves the deployment states of the update into place and removes
+// the journal. A completed journal left behind by a crash is finished on replay instead of being rolled back.
+func (j *updateJournal) complete() error {
+	j.state.Completed = true
+	if err := j.persist(); err != nil {
+		return err
+	}
+
+	return j.finish()
+}
+
+// finish moves the deployment states of the completed update into place and removes the journal.
+// Deployment states that were already moved into place are skipped, so an interrupted finish can be repeated.
+func (j *updateJournal) finish() error {
+	for _, artefact := range j.state.UninstalledDeploymentStates {
+		if err := os.RemoveAll(j.deploymentStateFolderOf(artefact)); err != nil {
+			return fmt.Errorf("failed to remove deployment state of %s: %w", artefact, err)
+		}
+	}
+
+	for _, artefact := range j.state.InstalledDeploymentStates {
+		stateFolder := j.deploymentStateFolderOf(artefact)
+		pendingStateFolder := stateFolder + deploymentStatePendingSuffix
+		if _, err := os.Lstat(pendingStateFolder); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return fmt.Errorf("failed to stat pending deployment state of %s: %w", artefact, err)
+		}
+
+		if err := os.RemoveAll(stateFolder); err != nil {
+			return fmt.Errorf("failed to remove previous deployment state of %s: %w", artefact, err)
+		}
+
+		if err := os.Rename(pendingStateFolder, stateFolder); err != nil {
+			return fmt.Errorf("failed to move deployment state of %s into place: %w", artefact, err)
+		}
+	}
+
+	return j.close()
+}
+
+// deploymentStateFolderOf computes the state folder of the passed artefact deployed by the update.
+func (j *updateJournal) deploymentStateFolderOf(artefact uuid.UUID) string {
+	return filepath.Join(j.state.DeploymentStateFolder, artefact.String())
+}
+
+// rollback restores the on-disk state of all journaled files and the IS states of all journaled artefacts and discards
+// the deployment states written by the update.
+// Files are restored through the server folder as a root, so symbolic links on disk cannot redirect the restore.
+// The staging folder is only removed if the rollback succeeded, keeping the staged files for manual recovery otherwise.
+func (j *updateJournal) rollback(ctx context.Context, controllerClient controller.Client) error {
+	var rollbackErr error
+
+	serverFolder, err := os.OpenRoot(j.state.ServerFolder)
+	if err != nil {
+		return fmt.Errorf("failed to open server folder %s: %w", j.state.ServerFolder, err)
+	}
+
+	defer utils.SwallowClose(serverFolder)
+
+	for _, file := range slices.Backward(j.state.Files) {
+		pathInRoot := filepath.FromSlash(file.Path)
+		if !file.Existed {
+			if err := serverFolder.Remove(pathInRoot); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				rollbackErr = errors.Join(rollbackErr, fmt.Errorf("failed to delete created file %s: %w", file.Path, err))
+			}
+
+			continue
+		}
+
+		if file.SymlinkTarget != nil {
+			if err := j.restoreSymlink(serverFolder, file.Path, *file.SymlinkTarget); err != nil {
+				rollbackErr = errors.Join(rollbackErr, err)
+			}
+
+			continue
+		}
+
+		if err := j.restoreStagedFile(serverFolder, file.Path); err != nil {
+			rollbackErr = errors.Join(rollbackErr, err)
+		}
+	}
+
+	// Created directories are removed deepest first, directories that are not empty are kept.
+	createdDirectories := slices.Clone(j.state.CreatedDirectories)
+	slices.SortFunc(createdDirectories, func(a, b string) int { return strings.Count(b, "/") - strings.Count(a, "/") })
+	for _, directory := range createdDirectories {
+		_ = serverFolder.Remove(filepath.FromSlash(directory))
+	}
+
+	// The rollback has to complete even if the update request was cancelled.
+	ctx = context.WithoutCancel(ctx)
+	for _, isState := range slices.Backward(j.state.IsStates) {
+		if err := controllerClient.UpdateState(ctx, j.state.ServerUUID, networkmodel.IS, networkmodel.UpdateServerStateRequest{
+			ArtefactIdentifier: isState.ArtefactIdentifier,
+			ArtefactUUID:       isState.PreviousArtefact,
+		}); err != nil {
+			rollbackErr = errors.Join(rollbackErr, fmt.Errorf("failed to restore is state of %s: %w", isState.ArtefactIdentifier, err))
+		}
+	}
+
+	// The states of the artefacts deployed by the update were never moved into place and are discarded.
+	for _, artefact := range j.state.InstalledDeploymentStates {
+		if err := os.RemoveAll(j.deploymentStateFolderOf(artefact) + deploymentStatePendingSuffix); err != nil {
+			rollbackErr = errors.Join(rollbackErr, fmt.Errorf("failed to discard deployment state of %s: %w", artefact, err))
+		}
+	}
+
+	if rollbackErr != nil {
+		return rollbackErr
+	}
+
+	return j.close()
+}
+
+// restoreStagedFile restores the staged file at the passed path into the server folder.
+func (j *updateJournal) restoreStagedFile(serverFolder *os.Root, pathInServerFolder string) error {
+	stagedPath := j.stagedPathOf(pathInServerFolder)
+
+	info, err := os.Stat(stagedPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat staged file %s: %w", pathInServerFolder, err)
+	}
+
+	pathInRoot := filepath.FromSlash(pathInServerFolder)
+	if err := serverFolder.MkdirAll(filepath.Dir(pathInRoot), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", pathInServerFolder, err)
+	}
+
+	stagedFile, err := os.Open(filepath.Clean(stagedPath))
+	if err != nil {
+		return fmt.Errorf("failed to open staged file %s: %w", pathInServerFolder, err)
+	}
+
+	defer utils.SwallowClose(stagedFile)
+
+	// A symbolic link deployed by the update is replaced rather than written through.
+	if err := writeDeployedFile(serverFolder, pathInRoot, stagedFile, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", pathInServerFolder, err)
+	}
+
+	// The restored file is synced to disk before the staged file is removed with the journal.
+	restoredFile, err := serverFolder.Open(pathInRoot)
+	if err != nil {
+		return fmt.Errorf("failed to open restored file %s: %w", pathInServerFolder, err)
+	}
+
+	defer utils.SwallowClose(restoredFile)
+
+	if err := restoredFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync restored file %s: %w", pathInServerFolder, err)
+	}
+
+	return j.chownRestoredFile(serverFolder, pathInServerFolder)
+}
+
+// restoreSymlink restores the symbolic link at the passed path pointing to the passed target into the server folder.
+func (j *updateJournal) restoreSymlink(serverFolder *os.Root, pathInServerFolder string, target string) error {
+	pathInRoot := filepath.FromSlash(pathInServerFolder)
+	if err := serverFolder.MkdirAll(filepath.Dir(pathInRoot), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", pathInServerFolder, err)
+	}
+
+	if err := writeDeployedSymlink(serverFolder, pathInRoot, target); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", pathInServerFolder, err)
+	}
+
+	return j.chownRestoredFile(serverFolder, pathInServerFolder)
+}
+
+// chownRestoredFile chowns the restored file at the passed path and all its parents inside the server folder to the
+// owner of the journal, if the journal has an owner.
+func (j *updateJournal) chownRestoredFile(serverFolder *os.Root, pathInServerFolder string) error {
+	if j.state.Owner == nil {
+		return nil
+	}
+
+	for chownedPath := pathInServerFolder; chownedPath != "."; chownedPath = path.Dir(chownedPath) {
+		if err := serverFolder.Lchown(filepath.FromSlash(chownedPath), j.state.Owner.UID, j.state.Owner.GID); err != nil {
+			return fmt.Errorf("failed to chown %s: %w", chownedPath, err)
+		}
+	}
+
+	return nil
+}
+
+// close removes the staging folder of the journal once the update was completed or rolled back.
+func (j *updateJournal) close() error {
+	if err := os.RemoveAll(j.stagingFolder); err != nil {
+		return fmt.Errorf("failed to remove journal staging folder %s: %w", j.stagingFolder, err)
+	}
+
+	return nil
+}
+
+// unlock releases the lock the update holds on the journal.
+func (j *updateJournal) unlock() {
+	if j.lock != nil {
+		utils.SwallowClose(j.lock)
+	}
+}
+
+// stagedPathOf computes the path a file of the server folder is staged at.
+func (j *updateJournal) stagedPathOf(pathInServerFolder string) string {
+	return filepath.Join(j.stagingFolder, journalStagedFilesFolder, filepath.FromSlash(pathInServerFolder))
+}
+
+// copyFileWithMode copies the file at the source path in the source root to the target path, creating its parent
+// directories and setting the passed permissions on it. The copy is synced to disk before returning.
+func copyFileWithMode(sourceRoot *os.Root, source string, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", target, err)
+	}
+
+	sourceFile, err := sourceRoot.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", source, err)
+	}
+
+	defer utils.SwallowClose(sourceFile)
+
+	targetFile, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+
+	if _, err := io.Copy(targetFile, sourceFile); err != nil {
+		_ = targetFile.Close()
+		return fmt.Errorf("failed to copy %s to %s: %w", source, target, err)
+	}
+
+	if err := targetFile.Sync(); err != nil {
+		_ = targetFile.Close()
+		return fmt.Errorf("failed to sync %s: %w", target, err)
+	}
+
+	if err := targetFile.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", target, err)
+	}
+
+	if err := os.Chmod(target, mode); err != nil {
+		return fmt.Errorf("failed to set mode of %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// linkOrCopyFile hard links the file at the source path in the source root to the target path, creating its parent
+// directories. Files that cannot be linked, e.g. as the target is on another file system, are copied via
+// copyFileWithMode instead.
+func linkOrCopyFile(sourceRoot *os.Root, source string, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", target, err)
+	}
+
+	if err := linkFile(sourceRoot, source, target); err == nil {
+		return nil
+	}
+
+	return copyFileWithMode(sourceRoot, source, target, mode)
+}
+
+// syncDirectory syncs the passed directory, persisting renames of its entries.
+func syncDirectory(directory string) error {
+	directoryFile, err := os.Open(filepath.Clean(directory))
+	if err != nil {
+		return fmt.Errorf("failed to open directory %s: %w", directory, err)
+	}
+
+	defer utils.SwallowClose(directoryFile)
+
+	if err := directoryFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync directory %s: %w", directory, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"golang.org/x/sys/unix"
+)
+
+// linkFile hard links the file at the source path in the source root to the target path.
+// The file is opened through the root and linked via its descriptor, so symbolic links on disk cannot redirect the link
+// to a file outside the root.
+func linkFile(sourceRoot *os.Root, source string, target string) error {
+	sourceFile, err := sourceRoot.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", source, err)
+	}
+
+	defer utils.SwallowClose(sourceFile)
+
+	descriptorPath := "/proc/self/fd/" + strconv.FormatUint(uint64(sourceFile.Fd()), 10)
+	if err := unix.Linkat(unix.AT_FDCWD, descriptorPath, unix.AT_FDCWD, target, unix.AT_SYMLINK_FOLLOW); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", source, target, err)
+	}
+
+	return nil
+}
+
+// tryLockFile places an exclusive lock on the passed file without blocking, reporting false if another open file holds it.
+// The lock is released once the file is closed.
+func tryLockFile(file *os.File) (bool, error) {
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil { //nolint:gosec
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to lock %s: %w", file.Name(), err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package manager
+
+import (
+	"errors"
+	"os"
+)
+
+// linkFile is not supported outside of linux, files are copied instead.
+func linkFile(_ *os.Root, _ string, _ string) error {
+	return errors.ErrUnsupported
+}
+
+// tryLockFile is not supported outside of linux, the lock is always reported as acquired.
+func tryLockFile(_ *os.File) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,305 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PlanUpdateDeployments plans the update of the deployments of the server to their TARGET state without modifying the
+// server folder.
+func (d DockerBasedManager) PlanUpdateDeployments(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	requiresRestart bool,
+	failOnUnexpectedOldFilesOnDisk bool,
+) (plan networkmodel.UpdatePlan, err error) {
+	ctx, span := tracing.Start(ctx, "plan update deployments", serverAttributes(
+		serverModel,
+		attribute.Bool("marauder.update.requires_restart", requiresRestart),
+	)...)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = d.retrieveContainerInfo(ctx, serverModel)
+	var serverRunning bool
+	if err == nil {
+		serverRunning = true
+	} else if !utils.CheckDockerError(err, errdefs.IsNotFound) {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed to fetch container info for %s: %w", serverModel.UUID.String(), err)
+	}
+
+	return d.planUpdateDeployments(ctx, serverModel, requiresRestart, failOnUnexpectedOldFilesOnDisk, serverRunning)
+}
+
+// planUpdateDeployments plans the update of all deployments of the server without modifying the server folder.
+func (d DockerBasedManager) planUpdateDeployments(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	requiresRestart bool,
+	failOnUnexpectedOldFilesOnDisk bool,
+	serverRunning bool,
+) (networkmodel.UpdatePlan, error) {
+	// A pending journal refuses the update, any plan computed against the journaled server folder would be wrong.
+	if err := d.checkNoPendingUpdateJournal(ctx, serverModel); err != nil {
+		return networkmodel.UpdatePlan{}, err
+	}
+
+	plan := networkmodel.UpdatePlan{
+		ServerUUID:    serverModel.UUID,
+		ServerRunning: serverRunning,
+		Artefacts:     make([]networkmodel.ArtefactUpdatePlan, 0),
+	}
+
+	serverFolderLocation, err := d.computeServerFolderLocation(serverModel)
+	if err != nil {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed to compute server folder location: %w", err)
+	}
+
+	data, err := d.fetchTemplateData(ctx, serverModel)
+	if err != nil {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed to fetch template data: %w", err)
+	}
+
+	// All missmatches are fetched, so that the plan of an update without restart can list the artefacts it defers.
+	missmatches, err := d.ControllerClient.FetchMissmatchesFor(ctx, serverModel.UUID, true)
+	if err != nil {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed to fetch missmatches for %s: %w", serverModel.UUID, err)
+	}
+
+	appliedMissmatches := make([]networkmodel.ArtefactVersionMissmatch, 0, len(missmatches))
+	for _, update := range missmatches {
+		artefactPlan, locallyModified, err := d.planSingleDeployment(ctx, serverModel, update, requiresRestart, serverFolderLocation, data)
+		if err != nil {
+			return networkmodel.UpdatePlan{}, fmt.Errorf("failed to plan update of %s on %s: %w", update.ArtefactIdentifier, serverModel.UUID.String(), err)
+		}
+
+		plan.RequiresRestart = plan.RequiresRestart || update.RequiresRestart
+		plan.FailsOnLocalModifications = plan.FailsOnLocalModifications || (failOnUnexpectedOldFilesOnDisk && locallyModified)
+		plan.Artefacts = append(plan.Artefacts, artefactPlan)
+
+		if !artefactPlan.Deferred {
+			appliedMissmatches = append(appliedMissmatches, update)
+		}
+	}
+
+	plan.FileConflicts, err = d.findUpdateFileConflicts(ctx, serverModel, appliedMissmatches)
+	if err != nil {
+		return networkmodel.UpdatePlan{}, fmt.Errorf("failed to find file conflicts of %s: %w", serverModel.UUID, err)
+	}
+
+	return plan, nil
+}
+
+// planSingleDeployment plans the update of a single deployment on the server without modifying the server folder.
+// The returned boolean reports if files of the currently installed artefact were modified or are missing on disk.
+func (d DockerBasedManager) planSingleDeployment(
+	ctx context.Context,
+	serverModel networkmodel.ServerModel,
+	update networkmodel.ArtefactVersionMissmatch,
+	requiresRestart bool,
+	serverFolderLocation string,
+	data templateData,
+) (networkmodel.ArtefactUpdatePlan, bool, error) {
+	artefactToInstall := update.Missmatch.ArtefactToInstall()
+	artefactToUninstall := update.Missmatch.ArtefactToUninstall()
+
+	artefactPlan := networkmodel.ArtefactUpdatePlan{
+		ArtefactIdentifier: update.ArtefactIdentifier,
+		RequiresRestart:    update.RequiresRestart,
+		Deferred:           update.RequiresRestart && !requiresRestart,
+		Files:              make([]networkmodel.FileUpdatePlan, 0),
+	}
+	if artefactToUninstall != nil {
+		artefactPlan.FromVersion = &artefactToUninstall.Version
+	}
+	if artefactToInstall != nil {
+		artefactPlan.ToVersion = &artefactToInstall.Version
+	}
+
+	if artefactPlan.Deferred {
+		return artefactPlan, false, nil
+	}
+
+	var artefactToInstallManifest filemodel.Manifest
+	newFiles := make(map[string]bool)
+	if artefactToInstall != nil {
+		var err error
+		artefactToInstallManifest, err = d.ControllerClient.FetchManifest(ctx, artefactToInstall.Artefact)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, fmt.Errorf("failed to fetch target artefact manifest: %w", err)
+		}
+
+		for _, file := range manifestFilesInServerFolder(artefactToInstallManifest) {
+			newFiles[file] = true
+		}
+	}
+
+	oldFiles := make(map[string]bool)
+	keptFiles := make(map[string]bool)
+	driftOfOldFiles := make(map[string]networkmodel.FileDriftKind)
+	locallyModified := false
+	if artefactToUninstall != nil {
+		artefactToUninstallOnDisk, err := d.downloadArtefact(ctx, artefactToUninstall.Artefact)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, fmt.Errorf("failed to fetch old artefact to disk: %w", err)
+		}
+
+		defer func() { _ = os.Remove(artefactToUninstallOnDisk) }()
+
+		artefactToUninstallManifest, err := d.ControllerClient.FetchManifest(ctx, artefactToUninstall.Artefact)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, fmt.Errorf("failed to fetch old artefact manifest: %w", err)
+		}
+
+		artefactToUninstallState, err := d.readDeploymentState(serverModel, artefactToUninstall.Artefact, data)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, fmt.Errorf("failed to read state of old artefact: %w", err)
+		}
+
+		drift, err := compareDeploymentFilesOnDisk(
+			artefactToUninstallManifest,
+			artefactToUninstallOnDisk,
+			serverFolderLocation,
+			d.FileEqualityRegistry,
+			artefactToUninstallState,
+		)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, fmt.Errorf("failed to compare old artefact with server folder: %w", err)
+		}
+
+		for _, file := range drift {
+			driftOfOldFiles[file.Path] = file.Kind
+		}
+
+		for _, file := range manifestFilesInServerFolder(artefactToUninstallManifest) {
+			oldFiles[file] = true
+		}
+
+		keptFiles = oldFilesKeptOnDisk(artefactToUninstallManifest, artefactToInstallManifest, drift)
+		locallyModified = len(unexpectedDrift(artefactToUninstallManifest, drift)) > 0
+	}
+
+	for file := range oldFiles {
+		if newFiles[file] || keptFiles[file] {
+			continue
+		}
+
+		artefactPlan.Files = append(artefactPlan.Files, networkmodel.FileUpdatePlan{
+			Path:                   file,
+			Change:                 networkmodel.FileUpdateDelete,
+			LosesLocalModification: driftOfOldFiles[file] == networkmodel.FileDriftModified,
+		})
+	}
+
+	newFileReferences := artefactToInstallManifest.Files.MatchedFilesToReferenceMap()
+	for file := range newFiles {
+		newFileReference := newFileReferences[pkg.FileParentDirectoryInArtefact+file]
+		if newFileReference.Deployment != nil && newFileReference.Deployment.MergeProvider != nil &&
+			oldFiles[file] && driftOfOldFiles[file] != networkmodel.FileDriftMissing {
+			artefactPlan.Files = append(artefactPlan.Files, networkmodel.FileUpdatePlan{Path: file, Change: networkmodel.FileUpdateMerge})
+			continue
+		}
+
+		if installedOnlyIfAbsent(newFileReference.Deployment.DeploymentMode()) {
+			present, err := presentAfterDeletion(file, oldFiles[file], keptFiles[file], driftOfOldFiles[file], serverFolderLocation)
+			if err != nil {
+				return networkmodel.ArtefactUpdatePlan{}, false, err
+			}
+
+			if present {
+				continue
+			}
+		}
+
+		filePlan, err := planNewFile(file, oldFiles[file] && !keptFiles[file], driftOfOldFiles[file], serverFolderLocation)
+		if err != nil {
+			return networkmodel.ArtefactUpdatePlan{}, false, err
+		}
+
+		artefactPlan.Files = append(artefactPlan.Files, filePlan)
+	}
+
+	slices.SortFunc(artefactPlan.Files, func(a, b networkmodel.FileUpdatePlan) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return artefactPlan, locallyModified, nil
+}
+
+// presentAfterDeletion returns if the passed file remains in the server folder after the files of the artefact to
+// uninstall were deleted.
+func presentAfterDeletion(
+	file string,
+	trackedByOldArtefact bool,
+	keptOnDisk bool,
+	drift networkmodel.FileDriftKind,
+	serverFolderLocation string,
+) (bool, error) {
+	if trackedByOldArtefact {
+		return keptOnDisk && drift != networkmodel.FileDriftMissing, nil
+	}
+
+	if _, err := os.Lstat(utils.CleanPathAndJoin(serverFolderLocation, file)); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return false, fmt.Errorf("failed to stat file %s: %w", file, err)
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// planNewFile plans the change of a file of the artefact to install in the server folder.
+func planNewFile(
+	file string,
+	trackedByOldArtefact bool,
+	drift networkmodel.FileDriftKind,
+	serverFolderLocation string,
+) (networkmodel.FileUpdatePlan, error) {
+	if trackedByOldArtefact {
+		if drift == networkmodel.FileDriftMissing {
+			return networkmodel.FileUpdatePlan{Path: file, Change: networkmodel.FileUpdateCreate}, nil
+		}
+
+		return networkmodel.FileUpdatePlan{
+			Path:                   file,
+			Change:                 networkmodel.FileUpdateOverwrite,
+			LosesLocalModification: drift == networkmodel.FileDriftModified,
+		}, nil
+	}
+
+	if _, err := os.Lstat(utils.CleanPathAndJoin(serverFolderLocation, file)); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return networkmodel.FileUpdatePlan{}, fmt.Errorf("failed to stat file %s: %w", file, err)
+		}
+
+		return networkmodel.FileUpdatePlan{Path: file, Change: networkmodel.FileUpdateCreate}, nil
+	}
+
+	// The file exists on disk without being tracked by the installed artefact, it is a local file.
+	return networkmodel.FileUpdatePlan{Path: file, Change: networkmodel.FileUpdateOverwrite, LosesLocalModification: true}, nil
+}
+
+// manifestFilesInServerFolder yields back the paths of all files of the manifest relative to the server folder.
+func manifestFilesInServerFolder(manifest filemodel.Manifest) []string {
+	files := make([]string, 0)
+	for filePathWithPrefix := range manifest.Files.MatchedFilesToReferenceMap() {
+		filePathWithoutPrefix, _ := strings.CutPrefix(filePathWithPrefix, pkg.FileParentDirectoryInArtefact)
+		files = append(files, filePathWithoutPrefix)
+	}
+
+	return files
+}
@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/tracing"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RecoverUpdateJournals replays the journal of every server found in the update journal folder, rolling back or completing
+// the updates interrupted by a crash of the operator. All journals are replayed even if one fails.
+// Journals that fail to replay are retried once the server is next started, updated or checked for drift.
+func (d DockerBasedManager) RecoverUpdateJournals(ctx context.Context) error {
+	entries, err := os.ReadDir(d.UpdateJournalPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read update journal folder %s: %w", d.UpdateJournalPath, err)
+	}
+
+	var recoverErr error
+	for _, entry := range entries {
+		serverUUID, err := uuid.Parse(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue // Not a journal.
+		}
+
+		if err := d.replayUnlockedUpdateJournal(ctx, serverUUID); err != nil {
+			recoverErr = errors.Join(recoverErr, fmt.Errorf("failed to replay update journal of server %s: %w", entry.Name(), err))
+		}
+	}
+
+	return recoverErr
+}
+
+// replayUpdateJournal replays the journal in the passed staging folder.
+// Completed updates only miss the removal of their journal, all other updates are rolled back.
+func (d DockerBasedManager) replayUpdateJournal(ctx context.Context, stagingFolder string) (err error) {
+	ctx, span := tracing.Start(ctx, "replay update journal", attribute.String("marauder.journal", stagingFolder))
+	defer func() { tracing.End(span, err) }()
+
+	journal, err := loadUpdateJournal(stagingFolder)
+	if err != nil {
+		return err
+	}
+
+	if journal == nil {
+		// The update crashed before the journal was persisted, nothing was changed yet.
+		if err := os.RemoveAll(stagingFolder); err != nil {
+			return fmt.Errorf("failed to remove empty journal %s: %w", stagingFolder, err)
+		}
+
+		return nil
+	}
+
+	if journal.state.Completed {
+		logrus.Info("completing interrupted update of server ", journal.state.ServerUUID)
+		return journal.finish()
+	}
+
+	logrus.Warn(
+		"rolling back interrupted update of server ", journal.state.ServerUUID,
+		" (", len(journal.state.Files), " files, ", len(journal.state.IsStates), " is states)",
+	)
+
+	if err := journal.rollback(ctx, d.ControllerClient); err != nil {
+		return fmt.Errorf("failed to rollback interrupted update: %w", err)
+	}
+
+	return nil
+}
+
+// checkNoPendingUpdateJournal fails with ErrUpdateJournalPending if the passed server has the journal of an update in
+// progress. The journal of an update that could not be rolled back is replayed again instead, failing with
+// ErrUpdateJournalPending only if the replay fails again.
+func (d DockerBasedManager) checkNoPendingUpdateJournal(ctx context.Context, server networkmodel.ServerModel) error {
+	stagingFolder := d.computeUpdateJournalLocation(server)
+	if _, err := os.Lstat(stagingFolder); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to check for update journal %s: %w", stagingFolder, err)
+	}
+
+	return d.replayUnlockedUpdateJournal(ctx, server.UUID)
+}
+
+// replayUnlockedUpdateJournal replays the journal of the passed server unless an update in progress holds its lock.
+func (d DockerBasedManager) replayUnlockedUpdateJournal(ctx context.Context, serverUUID uuid.UUID) error {
+	lock, locked, err := d.lockUpdateJournal(serverUUID)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		return fmt.Errorf("journal of server %s is locked by an update in progress: %w", serverUUID.String(), ErrUpdateJournalPending)
+	}
+
+	defer utils.SwallowClose(lock)
+
+	return d.replayPendingUpdateJournal(ctx, serverUUID)
+}
+
+// replayPendingUpdateJournal replays the journal of the passed server if one exists.
+// The caller must hold the lock of the journal.
+func (d DockerBasedManager) replayPendingUpdateJournal(ctx context.Context, serverUUID uuid.UUID) error {
+	stagingFolder := filepath.Join(d.UpdateJournalPath, serverUUID.String())
+	if _, err := os.Lstat(stagingFolder); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to check for update journal %s: %w", stagingFolder, err)
+	}
+
+	if err := d.replayUpdateJournal(ctx, stagingFolder); err != nil {
+		return fmt.Errorf("failed to replay journal %s of server %s: %w: %w", stagingFolder, serverUUID.String(), ErrUpdateJournalPending, err)
+	}
+
+	return nil
+}
+
+// lockUpdateJournal locks the journal of the passed server for an update or replay without blocking.
+// The returned lock file is released by closing it. If another update holds the lock, false is returned.
+func (d DockerBasedManager) lockUpdateJournal(serverUUID uuid.UUID) (*os.File, bool, error) {
+	if err := os.MkdirAll(d.UpdateJournalPath, 0o700); err != nil {
+		return nil, false, fmt.Errorf("failed to create update journal folder %s: %w", d.UpdateJournalPath, err)
+	}
+
+	lockPath := filepath.Join(d.UpdateJournalPath, serverUUID.String()+journalLockFileSuffix)
+	lock, err := os.OpenFile(filepath.Clean(lockPath), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open journal lock %s: %w", lockPath, err)
+	}
+
+	locked, err := tryLockFile(lock)
+	if err != nil || !locked {
+		utils.SwallowClose(lock)
+		return nil, false, err
+	}
+
+	return lock, true, nil
+}
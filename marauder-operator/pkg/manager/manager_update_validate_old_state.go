@@ -1,9 +1,11 @@
 package manager
 
 import (
+	"archive/tar"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,67 +13,207 @@ import (
 	"github.com/knockturnmc/marauder/marauder-lib/pkg"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/fileeq"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/filemodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
 	"github.com/knockturnmc/marauder/marauder-lib/pkg/utils"
 )
 
 // ErrFileUnequal is yielded back if a file on disk does not match the file in the artefact that should be deployed.
 var ErrFileUnequal = errors.New("files do not match")
 
-// validateOldDeploymentFilesOnDisk validates an old deployment on the disk.
+// validateOldDeploymentFilesOnDisk validates an old deployment on the disk and yields back the drift of its files.
+// All files that drifted from the old artefact although their deployment mode does not expect modifications are
+// reported in the returned error. If the files could not be compared at all, no drift is yielded back.
 func (d DockerBasedManager) validateOldDeploymentFilesOnDisk(
 	oldArtefact filemodel.Manifest,
 	oldArtefactOnDisk string,
 	serverFolderLocation string,
 	fileEqualityRegistry fileeq.FileEqualityRegistry,
-) error {
-	tarballReader, err := utils.NewFriendlyTarballReaderFromPath(oldArtefactOnDisk)
+	state deploymentState,
+) ([]networkmodel.FileDrift, error) {
+	drift, err := compareDeploymentFilesOnDisk(oldArtefact, oldArtefactOnDisk, serverFolderLocation, fileEqualityRegistry, state)
 	if err != nil {
-		return fmt.Errorf("failed to open old artefact tarball: %w", err)
+		return nil, err
+	}
+
+	unexpected := unexpectedDrift(oldArtefact, drift)
+	driftErrors := make([]error, 0, len(unexpected))
+	for _, file := range unexpected {
+		switch file.Kind {
+		case networkmodel.FileDriftMissing:
+			driftErrors = append(driftErrors, fmt.Errorf("expected file %s is missing: %w", file.Path, fs.ErrNotExist))
+		case networkmodel.FileDriftModified:
+			driftErrors = append(driftErrors, fmt.Errorf("file %s [%s] did not match expected state: %w", file.Path, file.EqualityProvider, ErrFileUnequal))
+		}
+	}
+
+	return drift, errors.Join(driftErrors...)
+}
+
+// compareDeploymentFilesOnDisk compares every file of the passed artefact with its counterpart in the server folder and
+// yields back all files that drifted from the artefact.
+// Files deployed as templates are compared against their output rendered with the template data of the passed deployment
+// state. Files deployed with a merge provider are compared against their merge result in the deployment state if their
+// last update merged them.
+// Files deployed in the install-once mode are owned by the server and hence never drift.
+// Files whose permissions differ from the recorded ones, or symbolic links pointing elsewhere, drifted as well.
+// Files are read through the server folder as a root, so symbolic links on disk cannot redirect the comparison to files
+// outside of it. If the server folder does not exist, all files are missing.
+// Only the path, kind, equality provider and detail of the returned drift are set.
+func compareDeploymentFilesOnDisk(
+	artefact filemodel.Manifest,
+	artefactOnDisk string,
+	serverFolderLocation string,
+	fileEqualityRegistry fileeq.FileEqualityRegistry,
+	state deploymentState,
+) ([]networkmodel.FileDrift, error) {
+	tarballReader, err := utils.NewFriendlyTarballReaderFromPath(artefactOnDisk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artefact tarball: %w", err)
 	}
 
 	defer func() { _ = tarballReader.Close(true) }()
 
-	fileReferenceMap := oldArtefact.Files.MatchedFilesToReferenceMap()
+	serverFolder, err := os.OpenRoot(serverFolderLocation)
+	switch {
+	case err == nil:
+		defer utils.SwallowClose(serverFolder)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to open server folder %s: %w", serverFolderLocation, err)
+	}
+
+	drift := make([]networkmodel.FileDrift, 0)
+	renderer := newDeploymentRenderer(artefact, state.data)
 	for {
 		header, err := tarballReader.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				return nil
+				return drift, nil
 			}
 
-			return fmt.Errorf("failed to read next header from artefact: %w", err)
+			return nil, fmt.Errorf("failed to read next header from artefact: %w", err)
 		}
 
-		fileReference, found := fileReferenceMap[header.Name]
-		if !found {
+		fileReference, found := renderer.fileReferences[header.Name]
+		if !found || fileReference.Deployment.DeploymentMode() == filemodel.FileDeploymentInstallOnce {
 			continue
 		}
 
 		deployment := utils.OrElse(fileReference.Deployment, filemodel.FileDeployment{})
 		fileEqualityIdentifier := utils.OrElse(deployment.EqualityProvider, "hash")
-		fileEquality, found := fileEqualityRegistry[fileEqualityIdentifier]
-		if !found {
-			return fmt.Errorf("%s is an unknown file equality: %w", fileEqualityIdentifier, fileeq.ErrUnknownFileEquality)
+		fileEquality, err := fileEqualityRegistry.Lookup(fileEqualityIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup file equality of %s: %w", header.Name, err)
 		}
 
-		filePathInServerFolder, _ := strings.CutPrefix(header.Name, pkg.FileParentDirectoryInArtefact)
-		fullyQualifiedFilePath := utils.CleanPathAndJoin(serverFolderLocation, filePathInServerFolder)
+		filePathInServerFolder, err := utils.ArtefactPathInServerFolder(header.Name)
+		if err != nil {
+			return nil, fmt.Errorf("refusing to compare file %s: %w", header.Name, err)
+		}
+
+		filePathInRoot := filepath.FromSlash(filePathInServerFolder)
+		fileDrift := networkmodel.FileDrift{Path: filePathInServerFolder, EqualityProvider: fileEqualityIdentifier}
+
+		if serverFolder == nil {
+			fileDrift.Kind = networkmodel.FileDriftMissing
+			drift = append(drift, fileDrift)
+
+			continue
+		}
+
+		if header.Typeflag == tar.TypeSymlink {
+			detail, err := symlinkDrift(serverFolder, filePathInRoot, header.Linkname)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				fileDrift.Kind = networkmodel.FileDriftMissing
+				drift = append(drift, fileDrift)
+			case err != nil:
+				return nil, err
+			case detail != "":
+				fileDrift.Kind = networkmodel.FileDriftModified
+				fileDrift.Detail = detail
+				drift = append(drift, fileDrift)
+			}
+
+			continue
+		}
 
-		fileOnDisk, err := os.Open(filepath.Clean(fullyQualifiedFilePath))
+		fileOnDisk, err := serverFolder.Open(filePathInRoot)
 		if err != nil {
-			return fmt.Errorf("failed to open expected file %s: %w", fullyQualifiedFilePath, err)
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to open expected file %s: %w", filePathInServerFolder, err)
+			}
+
+			fileDrift.Kind = networkmodel.FileDriftMissing
+			drift = append(drift, fileDrift)
+
+			continue
+		}
+
+		modeDrift, err := renderer.modeDrift(header.Name, fileOnDisk)
+		if err != nil {
+			_ = fileOnDisk.Close()
+			return nil, err
 		}
 
-		equals, err := fileEquality.Equals(fileOnDisk, tarballReader)
+		expectedFile, err := expectedFileOnDisk(renderer, deployment, header.Name, tarballReader, state)
 		if err != nil {
 			_ = fileOnDisk.Close()
-			return fmt.Errorf("failed to compare file %s with expected state: %w", filePathInServerFolder, err)
+			return nil, err
 		}
 
+		equals, err := fileEquality.Equals(fileOnDisk, expectedFile)
 		_ = fileOnDisk.Close()
+		utils.SwallowClose(expectedFile)
+
+		// The details of all changes to the file are reported, so a changed mode does not hide changed content.
+		// Files whose content changed alone are reported without a detail.
+		details := make([]string, 0, 2)
+		switch {
+		case err != nil:
+			// A file that can no longer be compared, e.g. a config file that no longer parses, was modified on disk.
+			details = append(details, fmt.Sprintf("failed to compare file with expected state: %s", err))
+		case !equals && modeDrift != "":
+			details = append(details, "content changed")
+		}
+
+		if modeDrift != "" {
+			details = append(details, modeDrift)
+		}
 
-		if !equals {
-			return fmt.Errorf("file %s [%s] did not match expected state: %w", filePathInServerFolder, fileEqualityIdentifier, ErrFileUnequal)
+		if err != nil || !equals || modeDrift != "" {
+			fileDrift.Kind = networkmodel.FileDriftModified
+			fileDrift.Detail = strings.Join(details, ", ")
+			drift = append(drift, fileDrift)
 		}
 	}
 }
+
+// expectedFileOnDisk yields back the expected content of the passed file in the tarball on disk.
+// This is the merge result of the file if present, otherwise the rendered file in the artefact.
+func expectedFileOnDisk(
+	renderer deploymentRenderer,
+	deployment filemodel.FileDeployment,
+	pathInTarball string,
+	tarballReader io.Reader,
+	state deploymentState,
+) (io.ReadCloser, error) {
+	filePathInServerFolder, _ := strings.CutPrefix(pathInTarball, pkg.FileParentDirectoryInArtefact)
+
+	if deployment.MergeProvider != nil {
+		mergedFile, found, err := state.openMergedFile(filePathInServerFolder)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			return mergedFile, nil
+		}
+	}
+
+	expectedFile, err := renderer.render(pathInTarball, tarballReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render expected file %s: %w", filePathInServerFolder, err)
+	}
+
+	return io.NopCloser(expectedFile), nil
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	libMetrics "github.com/knockturnmc/marauder/marauder-lib/pkg/metrics"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/models/networkmodel"
+	"github.com/knockturnmc/marauder/marauder-lib/pkg/worker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// Namespace is the namespace all metrics of the operator are exposed under.
+const Namespace = "marauder_operator"
+
+// The Metrics struct holds all metrics exposed by the operator.
+// All observation methods are safe to call on a nil instance, in which case they are a NOOP.
+type Metrics struct {
+	*libMetrics.Registry
+
+	lifecycleActionDuration    *prometheus.HistogramVec
+	artefactUpdateDuration     *prometheus.HistogramVec
+	managementExchangeDuration *prometheus.HistogramVec
+	managementExchangeErrors   *prometheus.CounterVec
+}
+
+// NewMetrics creates and registers all metrics of the operator on a new registry.
+func NewMetrics() (*Metrics, error) {
+	registry, err := libMetrics.NewRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
+	}
+
+	metrics := &Metrics{
+		Registry: registry,
+		lifecycleActionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "lifecycle_action_duration_seconds",
+			Help:      "Duration of lifecycle actions executed on servers by action and outcome.",
+			Buckets:   []float64{0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"environment", "name", "action", "outcome"}),
+		artefactUpdateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "artefact_update_duration_seconds",
+			Help:      "Duration of updating a single artefact deployment on a server.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		}, []string{"environment", "name", "artefact", "outcome"}),
+		managementExchangeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "management_exchange_duration_seconds",
+			Help:      "Latency of message exchanges with servers over their management socket.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}, []string{"environment", "name", "message"}),
+		managementExchangeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "management_exchange_errors_total",
+			Help:      "Number of failed message exchanges with servers over their management socket.",
+		}, []string{"environment", "name", "message"}),
+	}
+
+	if err := metrics.Register(
+		metrics.lifecycleActionDuration,
+		metrics.artefactUpdateDuration,
+		metrics.managementExchangeDuration,
+		metrics.managementExchangeErrors,
+	); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// RegisterDownloadService registers the cache statistics of the passed download service.
+func (m *Metrics) RegisterDownloadService(downloadService worker.DownloadService) error {
+	return m.Register(newDownloadServiceCollector(downloadService))
+}
+
+// The downloadServiceCollector collects the statistics of a download service, computing them once per scrape.
+type downloadServiceCollector struct {
+	downloadService worker.DownloadService
+
+	cacheHits          *prometheus.Desc
+	coalescedDownloads *prometheus.Desc
+	cacheMisses        *prometheus.Desc
+	cacheSize          *prometheus.Desc
+}
+
+// newDownloadServiceCollector creates a new collector for the statistics of the passed download service.
+func newDownloadServiceCollector(downloadService worker.DownloadService) *downloadServiceCollector {
+	return &downloadServiceCollector{
+		downloadService: downloadService,
+		cacheHits: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "download", "cache_hits_total"),
+			"Number of artefact download requests served from the local download cache.",
+			nil, nil,
+		),
+		coalescedDownloads: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "download", "coalesced_total"),
+			"Number of artefact download requests coalesced into an already running download.",
+			nil, nil,
+		),
+		cacheMisses: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "download", "cache_misses_total"),
+			"Number of artefact download requests that required a new download.",
+			nil, nil,
+		),
+		cacheSize: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "download", "cache_size_bytes"),
+			"Total size of all files in the local download cache.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *downloadServiceCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.cacheHits
+	descs <- c.coalescedDownloads
+	descs <- c.cacheMisses
+	descs <- c.cacheSize
+}
+
+func (c *downloadServiceCollector) Collect(metricsChan chan<- prometheus.Metric) {
+	stats, err := c.downloadService.Stats()
+	if err != nil {
+		logrus.Warn("failed to compute download service stats ", err)
+	}
+
+	metricsChan <- prometheus.MustNewConstMetric(c.cacheHits, prometheus.CounterValue, float64(stats.CacheHits))
+	metricsChan <- prometheus.MustNewConstMetric(c.coalescedDownloads, prometheus.CounterValue, float64(stats.CoalescedDownloads))
+	metricsChan <- prometheus.MustNewConstMetric(c.cacheMisses, prometheus.CounterValue, float64(stats.CacheMisses))
+	metricsChan <- prometheus.MustNewConstMetric(c.cacheSize, prometheus.GaugeValue, float64(stats.CacheSizeBytes))
+}
+
+// ObserveLifecycleAction records the execution of a lifecycle action on a server.
+func (m *Metrics) ObserveLifecycleAction(
+	server networkmodel.ServerModel,
+	action networkmodel.LifecycleAction,
+	outcome string,
+	duration time.Duration,
+) {
+	if m == nil {
+		return
+	}
+
+	m.lifecycleActionDuration.WithLabelValues(server.Environment, server.Name, string(action), outcome).Observe(duration.Seconds())
+}
+
+// ObserveArtefactUpdate records the update of a single artefact deployment on a server.
+func (m *Metrics) ObserveArtefactUpdate(server networkmodel.ServerModel, artefact string, outcome string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.artefactUpdateDuration.WithLabelValues(server.Environment, server.Name, artefact, outcome).Observe(duration.Seconds())
+}
+
+// ObserveManagementExchange records a message exchange with a server over its management socket.
+func (m *Metrics) ObserveManagementExchange(server networkmodel.ServerModel, message string, err error, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.managementExchangeDuration.WithLabelValues(server.Environment, server.Name, message).Observe(duration.Seconds())
+	if err != nil {
+		m.managementExchangeErrors.WithLabelValues(server.Environment, server.Name, message).Inc()
+	}
+}
@@ -66,10 +66,11 @@ func (x ServerStatus) Number() protoreflect.EnumNumber {
 }
 
 type Message struct {
-	state              protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Payload *anypb.Any             `protobuf:"bytes,1,opt,name=payload"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Payload      *anypb.Any             `protobuf:"bytes,1,opt,name=payload"`
+	xxx_hidden_TraceContext map[string]string      `protobuf:"bytes,2,rep,name=trace_context,json=traceContext" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *Message) Reset() {
@@ -104,10 +105,21 @@ func (x *Message) GetPayload() *anypb.Any {
 	return nil
 }
 
+func (x *Message) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.xxx_hidden_TraceContext
+	}
+	return nil
+}
+
 func (x *Message) SetPayload(v *anypb.Any) {
 	x.xxx_hidden_Payload = v
 }
 
+func (x *Message) SetTraceContext(v map[string]string) {
+	x.xxx_hidden_TraceContext = v
+}
+
 func (x *Message) HasPayload() bool {
 	if x == nil {
 		return false
@@ -122,7 +134,8 @@ func (x *Message) ClearPayload() {
 type Message_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Payload *anypb.Any
+	Payload      *anypb.Any
+	TraceContext map[string]string
 }
 
 func (b0 Message_builder) Build() *Message {
@@ -130,6 +143,7 @@ func (b0 Message_builder) Build() *Message {
 	b, x := &b0, m0
 	_, _ = b, x
 	x.xxx_hidden_Payload = b.Payload
+	x.xxx_hidden_TraceContext = b.TraceContext
 	return m0
 }
 
@@ -638,7 +652,7 @@ type ServerStatusRequest_Response struct {
 
 func (x *ServerStatusRequest_Response) Reset() {
 	*x = ServerStatusRequest_Response{}
-	mi := &file_proto_servers_proto_msgTypes[7]
+	mi := &file_proto_servers_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -650,7 +664,7 @@ func (x *ServerStatusRequest_Response) String() string {
 func (*ServerStatusRequest_Response) ProtoMessage() {}
 
 func (x *ServerStatusRequest_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_servers_proto_msgTypes[7]
+	mi := &file_proto_servers_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -713,7 +727,7 @@ type ServerPlayerRequest_Response struct {
 
 func (x *ServerPlayerRequest_Response) Reset() {
 	*x = ServerPlayerRequest_Response{}
-	mi := &file_proto_servers_proto_msgTypes[8]
+	mi := &file_proto_servers_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -725,7 +739,7 @@ func (x *ServerPlayerRequest_Response) String() string {
 func (*ServerPlayerRequest_Response) ProtoMessage() {}
 
 func (x *ServerPlayerRequest_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_servers_proto_msgTypes[8]
+	mi := &file_proto_servers_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -771,7 +785,7 @@ type ServerShutdownRequest_Response struct {
 
 func (x *ServerShutdownRequest_Response) Reset() {
 	*x = ServerShutdownRequest_Response{}
-	mi := &file_proto_servers_proto_msgTypes[9]
+	mi := &file_proto_servers_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -783,7 +797,7 @@ func (x *ServerShutdownRequest_Response) String() string {
 func (*ServerShutdownRequest_Response) ProtoMessage() {}
 
 func (x *ServerShutdownRequest_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_servers_proto_msgTypes[9]
+	mi := &file_proto_servers_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -814,7 +828,7 @@ type ServerToggleSaveRequest_Response struct {
 
 func (x *ServerToggleSaveRequest_Response) Reset() {
 	*x = ServerToggleSaveRequest_Response{}
-	mi := &file_proto_servers_proto_msgTypes[10]
+	mi := &file_proto_servers_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -826,7 +840,7 @@ func (x *ServerToggleSaveRequest_Response) String() string {
 func (*ServerToggleSaveRequest_Response) ProtoMessage() {}
 
 func (x *ServerToggleSaveRequest_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_servers_proto_msgTypes[10]
+	mi := &file_proto_servers_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -857,7 +871,7 @@ type ServerArtefactUpgradeNotification_Response struct {
 
 func (x *ServerArtefactUpgradeNotification_Response) Reset() {
 	*x = ServerArtefactUpgradeNotification_Response{}
-	mi := &file_proto_servers_proto_msgTypes[11]
+	mi := &file_proto_servers_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -869,7 +883,7 @@ func (x *ServerArtefactUpgradeNotification_Response) String() string {
 func (*ServerArtefactUpgradeNotification_Response) ProtoMessage() {}
 
 func (x *ServerArtefactUpgradeNotification_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_servers_proto_msgTypes[11]
+	mi := &file_proto_servers_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -896,9 +910,13 @@ var File_proto_servers_proto protoreflect.FileDescriptor
 
 const file_proto_servers_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/servers.proto\x12\vknockturnmc\x1a\x19google/protobuf/any.proto\"9\n" +
+	"\x13proto/servers.proto\x12\vknockturnmc\x1a\x19google/protobuf/any.proto\"\xc7\x01\n" +
 	"\aMessage\x12.\n" +
-	"\apayload\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\apayload\"T\n" +
+	"\apayload\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\apayload\x12K\n" +
+	"\rtrace_context\x18\x02 \x03(\v2&.knockturnmc.Message.TraceContextEntryR\ftraceContext\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"T\n" +
 	"\x13ServerStatusRequest\x1a=\n" +
 	"\bResponse\x121\n" +
 	"\x06status\x18\x01 \x01(\x0e2\x19.knockturnmc.ServerStatusR\x06status\"P\n" +
@@ -931,7 +949,7 @@ const file_proto_servers_proto_rawDesc = "" +
 	"!com.knockturnmc.marauder.protobufZ\f./marauderpb\xa0\x01\x01b\beditionsp\xe9\a"
 
 var file_proto_servers_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_servers_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_servers_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_servers_proto_goTypes = []any{
 	(ServerStatus)(0),                                  // 0: knockturnmc.ServerStatus
 	(*Message)(nil),                                    // 1: knockturnmc.Message
@@ -941,22 +959,24 @@ var file_proto_servers_proto_goTypes = []any{
 	(*ServerToggleSaveRequest)(nil),                    // 5: knockturnmc.ServerToggleSaveRequest
 	(*ServerArtefactUpgradeNotification)(nil),          // 6: knockturnmc.ServerArtefactUpgradeNotification
 	(*Player)(nil),                                     // 7: knockturnmc.Player
-	(*ServerStatusRequest_Response)(nil),               // 8: knockturnmc.ServerStatusRequest.Response
-	(*ServerPlayerRequest_Response)(nil),               // 9: knockturnmc.ServerPlayerRequest.Response
-	(*ServerShutdownRequest_Response)(nil),             // 10: knockturnmc.ServerShutdownRequest.Response
-	(*ServerToggleSaveRequest_Response)(nil),           // 11: knockturnmc.ServerToggleSaveRequest.Response
-	(*ServerArtefactUpgradeNotification_Response)(nil), // 12: knockturnmc.ServerArtefactUpgradeNotification.Response
-	(*anypb.Any)(nil),                                  // 13: google.protobuf.Any
+	nil,                                                // 8: knockturnmc.Message.TraceContextEntry
+	(*ServerStatusRequest_Response)(nil),               // 9: knockturnmc.ServerStatusRequest.Response
+	(*ServerPlayerRequest_Response)(nil),               // 10: knockturnmc.ServerPlayerRequest.Response
+	(*ServerShutdownRequest_Response)(nil),             // 11: knockturnmc.ServerShutdownRequest.Response
+	(*ServerToggleSaveRequest_Response)(nil),           // 12: knockturnmc.ServerToggleSaveRequest.Response
+	(*ServerArtefactUpgradeNotification_Response)(nil), // 13: knockturnmc.ServerArtefactUpgradeNotification.Response
+	(*anypb.Any)(nil),                                  // 14: google.protobuf.Any
 }
 var file_proto_servers_proto_depIdxs = []int32{
-	13, // 0: knockturnmc.Message.payload:type_name -> google.protobuf.Any
-	0,  // 1: knockturnmc.ServerStatusRequest.Response.status:type_name -> knockturnmc.ServerStatus
-	7,  // 2: knockturnmc.ServerPlayerRequest.Response.players:type_name -> knockturnmc.Player
-	3,  // [3:3] is the sub-list for method output_type
-	3,  // [3:3] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	14, // 0: knockturnmc.Message.payload:type_name -> google.protobuf.Any
+	8,  // 1: knockturnmc.Message.trace_context:type_name -> knockturnmc.Message.TraceContextEntry
+	0,  // 2: knockturnmc.ServerStatusRequest.Response.status:type_name -> knockturnmc.ServerStatus
+	7,  // 3: knockturnmc.ServerPlayerRequest.Response.players:type_name -> knockturnmc.Player
+	4,  // [4:4] is the sub-list for method output_type
+	4,  // [4:4] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_servers_proto_init() }
@@ -970,7 +990,7 @@ func file_proto_servers_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_servers_proto_rawDesc), len(file_proto_servers_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   12,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
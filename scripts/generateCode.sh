@@ -16,4 +16,6 @@ go list -f '{{.Dir}}' -m | while read module; do
   popd >/dev/null
 done
 
+go run ./marauder-client validate schema > marauder-client/schema/manifest.schema.json
+
 protoc -I "marauder-proto/src/main" --go_out "marauder-proto/src/main/golang" "marauder-proto/src/main/proto/servers.proto"